@@ -0,0 +1,302 @@
+// Package cli runs mvn-tui tasks from the command line without the TUI, so
+// the same task definitions work in scripts, git hooks and CI.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/AR0106/mvn-tui/config"
+	"github.com/AR0106/mvn-tui/export"
+	"github.com/AR0106/mvn-tui/maven"
+	"github.com/AR0106/mvn-tui/ui"
+)
+
+// Exit codes for failures that happen before Maven runs
+const (
+	exitError = 1   // The project could not be loaded or Maven could not start
+	exitUsage = 2   // Unknown subcommand, task, module or flag
+	exitStop  = 130 // The build was cancelled with Ctrl+C, as a shell reports SIGINT
+)
+
+// errUsage marks errors caused by a bad command line
+var errUsage = errors.New("usage error")
+
+// IsCommand reports whether name is a headless subcommand
+func IsCommand(name string) bool {
+	switch name {
+	case "run", "tasks", "modules", "config", "help":
+		return true
+	}
+	return false
+}
+
+// Run executes a subcommand in the project containing dir and returns the
+// process exit code. For run, that is Maven's own exit code.
+func Run(args []string, dir string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stdout)
+		return 0
+	}
+	if !IsCommand(args[0]) {
+		fmt.Fprintf(stderr, "Unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	root, err := maven.FindProjectRoot(dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return exitError
+	}
+	project, err := maven.LoadProject(root)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error loading Maven project: %v\n", err)
+		return exitError
+	}
+	layers, err := config.LoadLayers(project)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error loading config: %v\n", err)
+		return exitError
+	}
+
+	code := 0
+	switch args[0] {
+	case "tasks":
+		err = listTasks(project, config.Merge(layers...), args[1:], stdout)
+	case "modules":
+		err = listModules(project, args[1:], stdout)
+	case "config":
+		err = dumpConfig(layers, args[1:], stdout)
+	default:
+		// Ctrl+C stops Maven's whole process group, as cancelling a job does
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		code, err = runTask(ctx, project, layers, args[1:], stdout, stderr)
+	}
+
+	if errors.Is(err, flag.ErrHelp) {
+		printUsage(stdout)
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		if errors.Is(err, errUsage) {
+			return exitUsage
+		}
+		return exitError
+	}
+	return code
+}
+
+// printUsage describes the subcommands
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, `Usage:
+  mvn-tui                      Start the TUI
+  mvn-tui run <task> [flags]   Run a task without the TUI
+  mvn-tui tasks                List the tasks available in this project
+  mvn-tui modules              List the modules of this project
+  mvn-tui config [flags]       Show the effective config and where each value came from
+  mvn-tui --version            Show version information
+
+Flags for run and config:
+  --module <name>     Build only this module (repeatable, or comma-separated; run only)
+  --profile <id>      Activate this profile (repeatable, or comma-separated)
+  --skip-tests        Skip tests (-DskipTests)
+  --headless          Show the defaults of run instead of the TUI's (config only)
+
+Settings are layered: built-in defaults < user config < project config <
+flags. The user config is $XDG_CONFIG_HOME/mvn-tui/config.yaml, or
+~/.config/mvn-tui/config.yaml, and the project config is .mvn-tui.yaml.
+
+Tasks are matched by name, ignoring case and punctuation, so "Clean Install"
+can be given as clean-install and "Run (Java)" as run-java.
+`)
+}
+
+// flagLayer registers the flags that override config on fs, and returns a
+// function giving the layer they form once fs is parsed
+func flagLayer(fs *flag.FlagSet) func() config.Layer {
+	var profiles listValue
+	fs.Var(&profiles, "profile", "")
+	skipTests := fs.Bool("skip-tests", false, "")
+
+	return func() config.Layer {
+		cfg := &config.Config{Defaults: config.Defaults{Profiles: profiles}}
+		if *skipTests {
+			cfg.Defaults.Options.SkipTests = skipTests
+		}
+		return config.Layer{Source: config.SourceFlags, Config: cfg}
+	}
+}
+
+// dumpConfig prints the merged config of the TUI, or of run if --headless is given
+func dumpConfig(layers []config.Layer, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	headless := fs.Bool("headless", false, "")
+	flags := flagLayer(fs)
+	if positional, err := parseFlags(fs, args); err != nil {
+		return err
+	} else if len(positional) > 0 {
+		return fmt.Errorf("%w: config takes no arguments", errUsage)
+	}
+
+	all := append([]config.Layer{config.BuiltIn(*headless)}, layers...)
+	return config.Dump(stdout, append(all, flags())...)
+}
+
+// listValue is a flag that may be repeated or given a comma-separated list
+type listValue []string
+
+func (l *listValue) String() string { return strings.Join(*l, ",") }
+
+func (l *listValue) Set(s string) error {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+// parseFlags parses flags appearing anywhere among args and returns the
+// remaining positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %w", errUsage, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// findTask returns the task whose name matches name once both are slugs,
+// so that "Run (Java)" and run-java compare equal
+func findTask(tasks []ui.Task, name string) (ui.Task, bool) {
+	key := export.Slug(name)
+	for _, task := range tasks {
+		if export.Slug(task.Name) == key {
+			return task, true
+		}
+	}
+	return ui.Task{}, false
+}
+
+// listTasks prints the tasks of the project with their goals
+func listTasks(project *maven.Project, cfg *config.Config, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("tasks", flag.ContinueOnError)
+	if positional, err := parseFlags(fs, args); err != nil {
+		return err
+	} else if len(positional) > 0 {
+		return fmt.Errorf("%w: tasks takes no arguments", errUsage)
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	for _, task := range ui.ConfiguredTasks(project, cfg) {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", export.Slug(task.Name), strings.Join(task.Goals, " "), task.Description)
+	}
+	return w.Flush()
+}
+
+// listModules prints the module names accepted by run --module
+func listModules(project *maven.Project, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("modules", flag.ContinueOnError)
+	if positional, err := parseFlags(fs, args); err != nil {
+		return err
+	} else if len(positional) > 0 {
+		return fmt.Errorf("%w: modules takes no arguments", errUsage)
+	}
+
+	for _, module := range project.Modules {
+		fmt.Fprintln(stdout, module.Name)
+	}
+	return nil
+}
+
+// runTask runs a task with plain output and returns Maven's exit code.
+// The config layers apply first, then the task's recipe, then the flags.
+func runTask(ctx context.Context, project *maven.Project, layers []config.Layer, args []string, stdout, stderr io.Writer) (int, error) {
+	var modules listValue
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.Var(&modules, "module", "")
+	flags := flagLayer(fs)
+
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	if len(positional) != 1 {
+		return 0, fmt.Errorf("%w: run takes exactly one task, see mvn-tui tasks", errUsage)
+	}
+
+	all := append(append([]config.Layer{config.BuiltIn(true)}, layers...), flags())
+	cfg := config.Merge(all...)
+	task, ok := findTask(ui.ConfiguredTasks(project, cfg), positional[0])
+	if !ok {
+		return 0, fmt.Errorf("%w: unknown task %q, see mvn-tui tasks", errUsage, positional[0])
+	}
+
+	var options maven.BuildOptions
+	if err := cfg.Apply(project, &options); err != nil {
+		return 0, err
+	}
+	if task.Recipe != nil {
+		project, options = task.Recipe.Apply(project, options)
+		// Flags still take precedence over the recipe
+		if err := flags().Config.Apply(project, &options); err != nil {
+			return 0, err
+		}
+	}
+	if len(modules) > 0 {
+		if err := project.SelectModules(modules); err != nil {
+			return 0, fmt.Errorf("%w: %v, see mvn-tui modules", errUsage, err)
+		}
+	}
+
+	// The options may pick other settings files, or set properties profiles depend on
+	if options.Settings != "" || options.GlobalSettings != "" {
+		project.LoadSettings(options)
+	}
+	project.EvaluateProfiles(maven.NewActivationContext(project, options))
+
+	cmd := maven.BuildCommand(project, task.Goals, options)
+	fmt.Fprintf(stderr, "Running: %s\n", cmd.String())
+
+	result, err := maven.Execute(ctx, cmd, project.RootPath, func(line maven.OutputLine) {
+		if line.Stream == maven.Stderr {
+			fmt.Fprintln(stderr, line.Text)
+		} else {
+			fmt.Fprintln(stdout, line.Text)
+		}
+	})
+	defer result.Log.Close()
+	if err != nil {
+		return 0, fmt.Errorf("unable to start Maven: %w", err)
+	}
+
+	switch {
+	case result.Stopped != maven.NotStopped:
+		fmt.Fprintf(stderr, "Build cancelled: %s\n", result.Stopped)
+		return exitStop, nil
+	case result.Error != nil:
+		return 0, result.Error
+	case result.ExitCode < 0:
+		return exitError, nil
+	}
+	return result.ExitCode, nil
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// writeProject creates a multi-module project whose Maven wrapper echoes its
+// arguments and exits with the code in $FAKE_MVN_EXIT
+func writeProject(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake Maven wrapper is a shell script")
+	}
+
+	// Keep the user's own config out of the tests
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	dir := t.TempDir()
+	pom := `<project>
+  <groupId>com.example</groupId>
+  <artifactId>shop</artifactId>
+  <version>1.0</version>
+  <packaging>pom</packaging>
+  <modules>
+    <module>shop-core</module>
+    <module>shop-web</module>
+  </modules>
+  <profiles>
+    <profile><id>ci</id></profile>
+  </profiles>
+</project>`
+	if err := os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(pom), 0644); err != nil {
+		t.Fatalf("Failed to write pom.xml: %v", err)
+	}
+	script := "#!/bin/sh\necho \"mvn $*\"\necho \"warning on stderr\" >&2\nexit \"${FAKE_MVN_EXIT:-0}\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "mvnw"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake Maven wrapper: %v", err)
+	}
+	return dir
+}
+
+func TestRun_RunsTaskWithMavenExitCode(t *testing.T) {
+	dir := writeProject(t)
+	t.Setenv("FAKE_MVN_EXIT", "3")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"run", "clean-install", "--module", "shop-core", "--skip-tests", "--profile=ci,release"}, filepath.Join(dir, "shop-core"), &stdout, &stderr)
+
+	if code != 3 {
+		t.Errorf("Expected Maven's exit code 3, got %d (stderr: %s)", code, stderr.String())
+	}
+	want := "mvn -P ci,release -pl shop-core -B -DskipTests clean install\n"
+	if stdout.String() != want {
+		t.Errorf("Expected stdout %q, got %q", want, stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "warning on stderr") {
+		t.Errorf("Expected Maven's stderr on stderr, got %q", stderr.String())
+	}
+}
+
+func TestRun_RunsRecipeFromConfig(t *testing.T) {
+	dir := writeProject(t)
+	config := `
+defaults:
+  options:
+    offline: true
+recipes:
+  - name: Web Release
+    goals: [deploy]
+    modules: [shop-web]
+    properties:
+      skipITs: "true"
+`
+	if err := os.WriteFile(filepath.Join(dir, ".mvn-tui.yaml"), []byte(config), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"run", "web-release", "--profile", "ci"}, dir, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the recipe to succeed, got %d (stderr: %s)", code, stderr.String())
+	}
+	want := "mvn -P ci -pl shop-web -B -o -DskipITs=true deploy\n"
+	if stdout.String() != want {
+		t.Errorf("Expected stdout %q, got %q", want, stdout.String())
+	}
+}
+
+func TestRun_FlagsOverrideUserAndProjectConfig(t *testing.T) {
+	dir := writeProject(t)
+	userConfig := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "mvn-tui", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(userConfig), 0755); err != nil {
+		t.Fatalf("Failed to create user config directory: %v", err)
+	}
+	user := `
+defaults:
+  options:
+    threads: 1C
+    skipTests: false
+recipes:
+  - name: Quick
+    goals: [install]
+    options:
+      offline: true
+`
+	if err := os.WriteFile(userConfig, []byte(user), 0644); err != nil {
+		t.Fatalf("Failed to write user config: %v", err)
+	}
+	project := "defaults:\n  options:\n    threads: \"4\"\n"
+	if err := os.WriteFile(filepath.Join(dir, ".mvn-tui.yaml"), []byte(project), 0644); err != nil {
+		t.Fatalf("Failed to write project config: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"run", "quick", "--skip-tests"}, dir, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected the user's recipe to succeed, got %d (stderr: %s)", code, stderr.String())
+	}
+	want := "mvn -B -DskipTests -o -T 4 install\n"
+	if stdout.String() != want {
+		t.Errorf("Expected stdout %q, got %q", want, stdout.String())
+	}
+
+	stdout.Reset()
+	if code := Run([]string{"config", "--skip-tests"}, dir, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected config to succeed, got %d (stderr: %s)", code, stderr.String())
+	}
+	for _, want := range []string{
+		"user config: " + userConfig,
+		"skipTests: true # command line",
+		`threads: "4" # project config`,
+		"name: Quick # user config",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Expected %q in config dump:\n%s", want, stdout.String())
+		}
+	}
+}
+
+func TestRun_UsageErrors(t *testing.T) {
+	dir := writeProject(t)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown command", []string{"deploy"}, `Unknown command "deploy"`},
+		{"unknown task", []string{"run", "deploy"}, `unknown task "deploy"`},
+		{"missing task", []string{"run"}, "run takes exactly one task"},
+		{"unknown module", []string{"run", "test", "--module", "shop-api"}, `unknown module "shop-api"`},
+		{"unknown flag", []string{"run", "test", "--fast"}, "flag provided but not defined"},
+		{"extra argument", []string{"tasks", "all"}, "tasks takes no arguments"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := Run(tt.args, dir, &stdout, &stderr); code != exitUsage {
+				t.Errorf("Expected exit code %d, got %d", exitUsage, code)
+			}
+			if !strings.Contains(stderr.String(), tt.want) {
+				t.Errorf("Expected stderr to contain %q, got %q", tt.want, stderr.String())
+			}
+			if strings.Contains(stdout.String(), "mvn ") {
+				t.Error("Expected Maven not to run")
+			}
+		})
+	}
+}
+
+func TestRun_ListsTasksAndModules(t *testing.T) {
+	dir := writeProject(t)
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"tasks"}, dir, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected tasks to succeed, got %d (stderr: %s)", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "clean-install") || !strings.Contains(stdout.String(), "clean install") {
+		t.Errorf("Expected task names and goals, got:\n%s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := Run([]string{"modules"}, dir, &stdout, &stderr); code != 0 {
+		t.Fatalf("Expected modules to succeed, got %d", code)
+	}
+	if stdout.String() != "shop-core\nshop-web\n" {
+		t.Errorf("Expected module names, got %q", stdout.String())
+	}
+}
+
+func TestRun_OutsideProject(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"tasks"}, t.TempDir(), &stdout, &stderr); code != exitError {
+		t.Errorf("Expected exit code %d outside a project, got %d", exitError, code)
+	}
+	if !strings.Contains(stderr.String(), "no pom.xml found") {
+		t.Errorf("Expected a missing pom.xml error, got %q", stderr.String())
+	}
+}
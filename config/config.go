@@ -0,0 +1,467 @@
+// Package config loads the optional per-project .mvn-tui.yaml file and the
+// user's own config file, which define named recipes, default build options,
+// the JDK, module visibility, UI preferences and how much history to keep.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AR0106/mvn-tui/history"
+	"github.com/AR0106/mvn-tui/maven"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the config file at the project root
+const FileName = ".mvn-tui.yaml"
+
+// Config is the content of a config file, or several merged by Merge
+type Config struct {
+	Path       string            `yaml:"-"` // File the config was loaded from; empty if there is none
+	Defaults   Defaults          `yaml:"defaults,omitempty"`
+	UI         UI                `yaml:"ui,omitempty"`
+	Modules    Modules           `yaml:"modules,omitempty"`
+	Properties map[string]string `yaml:"properties,omitempty"` // Extra -D properties for every command
+	Recipes    []Recipe          `yaml:"recipes,omitempty"`
+	History    History           `yaml:"history,omitempty"`
+
+	origins map[string]Source // Layer each merged setting came from, by key
+}
+
+// Defaults are the options, profiles and JDK the TUI starts with
+type Defaults struct {
+	JDK      string   `yaml:"jdk,omitempty"` // Major version, such as 21, or the path of a JDK
+	Options  Options  `yaml:"options,omitempty"`
+	Profiles []string `yaml:"profiles,omitempty"`
+}
+
+// UI holds preferences for the TUI itself
+type UI struct {
+	ReactorPanel *bool `yaml:"reactorPanel,omitempty"` // Show the reactor panel beside the log
+	LogLines     int   `yaml:"logLines,omitempty"`     // Log lines kept in memory before spilling to disk
+}
+
+// History controls how much command history is kept per project
+type History struct {
+	Entries    int `yaml:"entries,omitempty"`    // Newest entries kept; 200 if unset
+	MaxAgeDays int `yaml:"maxAgeDays,omitempty"` // Entries older than this are dropped; no limit if unset
+}
+
+// Retention returns the limits of the history store
+func (h History) Retention() history.Retention {
+	return history.Retention{
+		Entries: h.Entries,
+		MaxAge:  time.Duration(h.MaxAgeDays) * 24 * time.Hour,
+	}
+}
+
+// Modules controls how modules appear at startup
+type Modules struct {
+	Hidden     []string `yaml:"hidden,omitempty"`     // Left out of the modules pane
+	Deselected []string `yaml:"deselected,omitempty"` // Listed, but not built until selected
+}
+
+// Options overrides build options. Unset fields keep their current value.
+type Options struct {
+	SkipTests          *bool  `yaml:"skipTests,omitempty"`
+	SkipTestCompile    *bool  `yaml:"skipTestCompile,omitempty"` // -Dmaven.test.skip=true
+	Offline            *bool  `yaml:"offline,omitempty"`
+	UpdateSnapshots    *bool  `yaml:"updateSnapshots,omitempty"`
+	Threads            string `yaml:"threads,omitempty"`
+	AlsoMake           *bool  `yaml:"alsoMake,omitempty"`
+	AlsoMakeDependents *bool  `yaml:"alsoMakeDependents,omitempty"`
+	FailStrategy       string `yaml:"failStrategy,omitempty"` // fail-fast, fail-at-end or fail-never
+	NonRecursive       *bool  `yaml:"nonRecursive,omitempty"`
+	Settings           string `yaml:"settings,omitempty"`
+	GlobalSettings     string `yaml:"globalSettings,omitempty"`
+	NoTransferProgress *bool  `yaml:"noTransferProgress,omitempty"`
+	Debug              *bool  `yaml:"debug,omitempty"`
+	Verbose            *bool  `yaml:"verbose,omitempty"`
+	Quiet              *bool  `yaml:"quiet,omitempty"`
+	Errors             *bool  `yaml:"errors,omitempty"`
+	BatchMode          *bool  `yaml:"batchMode,omitempty"`
+	ShowVersion        *bool  `yaml:"showVersion,omitempty"`
+}
+
+// Recipe is a named combination of goals, profiles, modules and options
+type Recipe struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description,omitempty"`
+	Goals       []string          `yaml:"goals"`
+	Profiles    []string          `yaml:"profiles,omitempty"` // Enabled on top of the current profiles
+	Modules     []string          `yaml:"modules,omitempty"`  // Built instead of the current selection
+	Options     Options           `yaml:"options,omitempty"`
+	Properties  map[string]string `yaml:"properties,omitempty"`
+}
+
+// ValidationError lists every problem found in a config file
+type ValidationError struct {
+	Path     string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s:\n  - %s", e.Path, strings.Join(e.Problems, "\n  - "))
+}
+
+var (
+	// jdkVersionPattern matches a JDK given by its major version
+	jdkVersionPattern = regexp.MustCompile(`^\d+$`)
+
+	// unknownFieldPattern matches the decoder's error for a key with no field
+	unknownFieldPattern = regexp.MustCompile(`^(line \d+: )field (\S+) not found in type \S+$`)
+)
+
+// Load reads the config file at the project root. A project without one
+// gets an empty config. Unknown keys and bad values are reported together
+// in a *ValidationError.
+func Load(project *maven.Project) (*Config, error) {
+	return loadFile(filepath.Join(project.RootPath, FileName), project)
+}
+
+// LoadUser reads the user's config file, see UserPath. It is validated like a
+// project's, except that it can't name modules.
+func LoadUser() (*Config, error) {
+	path, err := UserPath()
+	if err != nil {
+		return &Config{}, nil
+	}
+	return loadFile(path, nil)
+}
+
+// UserPath returns the path of the user's config file,
+// $XDG_CONFIG_HOME/mvn-tui/config.yaml, where XDG_CONFIG_HOME defaults to ~/.config
+func UserPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "mvn-tui", "config.yaml"), nil
+}
+
+// loadFile reads and validates a config file. Module names are checked
+// against project, or rejected if project is nil.
+func loadFile(path string, project *maven.Project) (*Config, error) {
+	name := path
+	if project != nil {
+		name = FileName
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	config := &Config{Path: path}
+	var problems []string
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(config)
+
+	var typeErr *yaml.TypeError
+	switch {
+	case errors.As(err, &typeErr):
+		// Keys and values the decoder could not place; the rest was decoded
+		for _, msg := range typeErr.Errors {
+			if m := unknownFieldPattern.FindStringSubmatch(msg); m != nil {
+				msg = fmt.Sprintf("%sunknown key %q", m[1], m[2])
+			}
+			problems = append(problems, msg)
+		}
+	case err != nil && err != io.EOF:
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+
+	problems = append(problems, config.validate(project)...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Path: name, Problems: problems}
+	}
+	return config, nil
+}
+
+// validate checks the values the decoder accepted
+func (c *Config) validate(project *maven.Project) []string {
+	var problems []string
+
+	if jdk := c.Defaults.JDK; jdk != "" && !jdkVersionPattern.MatchString(jdk) && !filepath.IsAbs(jdk) && !strings.HasPrefix(jdk, "~/") {
+		problems = append(problems, fmt.Sprintf("defaults.jdk: %q is neither a major version such as 21 nor an absolute path", jdk))
+	}
+	if c.UI.LogLines < 0 {
+		problems = append(problems, "ui.logLines: must not be negative")
+	}
+	if c.History.Entries < 0 {
+		problems = append(problems, "history.entries: must not be negative")
+	}
+	if c.History.MaxAgeDays < 0 {
+		problems = append(problems, "history.maxAgeDays: must not be negative")
+	}
+	problems = append(problems, c.Defaults.Options.validate("defaults.options")...)
+	problems = append(problems, validateProfiles("defaults.profiles", c.Defaults.Profiles)...)
+	problems = append(problems, validateModules(project, "modules.hidden", c.Modules.Hidden)...)
+	problems = append(problems, validateModules(project, "modules.deselected", c.Modules.Deselected)...)
+	problems = append(problems, validateProperties("properties", c.Properties)...)
+
+	names := make(map[string]bool)
+	for i, recipe := range c.Recipes {
+		where := fmt.Sprintf("recipes[%d]", i)
+		name := strings.TrimSpace(recipe.Name)
+		switch {
+		case name == "":
+			problems = append(problems, where+": name is required")
+		case names[strings.ToLower(name)]:
+			problems = append(problems, fmt.Sprintf("%s: duplicate recipe name %q", where, name))
+		default:
+			names[strings.ToLower(name)] = true
+			where = fmt.Sprintf("%s (%s)", where, name)
+		}
+
+		if len(recipe.Goals) == 0 {
+			problems = append(problems, where+": goals must not be empty")
+		}
+		for _, goal := range recipe.Goals {
+			if strings.TrimSpace(goal) == "" {
+				problems = append(problems, where+": goals must not contain empty entries")
+				break
+			}
+		}
+		problems = append(problems, recipe.Options.validate(where+".options")...)
+		problems = append(problems, validateProfiles(where+".profiles", recipe.Profiles)...)
+		problems = append(problems, validateModules(project, where+".modules", recipe.Modules)...)
+		problems = append(problems, validateProperties(where+".properties", recipe.Properties)...)
+	}
+
+	return problems
+}
+
+// validate checks the thread count, the fail strategy and that output modes
+// don't conflict
+func (o Options) validate(where string) []string {
+	var problems []string
+	if o.Threads != "" && !maven.ValidThreads(o.Threads) {
+		problems = append(problems, fmt.Sprintf("%s.threads: %q is not a thread count such as 4 or 1C", where, o.Threads))
+	}
+	if o.FailStrategy != "" && maven.FailStrategy(o.FailStrategy).Flag() == "" {
+		problems = append(problems, fmt.Sprintf("%s.failStrategy: %q is not fail-fast, fail-at-end or fail-never", where, o.FailStrategy))
+	}
+
+	var modes []string
+	for _, mode := range []struct {
+		name  string
+		value *bool
+	}{{"debug", o.Debug}, {"verbose", o.Verbose}, {"quiet", o.Quiet}} {
+		if mode.value != nil && *mode.value {
+			modes = append(modes, mode.name)
+		}
+	}
+	if len(modes) > 1 {
+		problems = append(problems, fmt.Sprintf("%s: %s can't be combined", where, strings.Join(modes, " and ")))
+	}
+	return problems
+}
+
+// validateProfiles checks that profile IDs are usable in -P
+func validateProfiles(where string, ids []string) []string {
+	var problems []string
+	for _, id := range ids {
+		if id == "" || strings.ContainsAny(id, ", \t") || strings.HasPrefix(id, "!") {
+			problems = append(problems, fmt.Sprintf("%s: %q is not a profile ID", where, id))
+		}
+	}
+	return problems
+}
+
+// validateModules checks that every name is a module of the project. Only a
+// project's own config file can name modules.
+func validateModules(project *maven.Project, where string, names []string) []string {
+	if project == nil {
+		if len(names) > 0 {
+			return []string{where + ": modules can only be named in a project's " + FileName}
+		}
+		return nil
+	}
+
+	var problems []string
+	for _, name := range names {
+		found := false
+		for _, module := range project.Modules {
+			if module.Name == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s: %q is not a module of this project", where, name))
+		}
+	}
+	return problems
+}
+
+// validateProperties checks that property names are usable in -D
+func validateProperties(where string, properties map[string]string) []string {
+	var problems []string
+	for _, key := range sortedKeys(properties) {
+		if key == "" || strings.ContainsAny(key, "= \t") {
+			problems = append(problems, fmt.Sprintf("%s: %q is not a property name", where, key))
+		}
+	}
+	return problems
+}
+
+// Apply merges the defaults into the project's module and profile state and
+// into options. It fails if the JDK can't be found.
+func (c *Config) Apply(project *maven.Project, options *maven.BuildOptions) error {
+	for i := range project.Modules {
+		for _, name := range c.Modules.Hidden {
+			if project.Modules[i].Name == name {
+				project.Modules[i].Hidden = true
+			}
+		}
+		for _, name := range c.Modules.Deselected {
+			if project.Modules[i].Name == name {
+				project.Modules[i].Selected = false
+			}
+		}
+	}
+
+	for _, id := range c.Defaults.Profiles {
+		project.EnableProfile(id)
+	}
+	c.Defaults.Options.apply(options)
+	options.Properties = mergeProperties(options.Properties, c.Properties)
+
+	if c.Defaults.JDK != "" {
+		home, err := resolveJDK(c.Defaults.JDK)
+		if err != nil {
+			return err
+		}
+		options.JavaHome = home
+	}
+	return nil
+}
+
+// detectJavaVersions finds installed JDKs; tests replace it
+var detectJavaVersions = maven.DetectJavaVersions
+
+// resolveJDK returns the home directory of a JDK given by major version or path
+func resolveJDK(jdk string) (string, error) {
+	if !jdkVersionPattern.MatchString(jdk) {
+		home := jdk
+		if rest, ok := strings.CutPrefix(jdk, "~/"); ok {
+			dir, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("unable to expand JDK path %s: %w", jdk, err)
+			}
+			home = filepath.Join(dir, rest)
+		}
+		if info, err := os.Stat(home); err != nil || !info.IsDir() {
+			return "", fmt.Errorf("JDK %s is not a directory", jdk)
+		}
+		return home, nil
+	}
+
+	for _, version := range detectJavaVersions() {
+		if version.Version == jdk && version.Path != "" {
+			return version.Path, nil
+		}
+	}
+	return "", fmt.Errorf("JDK %s is not installed", jdk)
+}
+
+// Apply returns copies of project and options with the recipe's modules,
+// profiles, options and properties applied, leaving the originals as they are
+func (r Recipe) Apply(project *maven.Project, options maven.BuildOptions) (*maven.Project, maven.BuildOptions) {
+	project = project.Clone()
+	if len(r.Modules) > 0 {
+		// Modules were checked by Load, so this can't fail
+		_ = project.SelectModules(r.Modules)
+	}
+	for _, id := range r.Profiles {
+		project.EnableProfile(id)
+	}
+	r.Options.apply(&options)
+	options.Properties = mergeProperties(options.Properties, r.Properties)
+	return project, options
+}
+
+// apply overrides the fields of options that are set in o. Turning on one of
+// debug, verbose or quiet turns the others off.
+func (o Options) apply(options *maven.BuildOptions) {
+	set := func(dst *bool, src *bool) {
+		if src != nil {
+			*dst = *src
+		}
+	}
+
+	for _, mode := range []*bool{o.Debug, o.Verbose, o.Quiet} {
+		if mode != nil && *mode {
+			options.Debug, options.Verbose, options.Quiet = false, false, false
+		}
+	}
+
+	set(&options.SkipTests, o.SkipTests)
+	set(&options.SkipTestCompile, o.SkipTestCompile)
+	set(&options.Offline, o.Offline)
+	set(&options.UpdateSnapshots, o.UpdateSnapshots)
+	set(&options.AlsoMake, o.AlsoMake)
+	set(&options.AlsoMakeDependents, o.AlsoMakeDependents)
+	set(&options.NonRecursive, o.NonRecursive)
+	set(&options.NoTransferProgress, o.NoTransferProgress)
+	set(&options.Debug, o.Debug)
+	set(&options.Verbose, o.Verbose)
+	set(&options.Quiet, o.Quiet)
+	set(&options.Errors, o.Errors)
+	set(&options.BatchMode, o.BatchMode)
+	set(&options.ShowVersion, o.ShowVersion)
+	if o.Threads != "" {
+		options.Threads = o.Threads
+	}
+	if o.FailStrategy != "" {
+		options.FailStrategy = maven.FailStrategy(o.FailStrategy)
+	}
+	if o.Settings != "" {
+		options.Settings = o.Settings
+	}
+	if o.GlobalSettings != "" {
+		options.GlobalSettings = o.GlobalSettings
+	}
+}
+
+// mergeProperties returns base with overrides applied, without modifying
+// base. An empty value in the config passes the name alone, as -Dname.
+func mergeProperties(base []maven.Property, overrides map[string]string) []maven.Property {
+	if len(overrides) == 0 {
+		return base
+	}
+	properties := make([]maven.Property, 0, len(overrides))
+	for _, name := range sortedKeys(overrides) {
+		value := overrides[name]
+		properties = append(properties, maven.Property{Name: name, Value: value, HasValue: value != "", Enabled: true})
+	}
+	return maven.MergeProperties(base, properties...)
+}
+
+// sortedKeys returns the keys of m in order, for stable messages
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
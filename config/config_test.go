@@ -0,0 +1,196 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AR0106/mvn-tui/maven"
+)
+
+// testProject returns a project in a temporary directory holding content as its config file
+func testProject(t *testing.T, content string) *maven.Project {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return &maven.Project{
+		RootPath:   dir,
+		Executable: "mvn",
+		Modules: []maven.Module{
+			{Name: "shop-core", Selected: true},
+			{Name: "shop-web", Selected: true},
+			{Name: "shop-docs", Selected: true},
+		},
+		Profiles: []maven.Profile{{ID: "dev"}, {ID: "ci"}},
+	}
+}
+
+func TestLoad_MissingFileGivesEmptyConfig(t *testing.T) {
+	project := &maven.Project{RootPath: t.TempDir()}
+
+	cfg, err := Load(project)
+	if err != nil {
+		t.Fatalf("Expected no error without a config file, got %v", err)
+	}
+	if cfg.Path != "" || len(cfg.Recipes) != 0 {
+		t.Errorf("Expected an empty config, got %+v", cfg)
+	}
+}
+
+func TestLoad_AppliesDefaults(t *testing.T) {
+	project := testProject(t, `
+defaults:
+  profiles: [dev, from-settings]
+  options:
+    skipTests: true
+    debug: true
+    threads: 1C
+    alsoMake: true
+    failStrategy: fail-at-end
+    noTransferProgress: true
+modules:
+  hidden: [shop-docs]
+  deselected: [shop-web]
+properties:
+  maven.javadoc.skip: "true"
+recipes:
+  - name: CI build
+    goals: [clean, verify]
+`)
+
+	cfg, err := Load(project)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	options := maven.BuildOptions{Quiet: true}
+	if err := cfg.Apply(project, &options); err != nil {
+		t.Fatalf("Failed to apply config: %v", err)
+	}
+
+	if !options.SkipTests || !options.Debug || options.Quiet || options.Threads != "1C" {
+		t.Errorf("Expected default options applied with debug replacing quiet, got %+v", options)
+	}
+	if !reflect.DeepEqual(project.GetEnabledProfiles(), []string{"dev", "from-settings"}) {
+		t.Errorf("Expected default profiles enabled, got %v", project.GetEnabledProfiles())
+	}
+	if !project.Modules[2].Hidden || !project.Modules[2].Selected {
+		t.Error("Expected shop-docs to be hidden but still built")
+	}
+	if project.Modules[1].Selected {
+		t.Error("Expected shop-web to be deselected")
+	}
+
+	cmd := maven.BuildCommand(project, []string{"install"}, options)
+	want := "-P dev,from-settings -pl shop-core,shop-docs -am -X -ntp -fae -DskipTests -T 1C -Dmaven.javadoc.skip=true install"
+	if cmd.PrettyArgs != want {
+		t.Errorf("Expected args %q, got %q", want, cmd.PrettyArgs)
+	}
+}
+
+func TestRecipe_ApplyLeavesOriginalsUnchanged(t *testing.T) {
+	project := testProject(t, `
+properties:
+  env: local
+recipes:
+  - name: Web only
+    goals: [package]
+    profiles: [ci]
+    modules: [shop-web]
+    options:
+      offline: true
+    properties:
+      env: ci
+      skipITs: ""
+`)
+
+	cfg, err := Load(project)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	options := maven.BuildOptions{}
+	if err := cfg.Apply(project, &options); err != nil {
+		t.Fatalf("Failed to apply config: %v", err)
+	}
+
+	recipeProject, recipeOptions := cfg.Recipes[0].Apply(project, options)
+	cmd := maven.BuildCommand(recipeProject, cfg.Recipes[0].Goals, recipeOptions)
+	want := "-P ci -pl shop-web -o -Denv=ci -DskipITs package"
+	if cmd.PrettyArgs != want {
+		t.Errorf("Expected args %q, got %q", want, cmd.PrettyArgs)
+	}
+
+	cmd = maven.BuildCommand(project, []string{"package"}, options)
+	if cmd.PrettyArgs != "-Denv=local package" {
+		t.Errorf("Expected the recipe not to change the project or options, got %q", cmd.PrettyArgs)
+	}
+}
+
+func TestLoad_ReportsEveryProblem(t *testing.T) {
+	project := testProject(t, `
+defaults:
+  options:
+    skiptests: true
+    quiet: true
+    verbose: true
+    threads: lots
+    failStrategy: sometimes
+modules:
+  hidden: [shop-api]
+properties:
+  "bad key": x
+recipes:
+  - name: Build
+    goals: []
+    profile: [ci]
+  - name: build
+    goals: [install]
+    options:
+      offline: maybe
+history:
+  maxAgeDays: -30
+`)
+
+	_, err := Load(project)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+
+	wantProblems := []string{
+		`line 4: unknown key "skiptests"`,
+		`line 16: unknown key "profile"`,
+		"cannot unmarshal !!str `maybe` into bool",
+		`defaults.options.threads: "lots" is not a thread count`,
+		`defaults.options.failStrategy: "sometimes" is not fail-fast, fail-at-end or fail-never`,
+		"defaults.options: verbose and quiet can't be combined",
+		`modules.hidden: "shop-api" is not a module of this project`,
+		`properties: "bad key" is not a property name`,
+		"recipes[0] (Build): goals must not be empty",
+		`recipes[1]: duplicate recipe name "build"`,
+		"history.maxAgeDays: must not be negative",
+	}
+	message := err.Error()
+	for _, want := range wantProblems {
+		if !strings.Contains(message, want) {
+			t.Errorf("Expected problem %q in:\n%s", want, message)
+		}
+	}
+	if len(validationErr.Problems) != len(wantProblems) {
+		t.Errorf("Expected %d problems, got %d:\n%s", len(wantProblems), len(validationErr.Problems), message)
+	}
+}
+
+func TestLoad_SyntaxError(t *testing.T) {
+	project := testProject(t, "recipes: [\n")
+
+	_, err := Load(project)
+	if err == nil || !strings.Contains(err.Error(), "failed to parse "+FileName) {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/AR0106/mvn-tui/maven"
+	"gopkg.in/yaml.v3"
+)
+
+// Source names a layer of configuration
+type Source string
+
+// Layers in order of precedence, lowest first
+const (
+	SourceBuiltIn Source = "built-in default"
+	SourceUser    Source = "user config"
+	SourceProject Source = "project config"
+	SourceFlags   Source = "command line"
+)
+
+// Layer is one level of configuration and the file it came from
+type Layer struct {
+	Source Source
+	Path   string // File the layer was read from or looked for; empty for built-ins and flags
+	Config *Config
+}
+
+// BuiltIn returns the defaults every other layer overrides. Headless runs use
+// batch mode; the TUI keeps Maven's normal output, which its reactor panel
+// follows.
+func BuiltIn(headless bool) Layer {
+	var options Options
+	if headless {
+		enabled := true
+		options = Options{BatchMode: &enabled}
+	}
+	return Layer{Source: SourceBuiltIn, Config: &Config{Defaults: Defaults{Options: options}}}
+}
+
+// LoadLayers reads the user's config file and the project's, in order of precedence
+func LoadLayers(project *maven.Project) ([]Layer, error) {
+	userPath, _ := UserPath()
+	user, err := LoadUser()
+	if err != nil {
+		return nil, err
+	}
+	projectConfig, err := Load(project)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Layer{
+		{Source: SourceUser, Path: userPath, Config: user},
+		{Source: SourceProject, Path: filepath.Join(project.RootPath, FileName), Config: projectConfig},
+	}, nil
+}
+
+// Merge combines layers given in order of precedence, lowest first. Options,
+// properties and other single values are overridden by later layers;
+// profiles and modules accumulate; a recipe replaces an earlier one of the
+// same name. The result remembers which layer each setting came from.
+func Merge(layers ...Layer) *Config {
+	merged := &Config{origins: make(map[string]Source)}
+	for _, layer := range layers {
+		if layer.Config != nil {
+			merged.merge(layer.Config, layer.Source)
+		}
+	}
+	return merged
+}
+
+// merge overlays c, recording source as the origin of what it sets
+func (m *Config) merge(c *Config, source Source) {
+	if c.Defaults.JDK != "" {
+		m.Defaults.JDK = c.Defaults.JDK
+		m.origins["defaults.jdk"] = source
+	}
+	m.Defaults.Options.merge(c.Defaults.Options, "defaults.options", source, m.origins)
+	m.Defaults.Profiles = m.appendUnique(m.Defaults.Profiles, c.Defaults.Profiles, "defaults.profiles", source)
+
+	if c.UI.ReactorPanel != nil {
+		m.UI.ReactorPanel = c.UI.ReactorPanel
+		m.origins["ui.reactorPanel"] = source
+	}
+	if c.UI.LogLines != 0 {
+		m.UI.LogLines = c.UI.LogLines
+		m.origins["ui.logLines"] = source
+	}
+
+	if c.History.Entries != 0 {
+		m.History.Entries = c.History.Entries
+		m.origins["history.entries"] = source
+	}
+	if c.History.MaxAgeDays != 0 {
+		m.History.MaxAgeDays = c.History.MaxAgeDays
+		m.origins["history.maxAgeDays"] = source
+	}
+
+	m.Modules.Hidden = m.appendUnique(m.Modules.Hidden, c.Modules.Hidden, "modules.hidden", source)
+	m.Modules.Deselected = m.appendUnique(m.Modules.Deselected, c.Modules.Deselected, "modules.deselected", source)
+
+	for _, key := range sortedKeys(c.Properties) {
+		if m.Properties == nil {
+			m.Properties = make(map[string]string)
+		}
+		m.Properties[key] = c.Properties[key]
+		m.origins["properties."+key] = source
+	}
+
+	for _, recipe := range c.Recipes {
+		key := "recipes." + strings.ToLower(strings.TrimSpace(recipe.Name))
+		i := slices.IndexFunc(m.Recipes, func(r Recipe) bool {
+			return strings.EqualFold(strings.TrimSpace(r.Name), strings.TrimSpace(recipe.Name))
+		})
+		if i >= 0 {
+			m.Recipes[i] = recipe
+		} else {
+			m.Recipes = append(m.Recipes, recipe)
+		}
+		m.origins[key] = source
+	}
+}
+
+// appendUnique adds the values not already in list, recording their origin
+func (m *Config) appendUnique(list, values []string, key string, source Source) []string {
+	for _, value := range values {
+		if !slices.Contains(list, value) {
+			list = append(list, value)
+		}
+		m.origins[key+"."+value] = source
+	}
+	return list
+}
+
+// merge overlays the fields set in o. Turning on one of debug, verbose or
+// quiet turns the others off, so a later layer's choice wins.
+func (o *Options) merge(overlay Options, key string, source Source, origins map[string]Source) {
+	dst := reflect.ValueOf(o).Elem()
+	src := reflect.ValueOf(overlay)
+	modes := []string{"debug", "verbose", "quiet"}
+
+	for i := 0; i < src.NumField(); i++ {
+		field := src.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		name := yamlName(src.Type().Field(i))
+		dst.Field(i).Set(field)
+		origins[key+"."+name] = source
+
+		if slices.Contains(modes, name) && field.Elem().Bool() {
+			disabled := false
+			for j := 0; j < dst.NumField(); j++ {
+				other := yamlName(dst.Type().Field(j))
+				if other != name && slices.Contains(modes, other) && !dst.Field(j).IsNil() {
+					dst.Field(j).Set(reflect.ValueOf(&disabled))
+					origins[key+"."+other] = source
+				}
+			}
+		}
+	}
+}
+
+// yamlName returns the key of a struct field in config files
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return name
+}
+
+// Origin returns the layer a merged setting came from, such as
+// "defaults.options.threads", "properties.skipITs" or "recipes.ci build"
+func (c *Config) Origin(key string) (Source, bool) {
+	source, ok := c.origins[key]
+	return source, ok
+}
+
+// Dump writes the merged configuration of layers as YAML, noting next to
+// each setting the layer it came from
+func Dump(w io.Writer, layers ...Layer) error {
+	fmt.Fprintln(w, "# Effective configuration. Layers, from lowest to highest precedence:")
+	for _, layer := range layers {
+		switch {
+		case layer.Path == "":
+			fmt.Fprintf(w, "#   %s\n", layer.Source)
+		case layer.Config == nil || layer.Config.Path == "":
+			fmt.Fprintf(w, "#   %s: %s (not found)\n", layer.Source, layer.Path)
+		default:
+			fmt.Fprintf(w, "#   %s: %s\n", layer.Source, layer.Path)
+		}
+	}
+
+	merged := Merge(layers...)
+	var root yaml.Node
+	if err := root.Encode(merged); err != nil {
+		return err
+	}
+	if len(root.Content) == 0 {
+		_, err := fmt.Fprintln(w, "# (nothing is set)")
+		return err
+	}
+	merged.annotate(&root, "")
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&root); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// annotate sets the line comment of every setting below node to its origin
+func (c *Config) annotate(node *yaml.Node, key string) {
+	comment := func(n *yaml.Node, key string) {
+		if source, ok := c.origins[key]; ok {
+			n.LineComment = string(source)
+		}
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			path := k.Value
+			if key != "" {
+				path = key + "." + k.Value
+			}
+			if v.Kind == yaml.ScalarNode {
+				comment(v, path)
+			} else {
+				c.annotate(v, path)
+			}
+		}
+
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind == yaml.ScalarNode {
+				comment(item, key+"."+item.Value)
+				continue
+			}
+			// Recipes are noted once, beside their name
+			for i := 0; i+1 < len(item.Content); i += 2 {
+				if item.Content[i].Value == "name" {
+					name := item.Content[i+1]
+					comment(name, key+"."+strings.ToLower(strings.TrimSpace(name.Value)))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AR0106/mvn-tui/maven"
+)
+
+// writeUserConfig points XDG_CONFIG_HOME at a temporary directory holding content as the user config
+func writeUserConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	path := filepath.Join(dir, "mvn-tui", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write user config: %v", err)
+	}
+	return path
+}
+
+func TestMerge_LaterLayersWin(t *testing.T) {
+	writeUserConfig(t, `
+defaults:
+  options:
+    threads: 1C
+    debug: true
+    batchMode: true
+  profiles: [mine]
+properties:
+  env: user
+  user.only: "1"
+recipes:
+  - name: Fast
+    goals: [install]
+ui:
+  reactorPanel: false
+`)
+	project := testProject(t, `
+defaults:
+  options:
+    threads: "2"
+  profiles: [dev]
+properties:
+  env: project
+recipes:
+  - name: fast
+    goals: [clean, install]
+  - name: Docs
+    goals: [site]
+`)
+
+	layers, err := LoadLayers(project)
+	if err != nil {
+		t.Fatalf("Failed to load layers: %v", err)
+	}
+	// A lower layer asking for quiet output, which the user's debug turns off
+	quiet := true
+	base := Layer{Source: SourceBuiltIn, Config: &Config{Defaults: Defaults{Options: Options{Quiet: &quiet}}}}
+	cfg := Merge(append([]Layer{base}, layers...)...)
+
+	options := maven.BuildOptions{}
+	if err := cfg.Apply(project, &options); err != nil {
+		t.Fatalf("Failed to apply config: %v", err)
+	}
+	if options.Threads != "2" || !options.Debug || options.Quiet || !options.BatchMode {
+		t.Errorf("Expected project threads, and the user's debug over quiet, got %+v", options)
+	}
+	if want := []maven.Property{{Name: "env", Value: "project", HasValue: true, Enabled: true}, {Name: "user.only", Value: "1", HasValue: true, Enabled: true}}; !reflect.DeepEqual(options.Properties, want) {
+		t.Errorf("Expected properties merged key by key, got %v", options.Properties)
+	}
+	if got := project.GetEnabledProfiles(); strings.Join(got, ",") != "dev,mine" {
+		t.Errorf("Expected profiles from both layers, got %v", got)
+	}
+	if len(cfg.Recipes) != 2 || strings.Join(cfg.Recipes[0].Goals, " ") != "clean install" {
+		t.Errorf("Expected the project's fast recipe to replace the user's, got %+v", cfg.Recipes)
+	}
+	if cfg.UI.ReactorPanel == nil || *cfg.UI.ReactorPanel {
+		t.Error("Expected the user's UI preference")
+	}
+
+	origins := map[string]Source{
+		"defaults.options.threads":   SourceProject,
+		"defaults.options.debug":     SourceUser,
+		"defaults.options.quiet":     SourceUser,
+		"defaults.options.batchMode": SourceUser,
+		"defaults.profiles.mine":     SourceUser,
+		"properties.env":             SourceProject,
+		"recipes.fast":               SourceProject,
+	}
+	for key, want := range origins {
+		if got, ok := cfg.Origin(key); !ok || got != want {
+			t.Errorf("Expected %s to come from %s, got %q", key, want, got)
+		}
+	}
+}
+
+func TestLoadUser_RejectsModules(t *testing.T) {
+	writeUserConfig(t, "modules:\n  hidden: [docs]\nrecipes:\n  - name: Core\n    goals: [install]\n    modules: [core]\n")
+
+	_, err := LoadUser()
+	if err == nil {
+		t.Fatal("Expected modules in the user config to be rejected")
+	}
+	for _, want := range []string{"modules.hidden: modules can only be named", "recipes[0] (Core).modules: modules can only be named"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in:\n%v", want, err)
+		}
+	}
+}
+
+func TestUserPath_DefaultsToDotConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", home)
+
+	path, err := UserPath()
+	if err != nil {
+		t.Fatalf("Failed to get user config path: %v", err)
+	}
+	if want := filepath.Join(home, ".config", "mvn-tui", "config.yaml"); path != want {
+		t.Errorf("Expected %s, got %s", want, path)
+	}
+}
+
+func TestApply_ResolvesJDK(t *testing.T) {
+	detectJavaVersions = func() []maven.JavaVersion {
+		return []maven.JavaVersion{{Version: "21", Path: "/opt/jdk-21"}, {Version: "17", Path: "/opt/jdk-17"}}
+	}
+	t.Cleanup(func() { detectJavaVersions = maven.DetectJavaVersions })
+
+	jdkHome := t.TempDir()
+	tests := []struct {
+		jdk     string
+		want    string
+		wantErr string
+	}{
+		{jdk: "17", want: "/opt/jdk-17"},
+		{jdk: "11", wantErr: "JDK 11 is not installed"},
+		{jdk: jdkHome, want: jdkHome},
+		{jdk: filepath.Join(jdkHome, "missing"), wantErr: "is not a directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.jdk, func(t *testing.T) {
+			cfg := &Config{Defaults: Defaults{JDK: tt.jdk}}
+			options := maven.BuildOptions{}
+			err := cfg.Apply(&maven.Project{}, &options)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to apply config: %v", err)
+			}
+			if options.JavaHome != tt.want {
+				t.Errorf("Expected JAVA_HOME %s, got %s", tt.want, options.JavaHome)
+			}
+			if cmd := maven.BuildCommand(&maven.Project{Executable: "mvn"}, []string{"verify"}, options); cmd.String() != "JAVA_HOME="+tt.want+" mvn verify" {
+				t.Errorf("Expected the command to set JAVA_HOME, got %q", cmd.String())
+			}
+		})
+	}
+}
+
+func TestDump_NotesOrigins(t *testing.T) {
+	userPath := writeUserConfig(t, "defaults:\n  jdk: \"21\"\n")
+	project := testProject(t, "properties:\n  skipITs: \"true\"\n")
+
+	layers, err := LoadLayers(project)
+	if err != nil {
+		t.Fatalf("Failed to load layers: %v", err)
+	}
+	flags := Layer{Source: SourceFlags, Config: &Config{Defaults: Defaults{Profiles: []string{"ci"}}}}
+
+	var out bytes.Buffer
+	if err := Dump(&out, append(append([]Layer{BuiltIn(true)}, layers...), flags)...); err != nil {
+		t.Fatalf("Failed to dump config: %v", err)
+	}
+
+	for _, want := range []string{
+		"#   user config: " + userPath + "\n",
+		"#   project config: " + filepath.Join(project.RootPath, FileName) + "\n",
+		`jdk: "21" # user config`,
+		"batchMode: true # built-in default",
+		"- ci # command line",
+		`skipITs: "true" # project config`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	// A missing file is still listed, so it's clear where it was looked for
+	os.Remove(userPath)
+	layers, _ = LoadLayers(project)
+	out.Reset()
+	if err := Dump(&out, layers...); err != nil {
+		t.Fatalf("Failed to dump config: %v", err)
+	}
+	if !strings.Contains(out.String(), userPath+" (not found)") {
+		t.Errorf("Expected the missing user config to be noted:\n%s", out.String())
+	}
+}
@@ -0,0 +1,273 @@
+// Package export writes Maven commands as a shell script, Makefile targets
+// or GitHub Actions steps, so a build run in the TUI can be repeated
+// elsewhere.
+package export
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/AR0106/mvn-tui/maven"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is a kind of file commands can be exported to
+type Format int
+
+const (
+	FormatShell Format = iota
+	FormatMakefile
+	FormatGitHubActions
+)
+
+// Formats lists every format, in the order the TUI offers them
+var Formats = []Format{FormatShell, FormatMakefile, FormatGitHubActions}
+
+// String returns the name of the format
+func (f Format) String() string {
+	switch f {
+	case FormatMakefile:
+		return "Makefile"
+	case FormatGitHubActions:
+		return "GitHub Actions"
+	default:
+		return "Shell script"
+	}
+}
+
+// DefaultFile returns the file name suggested for the format
+func (f Format) DefaultFile() string {
+	switch f {
+	case FormatMakefile:
+		return "mvn-tui.mk"
+	case FormatGitHubActions:
+		return "mvn-tui-steps.yml"
+	default:
+		return "mvn-tui.sh"
+	}
+}
+
+// Step is a command to export and the name it is exported under
+type Step struct {
+	Name    string
+	Command maven.Command
+}
+
+// Write exports steps in format. Commands run from projectRoot, so a Maven
+// wrapper inside it is written relative to it, as ./mvnw.
+func Write(w io.Writer, format Format, projectRoot string, steps []Step) error {
+	switch format {
+	case FormatShell:
+		return writeShell(w, projectRoot, steps)
+	case FormatMakefile:
+		return writeMakefile(w, projectRoot, steps)
+	case FormatGitHubActions:
+		return writeGitHubActions(w, projectRoot, steps)
+	default:
+		return fmt.Errorf("unknown export format %d", format)
+	}
+}
+
+// writeShell writes a POSIX shell script that stops at the first failure
+func writeShell(w io.Writer, projectRoot string, steps []Step) error {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString("# Exported from mvn-tui. Run from the project root.\n")
+	b.WriteString("set -e\n")
+	for _, step := range steps {
+		b.WriteString("\n")
+		writeComments(&b, "# ", step)
+		b.WriteString(shellLine(projectRoot, step.Command, true) + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMakefile writes a target per step and an "all" target running them in order
+func writeMakefile(w io.Writer, projectRoot string, steps []Step) error {
+	targets := make([]string, len(steps))
+	used := map[string]int{"all": 1} // Taken by the target running every step
+	for i, step := range steps {
+		name := Slug(step.Name)
+		if name == "" {
+			name = "build"
+		}
+		used[name]++
+		if used[name] > 1 {
+			name = fmt.Sprintf("%s-%d", name, used[name])
+		}
+		targets[i] = name
+	}
+
+	var b strings.Builder
+	b.WriteString("# Exported from mvn-tui. Run from the project root.\n")
+	fmt.Fprintf(&b, ".PHONY: all %s\n\n", strings.Join(targets, " "))
+	fmt.Fprintf(&b, "all: %s\n", strings.Join(targets, " "))
+	for i, step := range steps {
+		b.WriteString("\n")
+		writeComments(&b, "# ", step)
+		// Make expands $ in recipes before the shell sees them
+		line := strings.ReplaceAll(shellLine(projectRoot, step.Command, true), "$", "$$")
+		fmt.Fprintf(&b, "%s:\n\t%s\n", targets[i], line)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeGitHubActions writes steps to paste under a job's steps:
+func writeGitHubActions(w io.Writer, projectRoot string, steps []Step) error {
+	list := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, step := range steps {
+		item := &yaml.Node{Kind: yaml.MappingNode}
+		addScalar(item, "name", step.Name)
+		addScalar(item, "run", shellLine(projectRoot, step.Command, false))
+
+		var comments strings.Builder
+		writeComments(&comments, "", step)
+		item.HeadComment = strings.TrimSuffix(comments.String(), "\n")
+
+		if len(step.Command.Env) > 0 {
+			env := &yaml.Node{Kind: yaml.MappingNode}
+			for _, variable := range step.Command.Env {
+				key, value, _ := strings.Cut(variable, "=")
+				addScalar(env, key, value)
+			}
+			item.Content = append(item.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "env"}, env)
+		}
+		list.Content = append(list.Content, item)
+	}
+
+	if _, err := io.WriteString(w, "# Exported from mvn-tui. Paste under a job's steps: after checking out the project.\n"); err != nil {
+		return err
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(list); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// addScalar appends a string entry to a mapping node
+func addScalar(mapping *yaml.Node, key, value string) {
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Value: value, Tag: "!!str"})
+}
+
+// writeComments notes the step's name and the profiles and modules it built
+func writeComments(b *strings.Builder, prefix string, step Step) {
+	profiles, modules := ProfilesAndModules(step.Command)
+	fmt.Fprintf(b, "%s%s\n", prefix, step.Name)
+	if len(profiles) > 0 {
+		fmt.Fprintf(b, "%sProfiles: %s\n", prefix, strings.Join(profiles, ", "))
+	}
+	if len(modules) > 0 {
+		fmt.Fprintf(b, "%sModules: %s\n", prefix, strings.Join(modules, ", "))
+	}
+}
+
+// valueOptions are the Maven options whose value is the next argument
+var valueOptions = map[string]bool{
+	"-P": true, "--activate-profiles": true,
+	"-pl": true, "--projects": true,
+	"-T": true, "--threads": true,
+	"-rf": true, "--resume-from": true,
+	"-f": true, "--file": true,
+	"-s": true, "--settings": true,
+	"-gs": true, "--global-settings": true,
+	"-b": true, "--builder": true,
+	"-l": true, "--log-file": true,
+}
+
+// Goals returns the goals and phases of a command, leaving out its options
+func Goals(cmd maven.Command) []string {
+	var goals []string
+	for i := 0; i < len(cmd.Args); i++ {
+		arg := cmd.Args[i]
+		switch {
+		case valueOptions[arg]:
+			i++
+		case !strings.HasPrefix(arg, "-"):
+			goals = append(goals, arg)
+		}
+	}
+	return goals
+}
+
+// ProfilesAndModules returns the profiles (-P) and modules (-pl) a command
+// was run with
+func ProfilesAndModules(cmd maven.Command) (profiles, modules []string) {
+	for i := 0; i+1 < len(cmd.Args); i++ {
+		switch cmd.Args[i] {
+		case "-P", "--activate-profiles":
+			profiles = append(profiles, strings.Split(cmd.Args[i+1], ",")...)
+			i++
+		case "-pl", "--projects":
+			modules = append(modules, strings.Split(cmd.Args[i+1], ",")...)
+			i++
+		}
+	}
+	return profiles, modules
+}
+
+// CommandLine returns the command as a line of POSIX shell, as it ran
+func CommandLine(cmd maven.Command) string {
+	return shellLine("", cmd, true)
+}
+
+// shellLine returns the command as a line of POSIX shell, with its
+// environment variables in front if withEnv is set
+func shellLine(projectRoot string, cmd maven.Command, withEnv bool) string {
+	var words []string
+	if withEnv {
+		for _, variable := range cmd.Env {
+			key, value, _ := strings.Cut(variable, "=")
+			words = append(words, key+"="+Quote(value))
+		}
+	}
+	words = append(words, Quote(executable(projectRoot, cmd.Executable)))
+	for _, arg := range cmd.Args {
+		words = append(words, Quote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+// executable returns path relative to projectRoot if it is inside it
+func executable(projectRoot, path string) string {
+	if projectRoot == "" || !filepath.IsAbs(path) {
+		return path
+	}
+	rel, err := filepath.Rel(projectRoot, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return "./" + filepath.ToSlash(rel)
+}
+
+// Quote returns s as a single POSIX shell word
+func Quote(s string) string {
+	return maven.QuoteArg(s)
+}
+
+// Slug turns a task or recipe name into lowercase words joined by dashes, so
+// that "Run (Java)" becomes run-java, for use as a Makefile target or on the
+// command line. Names without letters or digits give "".
+func Slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
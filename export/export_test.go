@@ -0,0 +1,150 @@
+package export
+
+import (
+	"bytes"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/AR0106/mvn-tui/maven"
+)
+
+// testSteps returns a wrapper build with an awkward property and a plain install
+func testSteps() []Step {
+	return []Step{
+		{Name: "CI build", Command: maven.Command{
+			Executable: "/work/shop/mvnw",
+			Args:       []string{"-P", "ci,release", "-pl", "shop-core", "-Dmsg=it's $HOME", "clean", "verify"},
+			Env:        []string{"JAVA_HOME=/opt/jdk 21"},
+		}},
+		{Name: "install", Command: maven.Command{Executable: "mvn", Args: []string{"-o", "install"}}},
+	}
+}
+
+func TestWrite_Shell(t *testing.T) {
+	var out bytes.Buffer
+	if err := Write(&out, FormatShell, "/work/shop", testSteps()); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+
+	want := `#!/bin/sh
+# Exported from mvn-tui. Run from the project root.
+set -e
+
+# CI build
+# Profiles: ci, release
+# Modules: shop-core
+JAVA_HOME='/opt/jdk 21' ./mvnw -P ci,release -pl shop-core '-Dmsg=it'\''s $HOME' clean verify
+
+# install
+mvn -o install
+`
+	if out.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out.String())
+	}
+}
+
+func TestWrite_Makefile(t *testing.T) {
+	steps := append(testSteps(), Step{Name: "Install", Command: maven.Command{Executable: "mvn", Args: []string{"install"}}})
+
+	var out bytes.Buffer
+	if err := Write(&out, FormatMakefile, "/work/shop", steps); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+
+	for _, want := range []string{
+		".PHONY: all ci-build install install-2\n",
+		"all: ci-build install install-2\n",
+		"# Profiles: ci, release\n# Modules: shop-core\nci-build:\n\tJAVA_HOME='/opt/jdk 21' ./mvnw -P ci,release -pl shop-core '-Dmsg=it'\\''s $$HOME' clean verify\n",
+		"install-2:\n\tmvn install\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestWrite_MakefileReservesAll(t *testing.T) {
+	steps := []Step{
+		{Name: "All", Command: maven.Command{Executable: "mvn", Args: []string{"verify"}}},
+		{Name: "(?)", Command: maven.Command{Executable: "mvn", Args: []string{"install"}}},
+	}
+
+	var out bytes.Buffer
+	if err := Write(&out, FormatMakefile, "/work/shop", steps); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	for _, want := range []string{"all: all-2 build\n", "all-2:\n\tmvn verify\n", "build:\n\tmvn install\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Clean Install":        "clean-install",
+		"Run (Java)":           "run-java",
+		"Run (exec:java only)": "run-exec-java-only",
+		"run-java":             "run-java",
+		"  TEST ":              "test",
+		"(?)":                  "",
+	}
+	for name, want := range tests {
+		if got := Slug(name); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestWrite_GitHubActions(t *testing.T) {
+	var out bytes.Buffer
+	if err := Write(&out, FormatGitHubActions, "/work/shop", testSteps()); err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+
+	want := `# Exported from mvn-tui. Paste under a job's steps: after checking out the project.
+# CI build
+# Profiles: ci, release
+# Modules: shop-core
+- name: CI build
+  run: ./mvnw -P ci,release -pl shop-core '-Dmsg=it'\''s $HOME' clean verify
+  env:
+    JAVA_HOME: /opt/jdk 21
+# install
+- name: install
+  run: mvn -o install
+`
+	if out.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out.String())
+	}
+}
+
+func TestQuote_SurvivesTheShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+
+	for _, arg := range []string{"plain", "", "two words", "it's", `"double"`, "$HOME", "back\\slash", "new\nline", "*.java", "-Dx=a;b"} {
+		out, err := exec.Command("sh", "-c", "printf %s "+Quote(arg)).Output()
+		if err != nil {
+			t.Fatalf("Shell failed for %q: %v", arg, err)
+		}
+		if string(out) != arg {
+			t.Errorf("Quote(%q) came back from the shell as %q", arg, out)
+		}
+	}
+}
+
+func TestGoalsAndProfilesAndModules(t *testing.T) {
+	cmd := maven.Command{Args: []string{"-P", "dev", "-pl", "a,b", "-T", "1C", "-DskipTests", "-q", "clean", "spring-boot:run"}}
+
+	if got := strings.Join(Goals(cmd), " "); got != "clean spring-boot:run" {
+		t.Errorf("Expected goals without options, got %q", got)
+	}
+	profiles, modules := ProfilesAndModules(cmd)
+	if strings.Join(profiles, ",") != "dev" || strings.Join(modules, ",") != "a,b" {
+		t.Errorf("Expected profile dev and modules a,b, got %v and %v", profiles, modules)
+	}
+}
@@ -0,0 +1,76 @@
+package history
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxGoals is how many goal lines typed at the custom goal prompt are kept
+const MaxGoals = 100
+
+const goalsFile = "goals.txt"
+
+// Goals returns the goal lines typed in the project, oldest first
+func (s *Store) Goals() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readGoals()
+}
+
+// AddGoals records a typed goal line, moving it to the end if it was typed
+// before, and returns the updated lines
+func (s *Store) AddGoals(line string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line = strings.TrimSpace(line)
+	goals, err := s.readGoals()
+	if err != nil {
+		return nil, err
+	}
+	kept := goals[:0]
+	for _, goal := range goals {
+		if goal != line {
+			kept = append(kept, goal)
+		}
+	}
+	goals = append(kept, line)
+	if len(goals) > MaxGoals {
+		goals = goals[len(goals)-MaxGoals:]
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to save goals: %w", err)
+	}
+	path := filepath.Join(s.dir, goalsFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(goals, "\n")+"\n"), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to save goals: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("failed to save goals: %w", err)
+	}
+	return goals, nil
+}
+
+// readGoals parses the goals file
+func (s *Store) readGoals() ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, goalsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read goals: %w", err)
+	}
+
+	var goals []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			goals = append(goals, line)
+		}
+	}
+	return goals, nil
+}
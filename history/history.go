@@ -0,0 +1,319 @@
+// Package history persists the commands run in a project, with their logs,
+// under the user's state directory, so they survive restarts.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AR0106/mvn-tui/maven"
+)
+
+// DefaultEntries is the number of entries kept when retention doesn't say
+const DefaultEntries = 200
+
+const (
+	entriesFile = "history.jsonl"
+	logsDir     = "logs"
+)
+
+// Retention limits how much history is kept. Entries beyond the newest
+// Entries, or older than MaxAge, are dropped along with their logs.
+type Retention struct {
+	Entries int           // DefaultEntries if zero or less
+	MaxAge  time.Duration // No limit if zero
+}
+
+// Entry is one command run in the project
+type Entry struct {
+	Command   maven.Command `json:"command"`
+	ExitCode  int           `json:"exitCode"`
+	Duration  time.Duration `json:"duration"`
+	StartTime time.Time     `json:"startTime"`
+	Branch    string        `json:"branch,omitempty"` // Git branch the command ran on, if any
+	Error     string        `json:"error,omitempty"`  // Why the command couldn't run
+	LogFile   string        `json:"logFile,omitempty"`
+
+	// ResumeFrom is the module the failed build can be resumed from with -rf
+	ResumeFrom string `json:"resumeFrom,omitempty"`
+
+	Log *maven.LogStore `json:"-"` // The log while it is still in memory, for entries of this session
+}
+
+// NewEntry records a finished command
+func NewEntry(result *maven.ExecutionResult, branch string) Entry {
+	entry := Entry{
+		Command:    result.Command,
+		ExitCode:   result.ExitCode,
+		Duration:   result.Duration,
+		StartTime:  result.StartTime,
+		Branch:     branch,
+		LogFile:    result.StartTime.UTC().Format("20060102-150405.000000000") + ".log",
+		ResumeFrom: result.ResumeFrom,
+		Log:        result.Log,
+	}
+	if result.Error != nil {
+		entry.Error = result.Error.Error()
+	}
+	return entry
+}
+
+// Succeeded reports whether the command ran and exited with code zero
+func (e Entry) Succeeded() bool {
+	return e.ExitCode == 0 && e.Error == ""
+}
+
+// Store reads and writes the history of one project. It is safe for
+// concurrent use.
+type Store struct {
+	mu        sync.Mutex
+	dir       string
+	retention Retention
+}
+
+// StateDir returns mvn-tui's directory for persistent state,
+// $XDG_STATE_HOME/mvn-tui, where XDG_STATE_HOME defaults to ~/.local/state
+func StateDir() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "mvn-tui"), nil
+}
+
+// Open returns the store of the project at projectRoot. Nothing is created
+// on disk until the first entry is added.
+func Open(projectRoot string, retention Retention) (*Store, error) {
+	stateDir, err := StateDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to find the state directory: %w", err)
+	}
+	root, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	// Readable, and unique even for projects with the same directory name
+	sum := sha256.Sum256([]byte(root))
+	name := filepath.Base(root) + "-" + hex.EncodeToString(sum[:6])
+
+	if retention.Entries <= 0 {
+		retention.Entries = DefaultEntries
+	}
+	return &Store{dir: filepath.Join(stateDir, "projects", name), retention: retention}, nil
+}
+
+// Dir returns the directory holding the project's history
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// LogPath returns where the log of entry is stored
+func (s *Store) LogPath(entry Entry) string {
+	return filepath.Join(s.dir, logsDir, entry.LogFile)
+}
+
+// Load returns the retained entries, oldest first. Lines that can't be
+// parsed are skipped, so a damaged file loses only those entries.
+func (s *Store) Load() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	kept, _ := s.retain(entries, time.Now())
+	return kept, nil
+}
+
+// Add appends entry with its log and drops entries beyond retention. The log
+// is streamed to its file, so spilled lines aren't read back into memory.
+func (s *Store) Add(entry Entry, log *maven.LogStore) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Join(s.dir, logsDir), 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	if entry.LogFile != "" {
+		if err := writeLog(s.LogPath(entry), log); err != nil {
+			return fmt.Errorf("failed to save log: %w", err)
+		}
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(s.dir, entriesFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+
+	return s.prune(time.Now())
+}
+
+// writeLog writes log to the file at path; a nil log leaves it empty
+func writeLog(path string, log *maven.LogStore) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if log != nil {
+		if _, err := log.WriteTo(f); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// read parses the entries file
+func (s *Store) read() ([]Entry, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, entriesFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// retain splits entries into those kept by the retention and those dropped
+func (s *Store) retain(entries []Entry, now time.Time) (kept, dropped []Entry) {
+	for i, entry := range entries {
+		tooMany := len(entries)-i > s.retention.Entries
+		tooOld := s.retention.MaxAge > 0 && now.Sub(entry.StartTime) > s.retention.MaxAge
+		if tooMany || tooOld {
+			dropped = append(dropped, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	return kept, dropped
+}
+
+// prune rewrites the entries file without the entries beyond retention and
+// removes their logs
+func (s *Store) prune(now time.Time) error {
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	kept, dropped := s.retain(entries, now)
+	if len(dropped) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range kept {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(line, '\n'))
+	}
+
+	// Replace the file in one step, so a crash can't leave it half written
+	path := filepath.Join(s.dir, entriesFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to prune history: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to prune history: %w", err)
+	}
+
+	for _, entry := range dropped {
+		if entry.LogFile != "" {
+			os.Remove(s.LogPath(entry))
+		}
+	}
+	return nil
+}
+
+// GitBranch returns the branch checked out in the git repository containing
+// dir, the abbreviated commit if HEAD is detached, or "" outside a repository
+func GitBranch(dir string) string {
+	gitDir := findGitDir(dir)
+	if gitDir == "" {
+		return ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	head := strings.TrimSpace(string(data))
+	if ref, ok := strings.CutPrefix(head, "ref: "); ok {
+		return strings.TrimPrefix(ref, "refs/heads/")
+	}
+	if len(head) > 7 {
+		head = head[:7]
+	}
+	return head
+}
+
+// findGitDir walks up from dir to the repository's git directory, following
+// the "gitdir:" file of worktrees and submodules
+func findGitDir(dir string) string {
+	for {
+		path := filepath.Join(dir, ".git")
+		if info, err := os.Stat(path); err == nil {
+			if info.IsDir() {
+				return path
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return ""
+			}
+			gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+			if !ok {
+				return ""
+			}
+			if !filepath.IsAbs(gitDir) {
+				gitDir = filepath.Join(dir, gitDir)
+			}
+			return gitDir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
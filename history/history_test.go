@@ -0,0 +1,219 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AR0106/mvn-tui/maven"
+)
+
+// openStore returns the store of a new project, kept under a temporary state directory
+func openStore(t *testing.T, retention Retention) *Store {
+	t.Helper()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	store, err := Open(filepath.Join(t.TempDir(), "shop"), retention)
+	if err != nil {
+		t.Fatalf("Failed to open store: %v", err)
+	}
+	return store
+}
+
+// logOf returns a log holding lines
+func logOf(lines ...string) *maven.LogStore {
+	log := maven.NewLogStore(0)
+	log.Append(lines...)
+	return log
+}
+
+// entryAt returns an entry for a command started at start
+func entryAt(start time.Time, goal string) Entry {
+	return NewEntry(&maven.ExecutionResult{
+		Command:   maven.Command{Executable: "mvn", Args: []string{goal}, PrettyArgs: goal},
+		ExitCode:  1,
+		Duration:  2 * time.Second,
+		StartTime: start,
+	}, "main")
+}
+
+func TestStore_AddThenLoad(t *testing.T) {
+	store := openStore(t, Retention{})
+	if !strings.HasPrefix(filepath.Base(store.Dir()), "shop-") {
+		t.Errorf("Expected the project directory to be named after the project, got %s", store.Dir())
+	}
+
+	entries, err := store.Load()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Expected no history before the first command, got %v (%v)", entries, err)
+	}
+
+	start := time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)
+	entry := entryAt(start, "verify")
+	entry.Command.Env = []string{"JAVA_HOME=/opt/jdk-21"}
+	entry.ResumeFrom = ":billing-service"
+	if err := store.Add(entry, logOf("[INFO] BUILD FAILURE", "Completed with exit code 1")); err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+
+	entries, err = store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	got := entries[0]
+	if got.Command.String() != "JAVA_HOME=/opt/jdk-21 mvn verify" || got.ExitCode != 1 || got.Duration != 2*time.Second || !got.StartTime.Equal(start) || got.Branch != "main" || got.ResumeFrom != ":billing-service" {
+		t.Errorf("Expected the entry to round-trip, got %+v", got)
+	}
+	if got.Succeeded() {
+		t.Error("Expected a failed entry")
+	}
+
+	log, err := os.ReadFile(store.LogPath(got))
+	if err != nil {
+		t.Fatalf("Expected the log to be stored: %v", err)
+	}
+	if string(log) != "[INFO] BUILD FAILURE\nCompleted with exit code 1\n" {
+		t.Errorf("Unexpected stored log %q", log)
+	}
+}
+
+func TestStore_PrunesByRetention(t *testing.T) {
+	store := openStore(t, Retention{Entries: 2, MaxAge: 24 * time.Hour})
+
+	now := time.Now()
+	old := entryAt(now.Add(-48*time.Hour), "clean")
+	entries := []Entry{old, entryAt(now.Add(-3*time.Minute), "compile"), entryAt(now.Add(-2*time.Minute), "test"), entryAt(now.Add(-time.Minute), "install")}
+	for _, entry := range entries {
+		if err := store.Add(entry, logOf(entry.Command.PrettyArgs)); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load history: %v", err)
+	}
+	var goals []string
+	for _, entry := range loaded {
+		goals = append(goals, entry.Command.PrettyArgs)
+	}
+	if strings.Join(goals, ",") != "test,install" {
+		t.Errorf("Expected the newest 2 entries, got %v", goals)
+	}
+
+	for _, entry := range entries[:2] {
+		if _, err := os.Stat(store.LogPath(entry)); !os.IsNotExist(err) {
+			t.Errorf("Expected the log of pruned %q to be removed", entry.Command.PrettyArgs)
+		}
+	}
+	if _, err := os.Stat(store.LogPath(entries[3])); err != nil {
+		t.Errorf("Expected the log of a kept entry: %v", err)
+	}
+}
+
+func TestStore_LoadSkipsDamagedLines(t *testing.T) {
+	store := openStore(t, Retention{})
+	if err := store.Add(entryAt(time.Now(), "package"), nil); err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(store.Dir(), entriesFile), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	f.WriteString("{\"command\": trunc\n")
+	f.Close()
+
+	entries, err := store.Load()
+	if err != nil || len(entries) != 1 {
+		t.Errorf("Expected the damaged line to be skipped, got %d entries (%v)", len(entries), err)
+	}
+}
+
+func TestGitBranch(t *testing.T) {
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	repo := t.TempDir()
+	write(filepath.Join(repo, ".git", "HEAD"), "ref: refs/heads/feature/login\n")
+	module := filepath.Join(repo, "shop-core")
+	os.MkdirAll(module, 0755)
+
+	detached := t.TempDir()
+	write(filepath.Join(detached, ".git", "HEAD"), "4972aa1c0ffee4972aa1c0ffee4972aa1c0ffee12\n")
+
+	// A worktree's .git is a file pointing at its git directory
+	worktree := t.TempDir()
+	write(filepath.Join(worktree, ".git"), "gitdir: "+filepath.Join(repo, ".git", "worktrees", "wt")+"\n")
+	write(filepath.Join(repo, ".git", "worktrees", "wt", "HEAD"), "ref: refs/heads/release\n")
+
+	tests := map[string]string{
+		module:      "feature/login",
+		detached:    "4972aa1",
+		worktree:    "release",
+		t.TempDir(): "",
+	}
+	for dir, want := range tests {
+		if got := GitBranch(dir); got != want {
+			t.Errorf("GitBranch(%s) = %q, want %q", dir, got, want)
+		}
+	}
+}
+
+func TestStore_AddGoals(t *testing.T) {
+	store := openStore(t, Retention{})
+	if goals, err := store.Goals(); err != nil || len(goals) != 0 {
+		t.Fatalf("Expected no goals before the first one, got %v (%v)", goals, err)
+	}
+
+	for _, line := range []string{"dependency:tree", `versions:set -DnewVersion="2.0 beta"`, " dependency:tree "} {
+		if _, err := store.AddGoals(line); err != nil {
+			t.Fatalf("Failed to add goals: %v", err)
+		}
+	}
+	goals, err := store.Goals()
+	want := []string{`versions:set -DnewVersion="2.0 beta"`, "dependency:tree"}
+	if err != nil || strings.Join(goals, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected a line typed again to move to the end, got %q (%v)", goals, err)
+	}
+
+	for i := 0; i < MaxGoals+5; i++ {
+		goals, err = store.AddGoals(fmt.Sprintf("help:describe -Dplugin=p%d", i))
+	}
+	if err != nil || len(goals) != MaxGoals || goals[MaxGoals-1] != fmt.Sprintf("help:describe -Dplugin=p%d", MaxGoals+4) {
+		t.Errorf("Expected the newest %d lines, got %d ending %q (%v)", MaxGoals, len(goals), goals[len(goals)-1], err)
+	}
+}
+
+func TestStore_SaveProperties(t *testing.T) {
+	store := openStore(t, Retention{})
+	if properties, err := store.Properties(); err != nil || len(properties) != 0 {
+		t.Fatalf("Expected no properties before saving, got %v (%v)", properties, err)
+	}
+
+	want := []maven.Property{
+		{Name: "test", Value: "MyTest", Enabled: true},
+		{Name: "msg", Value: "two words"},
+		{Name: "skipITs", Enabled: true},
+	}
+	if err := store.SaveProperties(want); err != nil {
+		t.Fatalf("Failed to save properties: %v", err)
+	}
+	properties, err := store.Properties()
+	if err != nil || !reflect.DeepEqual(properties, want) {
+		t.Errorf("Properties() = %+v (%v), want %+v", properties, err, want)
+	}
+}
@@ -0,0 +1,57 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/AR0106/mvn-tui/maven"
+)
+
+const propertiesFile = "properties.json"
+
+// Properties returns the system properties kept in the project's
+// properties panel, in the order they were added
+func (s *Store) Properties() ([]maven.Property, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(s.dir, propertiesFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read properties: %w", err)
+	}
+
+	var properties []maven.Property
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, fmt.Errorf("failed to parse properties: %w", err)
+	}
+	return properties, nil
+}
+
+// SaveProperties replaces the properties kept for the project
+func (s *Store) SaveProperties(properties []maven.Property) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(properties, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to save properties: %w", err)
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to save properties: %w", err)
+	}
+	path := filepath.Join(s.dir, propertiesFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to save properties: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to save properties: %w", err)
+	}
+	return nil
+}
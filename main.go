@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/AR0106/mvn-tui/cli"
+	"github.com/AR0106/mvn-tui/config"
+	"github.com/AR0106/mvn-tui/history"
 	"github.com/AR0106/mvn-tui/maven"
 	"github.com/AR0106/mvn-tui/ui"
 	tea "github.com/charmbracelet/bubbletea"
@@ -29,6 +32,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Subcommands run tasks without the TUI
+	if len(os.Args) > 1 && (cli.IsCommand(os.Args[1]) || os.Args[1] == "--help" || os.Args[1] == "-h") {
+		os.Exit(cli.Run(os.Args[1:], cwd, os.Stdout, os.Stderr))
+	}
+
 	// Find Maven project root
 	projectRoot, err := maven.FindProjectRoot(cwd)
 
@@ -44,13 +52,36 @@ func main() {
 			fmt.Fprintf(os.Stderr, "Error loading Maven project: %v\n", err)
 			os.Exit(1)
 		}
-		model = ui.NewModel(project)
+		// Built-in defaults < user config < project config
+		layers, err := config.LoadLayers(project)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		cfg := config.Merge(append([]config.Layer{config.BuiltIn(false)}, layers...)...)
+		m, err := ui.NewModelWithConfig(project, cfg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying config: %v\n", err)
+			os.Exit(1)
+		}
+		// History is only kept when the state directory can be found
+		if store, err := history.Open(project.RootPath, cfg.History.Retention()); err == nil {
+			m = m.WithHistory(store)
+		}
+		model = m
 	}
 
 	// Create and start the Bubbletea program
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
-	if _, err := p.Run(); err != nil {
+	finalModel, err := p.Run()
+
+	// Remove any log files spilled to disk during the session
+	if m, ok := finalModel.(ui.Model); ok {
+		m.Close()
+	}
+
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running program: %v\n", err)
 		os.Exit(1)
 	}
@@ -0,0 +1,370 @@
+package maven
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ActivationContext is what profile activation is evaluated against, standing
+// in for the JVM and command line Maven would run with
+type ActivationContext struct {
+	JDK        string            // Version of the JDK running Maven, such as 21.0.2; JDK conditions fail if empty
+	GOOS       string            // Operating system, as runtime.GOOS names it
+	GOARCH     string            // Architecture, as runtime.GOARCH names it
+	Properties map[string]string // -D properties, from .mvn/maven.config and the options
+	LookupEnv  func(string) (string, bool)
+}
+
+// NewActivationContext describes the machine and options the project would
+// be built with. The JDK is the one in options, else $JAVA_HOME, else the
+// java on the PATH, which is only run if a profile depends on the JDK.
+func NewActivationContext(project *Project, options BuildOptions) ActivationContext {
+	properties := MavenConfigProperties(project.RootPath)
+	for _, prop := range EnabledProperties(options.Properties) {
+		// Maven sets a property passed as -Dname without a value to true
+		value := prop.Value
+		if value == "" && !prop.HasValue {
+			value = "true"
+		}
+		properties[prop.Name] = value
+	}
+
+	ctx := ActivationContext{
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		Properties: properties,
+		LookupEnv:  os.LookupEnv,
+	}
+
+	home := options.JavaHome
+	if home == "" {
+		home = os.Getenv("JAVA_HOME")
+	}
+	if home != "" {
+		ctx.JDK = jdkReleaseVersion(home)
+	} else if project.dependsOnJDK() {
+		ctx.JDK = getJavaVersionFromExec("java").FullVersion
+	}
+	return ctx
+}
+
+// releaseVersionPattern matches the version in a JDK's release file
+var releaseVersionPattern = regexp.MustCompile(`(?m)^JAVA_VERSION="([^"]+)"`)
+
+// jdkReleaseVersion reads the version of the JDK at home from its release file
+func jdkReleaseVersion(home string) string {
+	data, err := os.ReadFile(filepath.Join(home, "release"))
+	if err != nil {
+		return ""
+	}
+	if match := releaseVersionPattern.FindSubmatch(data); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+// dependsOnJDK reports whether a profile is activated by the JDK version
+func (p *Project) dependsOnJDK() bool {
+	for _, prof := range p.Profiles {
+		if prof.Activation != nil && prof.Activation.JDK != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateProfiles works out which profiles Maven activates on its own in
+// ctx, including those listed under activeProfiles in settings.xml. As in
+// Maven, activeByDefault profiles stay off when another profile declared in
+// the same file is activated.
+func (p *Project) EvaluateProfiles(ctx ActivationContext) {
+	activated := make(map[string]string) // First profile activated in each file
+	for i := range p.Profiles {
+		prof := &p.Profiles[i]
+		prof.AutoActive, prof.ByDefault, prof.Reason = false, false, ""
+		if p.Settings.activates(prof.ID) || p.GlobalSettings.activates(prof.ID) {
+			prof.AutoActive, prof.Reason = true, "activeProfiles in settings.xml"
+		} else if prof.Activation != nil {
+			dir := prof.basedir
+			if dir == "" {
+				dir = p.RootPath
+			}
+			prof.AutoActive, prof.Reason = ctx.evaluate(*prof.Activation, dir)
+		}
+		if _, ok := activated[prof.declaredIn()]; prof.AutoActive && !ok {
+			activated[prof.declaredIn()] = prof.ID
+		}
+	}
+
+	for i := range p.Profiles {
+		prof := &p.Profiles[i]
+		if prof.AutoActive || prof.Activation == nil || !prof.Activation.ActiveByDefault {
+			continue
+		}
+		if other, ok := activated[prof.declaredIn()]; ok {
+			prof.Reason = fmt.Sprintf("activeByDefault, but %s is active", other)
+			continue
+		}
+		prof.AutoActive, prof.ByDefault, prof.Reason = true, true, "activeByDefault"
+	}
+}
+
+// declaredIn returns the first file declaring the profile
+func (p Profile) declaredIn() string {
+	if len(p.Sources) == 0 {
+		return ""
+	}
+	return p.Sources[0]
+}
+
+// evaluate reports whether the conditions of a profile declared in the POM
+// in dir are all met, and which they are. A profile with no conditions
+// besides activeByDefault isn't activated here.
+func (ctx ActivationContext) evaluate(a Activation, dir string) (bool, string) {
+	var reasons []string
+	if a.JDK != "" {
+		if ctx.JDK == "" || !jdkMatches(a.JDK, ctx.JDK) {
+			return false, ""
+		}
+		reasons = append(reasons, fmt.Sprintf("JDK %s matches %s", ctx.JDK, a.JDK))
+	}
+	if a.OS != nil {
+		if !ctx.osMatches(*a.OS) {
+			return false, ""
+		}
+		reasons = append(reasons, "OS "+describeOS(*a.OS))
+	}
+	if a.Property != nil && a.Property.Name != "" {
+		reason, ok := ctx.propertyMatches(*a.Property)
+		if !ok {
+			return false, ""
+		}
+		reasons = append(reasons, reason)
+	}
+	if a.File != nil && (a.File.Exists != "" || a.File.Missing != "") {
+		reason, ok := fileMatches(*a.File, dir)
+		if !ok {
+			return false, ""
+		}
+		reasons = append(reasons, reason)
+	}
+	return len(reasons) > 0, strings.Join(reasons, ", ")
+}
+
+// jdkMatches reports whether version satisfies a jdk condition: a version
+// prefix such as 17 or 1.8, a range such as [11,17), or either negated with !
+func jdkMatches(condition, version string) bool {
+	if negated, ok := strings.CutPrefix(condition, "!"); ok {
+		return !jdkMatches(negated, version)
+	}
+	if strings.HasPrefix(condition, "[") || strings.HasPrefix(condition, "(") {
+		return inVersionRanges(condition, version)
+	}
+	rest, ok := strings.CutPrefix(version, condition)
+	return ok && (rest == "" || !isDigit(rest[0]))
+}
+
+// rangePattern matches one range of a version range list
+var rangePattern = regexp.MustCompile(`([\[(])\s*([^,\])]*)\s*(?:,\s*([^\])]*))?\s*([\])])`)
+
+// inVersionRanges reports whether version is in any of the ranges
+func inVersionRanges(ranges, version string) bool {
+	for _, r := range rangePattern.FindAllStringSubmatch(ranges, -1) {
+		lower, upper := strings.TrimSpace(r[2]), strings.TrimSpace(r[3])
+		if !strings.Contains(r[0], ",") {
+			// [17] is exactly 17
+			upper = lower
+		}
+		if lower != "" {
+			c := compareVersions(version, lower)
+			if c < 0 || c == 0 && r[1] == "(" {
+				continue
+			}
+		}
+		if upper != "" {
+			c := compareVersions(version, upper)
+			if c > 0 || c == 0 && r[4] == ")" {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// compareVersions compares a version with a range bound number by number,
+// only as far as the bound goes, so 1.8.0_382 counts as 1.8 and 17.0.2 as 17
+func compareVersions(version, bound string) int {
+	split := func(v string) []int {
+		var numbers []int
+		for _, field := range strings.FieldsFunc(v, func(r rune) bool { return r < '0' || r > '9' }) {
+			n, _ := strconv.Atoi(field)
+			numbers = append(numbers, n)
+		}
+		return numbers
+	}
+	x, y := split(version), split(bound)
+	for i, n := range y {
+		var m int
+		if i < len(x) {
+			m = x[i]
+		}
+		if m != n {
+			if m < n {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// osMatches reports whether the operating system matches every part of an
+// os condition. Names and architectures are compared the way Java reports them.
+func (ctx ActivationContext) osMatches(o ActivationOS) bool {
+	name, arch := javaOSName(ctx.GOOS), javaOSArch(ctx.GOOS, ctx.GOARCH)
+	if o.Name != "" && !negatable(o.Name, func(v string) bool { return strings.EqualFold(v, name) }) {
+		return false
+	}
+	if o.Family != "" && !negatable(o.Family, func(v string) bool { return inOSFamily(ctx.GOOS, strings.ToLower(v)) }) {
+		return false
+	}
+	if o.Arch != "" && !negatable(o.Arch, func(v string) bool { return strings.EqualFold(v, arch) }) {
+		return false
+	}
+	// The OS version isn't known without a JVM
+	return o.Version == ""
+}
+
+// negatable applies match to a condition value, inverting it for a leading !
+func negatable(value string, match func(string) bool) bool {
+	if negated, ok := strings.CutPrefix(value, "!"); ok {
+		return !match(negated)
+	}
+	return match(value)
+}
+
+// javaOSName returns the os.name Java reports for goos
+func javaOSName(goos string) string {
+	switch goos {
+	case "darwin":
+		return "Mac OS X"
+	case "windows":
+		return "Windows"
+	case "linux":
+		return "Linux"
+	case "freebsd":
+		return "FreeBSD"
+	}
+	return goos
+}
+
+// javaOSArch returns the os.arch Java reports for goarch
+func javaOSArch(goos, goarch string) string {
+	switch goarch {
+	case "amd64":
+		if goos == "darwin" {
+			return "x86_64"
+		}
+		return "amd64"
+	case "arm64":
+		return "aarch64"
+	case "386":
+		return "x86"
+	}
+	return goarch
+}
+
+// inOSFamily reports whether goos belongs to one of Maven's OS families
+func inOSFamily(goos, family string) bool {
+	switch family {
+	case "windows", "winnt", "dos":
+		return goos == "windows"
+	case "mac":
+		return goos == "darwin"
+	case "unix":
+		return goos != "windows" && goos != "plan9"
+	}
+	return false
+}
+
+// describeOS summarises an os condition
+func describeOS(o ActivationOS) string {
+	var parts []string
+	for _, part := range []string{o.Family, o.Name, o.Arch} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// propertyMatches checks a property condition: !name is met when the
+// property is unset, a name alone when it is set, and a value when the
+// property equals it, or differs from it for !value. env.NAME names an
+// environment variable.
+func (ctx ActivationContext) propertyMatches(p ActivationProperty) (string, bool) {
+	if name, ok := strings.CutPrefix(p.Name, "!"); ok {
+		_, set := ctx.property(name)
+		return name + " is unset", !set
+	}
+
+	value, set := ctx.property(p.Name)
+	switch {
+	case p.Value == "":
+		return p.Name + " is set", set
+	case strings.HasPrefix(p.Value, "!"):
+		return fmt.Sprintf("%s is not %s", p.Name, p.Value[1:]), value != p.Value[1:]
+	default:
+		return fmt.Sprintf("%s=%s", p.Name, p.Value), set && value == p.Value
+	}
+}
+
+// property looks up a -D property, an env.NAME variable, or one of the
+// system properties known without a JVM
+func (ctx ActivationContext) property(name string) (string, bool) {
+	if value, ok := ctx.Properties[name]; ok {
+		return value, true
+	}
+	if variable, ok := strings.CutPrefix(name, "env."); ok && ctx.LookupEnv != nil {
+		return ctx.LookupEnv(variable)
+	}
+	switch name {
+	case "java.version":
+		return ctx.JDK, ctx.JDK != ""
+	case "os.name":
+		return javaOSName(ctx.GOOS), true
+	case "os.arch":
+		return javaOSArch(ctx.GOOS, ctx.GOARCH), true
+	}
+	return "", false
+}
+
+// fileMatches checks a file condition, with paths relative to dir
+func fileMatches(f ActivationFile, dir string) (string, bool) {
+	resolve := func(file string) string {
+		for _, expression := range []string{"${basedir}", "${project.basedir}"} {
+			file = strings.ReplaceAll(file, expression, dir)
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+		return file
+	}
+
+	if f.Exists != "" {
+		_, err := os.Stat(resolve(f.Exists))
+		return f.Exists + " exists", err == nil
+	}
+	_, err := os.Stat(resolve(f.Missing))
+	return f.Missing + " is missing", err != nil
+}
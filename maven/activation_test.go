@@ -0,0 +1,158 @@
+package maven
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJDKMatches(t *testing.T) {
+	tests := []struct {
+		condition, version string
+		want               bool
+	}{
+		{"17", "17.0.2", true},
+		{"1.8", "1.8.0_382", true},
+		{"1", "17.0.2", false},
+		{"!1.8", "21", true},
+		{"[11,)", "1.8.0_382", false},
+		{"[11,)", "21.0.1", true},
+		{"[11,17)", "17", false},
+		{"[11,17)", "17.0.2", false},
+		{"[17,)", "17.0.2", true},
+		{"[11,17)", "16.0.2", true},
+		{"(,1.8],[21,)", "1.8.0_382", true},
+		{"(,1.8],[21,)", "17.0.2", false},
+		{"[21]", "21", true},
+		{"!(,11)", "17", true},
+	}
+	for _, tt := range tests {
+		if got := jdkMatches(tt.condition, tt.version); got != tt.want {
+			t.Errorf("jdkMatches(%q, %q) = %v, want %v", tt.condition, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateProfiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"src/main/docker/Dockerfile": "FROM scratch\n"})
+
+	env := map[string]string{"CI": "true"}
+	ctx := ActivationContext{
+		JDK:        "21.0.2",
+		GOOS:       "linux",
+		GOARCH:     "arm64",
+		Properties: map[string]string{"release": "true", "db": "h2"},
+		LookupEnv: func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		},
+	}
+
+	tests := []struct {
+		activation Activation
+		want       bool
+		reason     string
+	}{
+		{Activation{JDK: "[17,)"}, true, "JDK 21.0.2 matches [17,)"},
+		{Activation{JDK: "1.8"}, false, ""},
+		{Activation{OS: &ActivationOS{Family: "unix", Arch: "aarch64"}}, true, "OS unix aarch64"},
+		{Activation{OS: &ActivationOS{Family: "!unix"}}, false, ""},
+		{Activation{OS: &ActivationOS{Name: "linux", Version: "6.1"}}, false, ""},
+		{Activation{Property: &ActivationProperty{Name: "release"}}, true, "release is set"},
+		{Activation{Property: &ActivationProperty{Name: "!skipDocs"}}, true, "skipDocs is unset"},
+		{Activation{Property: &ActivationProperty{Name: "db", Value: "postgres"}}, false, ""},
+		{Activation{Property: &ActivationProperty{Name: "db", Value: "!postgres"}}, true, "db is not postgres"},
+		{Activation{Property: &ActivationProperty{Name: "env.CI", Value: "true"}}, true, "env.CI=true"},
+		{Activation{File: &ActivationFile{Exists: "${basedir}/src/main/docker/Dockerfile"}}, true, "${basedir}/src/main/docker/Dockerfile exists"},
+		{Activation{File: &ActivationFile{Missing: "src/main/docker"}}, false, ""},
+		// Every condition must hold
+		{Activation{JDK: "21", Property: &ActivationProperty{Name: "nope"}}, false, ""},
+	}
+	for _, tt := range tests {
+		project := &Project{RootPath: dir, Profiles: []Profile{{ID: "p", Activation: &tt.activation}}}
+		project.EvaluateProfiles(ctx)
+		if got := project.Profiles[0]; got.AutoActive != tt.want || got.Reason != tt.reason {
+			t.Errorf("%+v: got active %v (%q), want %v (%q)", tt.activation, got.AutoActive, got.Reason, tt.want, tt.reason)
+		}
+	}
+}
+
+func TestProfileArgs_KeepsActiveByDefaultProfiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"pom.xml": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <profiles>
+    <profile><id>local</id><activation><activeByDefault>true</activeByDefault></activation></profile>
+    <profile><id>ci</id></profile>
+    <profile><id>docker</id><activation><property><name>env.MVN_TUI_TEST_DOCKER</name></property></activation></profile>
+  </profiles>
+</project>`})
+	t.Setenv("JAVA_HOME", "")
+
+	project, err := LoadProject(dir)
+	if err != nil {
+		t.Fatalf("Failed to load project: %v", err)
+	}
+	if local := project.Profiles[0]; !local.AutoActive || !local.ByDefault || local.Reason != "activeByDefault" {
+		t.Fatalf("Expected local to be active by default, got %+v", local)
+	}
+
+	args := func() string {
+		return strings.Join(BuildCommand(project, []string{"verify"}, BuildOptions{}).Args, " ")
+	}
+	if got := args(); got != "verify" {
+		t.Errorf("Expected Maven to activate local on its own, got %q", got)
+	}
+	project.ToggleProfile(1)
+	if got := args(); got != "-P local,ci verify" {
+		t.Errorf("Expected local to stay active beside ci, got %q", got)
+	}
+	project.ToggleProfile(0)
+	if got := args(); got != "-P !local,ci verify" || project.Profiles[0].Active() {
+		t.Errorf("Expected local to be disabled, got %q", got)
+	}
+
+	// A profile activated by its conditions turns activeByDefault ones off
+	t.Setenv("MVN_TUI_TEST_DOCKER", "1")
+	project.EvaluateProfiles(NewActivationContext(project, BuildOptions{}))
+	if local, docker := project.Profiles[0], project.Profiles[2]; local.AutoActive || !docker.AutoActive || local.Reason != "activeByDefault, but docker is active" {
+		t.Errorf("Expected docker to replace local, got %+v and %+v", local, docker)
+	}
+}
+
+func TestNewActivationContext_ValuelessPropertiesAreTrue(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"pom.xml": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <profiles>
+    <profile><id>release</id><activation><property><name>release</name><value>true</value></property></activation></profile>
+    <profile><id>empty</id><activation><property><name>docs</name><value>!true</value></property></activation></profile>
+  </profiles>
+</project>`})
+	t.Setenv("JAVA_HOME", "")
+
+	project, err := LoadProject(dir)
+	if err != nil {
+		t.Fatalf("Failed to load project: %v", err)
+	}
+	options := BuildOptions{Properties: []Property{
+		{Name: "release", Enabled: true},
+		{Name: "docs", HasValue: true, Enabled: true},
+	}}
+	ctx := NewActivationContext(project, options)
+	if ctx.Properties["release"] != "true" || ctx.Properties["docs"] != "" {
+		t.Errorf("Expected -Drelease to be true and -Ddocs= empty, got %q and %q", ctx.Properties["release"], ctx.Properties["docs"])
+	}
+
+	project.EvaluateProfiles(ctx)
+	if release := project.Profiles[0]; !release.AutoActive || release.Reason != "release=true" {
+		t.Errorf("Expected -Drelease to activate release, got %+v", release)
+	}
+	if empty := project.Profiles[1]; !empty.AutoActive {
+		t.Errorf("Expected -Ddocs= not to count as true, got %+v", empty)
+	}
+}
@@ -2,28 +2,80 @@ package maven
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
 	"strings"
 )
 
-// BuildOptions represents Maven build options
+// BuildOptions represents Maven build options. Options that contradict each
+// other may all be set; BuildCommand decides which apply.
 type BuildOptions struct {
-	SkipTests       bool
-	Offline         bool
-	UpdateSnapshots bool
-	Threads         string
-	Debug           bool // -X or --debug
-	Verbose         bool // -v or --verbose (deprecated but still works)
-	Quiet           bool // -q or --quiet
-	Errors          bool // -e or --errors (show full stack traces)
-	BatchMode       bool // -B or --batch-mode (non-interactive)
-	ShowVersion     bool // -V or --show-version
+	SkipTests          bool
+	SkipTestCompile    bool // -Dmaven.test.skip=true: don't compile tests either; implies SkipTests
+	Offline            bool
+	UpdateSnapshots    bool   // -U, ignored when offline
+	Threads            string // -T, such as 4 or 1C
+	AlsoMake           bool   // -am: also build the modules the selected ones depend on
+	AlsoMakeDependents bool   // -amd: also build the modules depending on the selected ones
+	ResumeFrom         string // -rf, a module as :artifactId or its path
+	FailStrategy       FailStrategy
+	NonRecursive       bool       // -N: build only the project in the current directory
+	Settings           string     // -s, an alternate user settings.xml
+	GlobalSettings     string     // -gs, an alternate global settings.xml
+	NoTransferProgress bool       // -ntp or --no-transfer-progress
+	Debug              bool       // -X or --debug
+	Verbose            bool       // -v or --verbose (deprecated but still works)
+	Quiet              bool       // -q or --quiet
+	Errors             bool       // -e or --errors (show full stack traces)
+	BatchMode          bool       // -B or --batch-mode (non-interactive)
+	ShowVersion        bool       // -V or --show-version
+	Properties         []Property // Extra -D system properties
+	JavaHome           string     // JDK that runs Maven; the inherited JAVA_HOME if empty
+}
+
+// FailStrategy is how the reactor carries on after a module fails
+type FailStrategy string
+
+const (
+	FailFast  FailStrategy = "fail-fast"   // -ff: stop at the first failure, as Maven does by default
+	FailAtEnd FailStrategy = "fail-at-end" // -fae: build what doesn't depend on a failed module, then fail
+	FailNever FailStrategy = "fail-never"  // -fn: never fail the build
+)
+
+// FailStrategies lists the strategies in the order the UI cycles through them
+var FailStrategies = []FailStrategy{"", FailFast, FailAtEnd, FailNever}
+
+// Flag returns the Maven option of the strategy, or "" for Maven's default
+// or an unknown strategy
+func (s FailStrategy) Flag() string {
+	switch s {
+	case FailFast:
+		return "-ff"
+	case FailAtEnd:
+		return "-fae"
+	case FailNever:
+		return "-fn"
+	}
+	return ""
+}
+
+// threadsPattern matches Maven's -T values, such as 4 or 1.5C
+var threadsPattern = regexp.MustCompile(`^\d+(\.\d+)?C?$`)
+
+// ValidThreads reports whether s is a thread count -T accepts, such as 4 or
+// 1C, and not zero
+func ValidThreads(s string) bool {
+	return threadsPattern.MatchString(s) && strings.Trim(s, "0.C") != ""
 }
 
 // Command represents a Maven command
 type Command struct {
 	Executable string
 	Args       []string
-	PrettyArgs string
+	PrettyArgs string   // Args quoted for a POSIX shell
+	Env        []string // Variables added to the inherited environment, as KEY=value
 }
 
 // BuildCommand constructs a Maven command from project state and options
@@ -31,16 +83,33 @@ func BuildCommand(project *Project, goals []string, options BuildOptions) Comman
 	args := []string{}
 
 	// Add enabled profiles
-	profiles := project.GetEnabledProfiles()
+	profiles := project.ProfileArgs()
 	if len(profiles) > 0 {
 		args = append(args, "-P", strings.Join(profiles, ","))
 	}
 
-	// Add selected modules (if not all selected)
-	selectedModules := project.GetSelectedModules()
-	if len(selectedModules) > 0 && len(selectedModules) < len(project.Modules) {
+	// Add selected modules (if not all selected), and what they depend on or
+	// what depends on them. Building non-recursively leaves no reactor to
+	// resume or to make more of.
+	selectedModules := project.SelectedProjects()
+	partial := len(selectedModules) > 0 && len(selectedModules) < len(project.Modules)
+	if partial {
 		args = append(args, "-pl", strings.Join(selectedModules, ","))
 	}
+	if partial && !options.NonRecursive {
+		if options.AlsoMake {
+			args = append(args, "-am")
+		}
+		if options.AlsoMakeDependents {
+			args = append(args, "-amd")
+		}
+	}
+	if options.ResumeFrom != "" && !options.NonRecursive {
+		args = append(args, "-rf", options.ResumeFrom)
+	}
+	if options.NonRecursive {
+		args = append(args, "-N")
+	}
 
 	// Add output control options (these should come early)
 	if options.Debug {
@@ -62,32 +131,175 @@ func BuildCommand(project *Project, goals []string, options BuildOptions) Comman
 	if options.ShowVersion {
 		args = append(args, "-V")
 	}
+	if options.NoTransferProgress {
+		args = append(args, "-ntp")
+	}
+	if options.Settings != "" {
+		args = append(args, "-s", options.Settings)
+	}
+	if options.GlobalSettings != "" {
+		args = append(args, "-gs", options.GlobalSettings)
+	}
+	if flag := options.FailStrategy.Flag(); flag != "" {
+		args = append(args, flag)
+	}
 
-	// Add build options
-	if options.SkipTests {
+	// Add build options. Skipping test compilation skips the tests too, and
+	// snapshots can't be updated offline.
+	if options.SkipTestCompile {
+		args = append(args, "-Dmaven.test.skip=true")
+	} else if options.SkipTests {
 		args = append(args, "-DskipTests")
 	}
 	if options.Offline {
 		args = append(args, "-o")
-	}
-	if options.UpdateSnapshots {
+	} else if options.UpdateSnapshots {
 		args = append(args, "-U")
 	}
 	if options.Threads != "" {
 		args = append(args, "-T", options.Threads)
 	}
 
+	// Add system properties in a stable order. Each is one argument, however
+	// its value is spaced or quoted.
+	properties := EnabledProperties(options.Properties)
+	sort.Slice(properties, func(i, j int) bool { return properties[i].Name < properties[j].Name })
+	for _, prop := range properties {
+		args = append(args, "-D"+prop.String())
+	}
+
 	// Add goals
 	args = append(args, goals...)
 
+	var env []string
+	if options.JavaHome != "" {
+		env = append(env, "JAVA_HOME="+options.JavaHome)
+	}
+
 	return Command{
 		Executable: project.Executable,
 		Args:       args,
-		PrettyArgs: strings.Join(args, " "),
+		PrettyArgs: JoinArgs(args),
+		Env:        env,
 	}
 }
 
 // String returns a string representation of the command
 func (c Command) String() string {
+	if len(c.Env) > 0 {
+		return fmt.Sprintf("%s %s %s", strings.Join(c.Env, " "), c.Executable, c.PrettyArgs)
+	}
 	return fmt.Sprintf("%s %s", c.Executable, c.PrettyArgs)
 }
+
+// WithResumeFrom returns the command resuming the reactor from module, e.g.
+// :billing-service, in place of any module it already resumed from. The
+// profiles, modules, options and goals stay as they were.
+func (c Command) WithResumeFrom(module string) Command {
+	args := make([]string, 0, len(c.Args)+2)
+	for i := 0; i < len(c.Args); i++ {
+		switch arg := c.Args[i]; {
+		case arg == "-rf" || arg == "--resume-from":
+			i++ // Drop its value too
+		case strings.HasPrefix(arg, "--resume-from="):
+		default:
+			args = append(args, arg)
+		}
+	}
+	args = append(args, "-rf", module)
+
+	c.Args = args
+	c.PrettyArgs = JoinArgs(args)
+	return c
+}
+
+// execCommand creates the process for the command, adding its variables to
+// the inherited environment
+func (c Command) execCommand() *exec.Cmd {
+	execCmd := exec.Command(c.Executable, c.Args...)
+	if len(c.Env) > 0 {
+		execCmd.Env = append(os.Environ(), c.Env...)
+	}
+	return execCmd
+}
+
+// safeWord matches words the shell takes literally without quoting
+var safeWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// QuoteArg returns s as a single POSIX shell word
+func QuoteArg(s string) string {
+	if safeWord.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// JoinArgs joins arguments into a line SplitArgs splits back into them
+func JoinArgs(args []string) string {
+	words := make([]string, len(args))
+	for i, arg := range args {
+		words[i] = QuoteArg(arg)
+	}
+	return strings.Join(words, " ")
+}
+
+// SplitArgs splits a line typed by the user into arguments the way a POSIX
+// shell would: spaces separate arguments, quotes keep them together and a
+// backslash escapes the next character, so -Dmsg="two words" stays one
+// argument. Variables and globs are not expanded.
+func SplitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune // The quote being read, or zero
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case quote == '"':
+			switch {
+			case r == '"':
+				quote = 0
+			case r == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]):
+				i++
+				current.WriteRune(runes[i])
+			default:
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			i++
+			current.WriteRune(runes[i])
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
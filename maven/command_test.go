@@ -0,0 +1,144 @@
+package maven
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitArgs(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{line: "clean install", want: []string{"clean", "install"}},
+		{line: "  -P dev,ci   verify ", want: []string{"-P", "dev,ci", "verify"}},
+		{line: `-Dmsg="two words" test`, want: []string{"-Dmsg=two words", "test"}},
+		{line: `-Dmsg='it''s' -Dq="say \"hi\" \n"`, want: []string{"-Dmsg=its", `-Dq=say "hi" \n`}},
+		{line: `'-Dmsg=it'\''s $HOME'`, want: []string{"-Dmsg=it's $HOME"}},
+		{line: `a\ b ""`, want: []string{"a b", ""}},
+		{line: "", want: nil},
+		{line: `-Dmsg="open`, wantErr: true},
+		{line: `trailing\`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := SplitArgs(tt.line)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("SplitArgs(%q): expected an error, got %q", tt.line, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("SplitArgs(%q): unexpected error %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitArgs(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestBuildCommand_QuotesProperties(t *testing.T) {
+	project := &Project{Executable: "mvn"}
+	options := BuildOptions{Properties: []Property{
+		{Name: "test", Value: "MyTest#should*", HasValue: true, Enabled: true},
+		{Name: "spring.profiles.active", Value: "dev", HasValue: true, Enabled: true},
+		{Name: "msg", Value: "it's done", HasValue: true, Enabled: true},
+		{Name: "skipITs", Enabled: true},
+	}}
+	cmd := BuildCommand(project, []string{"verify"}, options)
+
+	want := []string{"-Dmsg=it's done", "-DskipITs", "-Dspring.profiles.active=dev", "-Dtest=MyTest#should*", "verify"}
+	if !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("Args = %q, want %q", cmd.Args, want)
+	}
+	if want := `'-Dmsg=it'\''s done' -DskipITs -Dspring.profiles.active=dev '-Dtest=MyTest#should*' verify`; cmd.PrettyArgs != want {
+		t.Errorf("PrettyArgs = %s, want %s", cmd.PrettyArgs, want)
+	}
+	if args, err := SplitArgs(cmd.PrettyArgs); err != nil || !reflect.DeepEqual(args, cmd.Args) {
+		t.Errorf("Expected PrettyArgs to split back into Args, got %q (%v)", args, err)
+	}
+}
+
+func TestBuildCommand_KeepsExplicitlyEmptyProperties(t *testing.T) {
+	var properties []Property
+	for _, typed := range []string{"skipITs", "suffix=", "env=ci", "env=local"} {
+		prop, err := ParseProperty(typed)
+		if err != nil {
+			t.Fatalf("ParseProperty(%q): %v", typed, err)
+		}
+		properties = append(properties, prop)
+	}
+	properties = append(properties, Property{Name: "debug", Value: "true"})
+
+	cmd := BuildCommand(&Project{Executable: "mvn"}, []string{"verify"}, BuildOptions{Properties: properties})
+	if want := []string{"-Denv=local", "-DskipITs", "-Dsuffix=", "verify"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("Args = %q, want %q", cmd.Args, want)
+	}
+}
+
+func TestBuildCommand_ResolvesConflictingOptions(t *testing.T) {
+	modules := []Module{
+		{Name: "core", ArtifactID: "core", Selected: true},
+		{Name: "web", ArtifactID: "web"},
+	}
+	tests := []struct {
+		name    string
+		modules []Module
+		options BuildOptions
+		want    string
+	}{
+		{"also make with a selection", modules, BuildOptions{AlsoMake: true, AlsoMakeDependents: true}, "-pl :core -am -amd"},
+		{"also make without a selection", nil, BuildOptions{AlsoMake: true, AlsoMakeDependents: true}, ""},
+		{"non-recursive drops the reactor options", modules, BuildOptions{AlsoMake: true, ResumeFrom: ":core", NonRecursive: true}, "-pl :core -N"},
+		{"resume from", nil, BuildOptions{ResumeFrom: ":web", FailStrategy: FailAtEnd}, "-rf :web -fae"},
+		{"fail strategies", nil, BuildOptions{FailStrategy: FailNever}, "-fn"},
+		{"unknown fail strategy", nil, BuildOptions{FailStrategy: "sometimes"}, ""},
+		{"debug wins over verbose and quiet", nil, BuildOptions{Debug: true, Verbose: true, Quiet: true}, "-X"},
+		{"verbose wins over quiet", nil, BuildOptions{Verbose: true, Quiet: true}, "-v"},
+		{"skipping test compilation skips tests", nil, BuildOptions{SkipTests: true, SkipTestCompile: true}, "-Dmaven.test.skip=true"},
+		{"no snapshot updates offline", nil, BuildOptions{Offline: true, UpdateSnapshots: true}, "-o"},
+		{"settings and transfer progress", nil, BuildOptions{Settings: "ci/settings.xml", GlobalSettings: "/etc/maven/settings.xml", NoTransferProgress: true, Threads: "1C"}, "-ntp -s ci/settings.xml -gs /etc/maven/settings.xml -T 1C"},
+	}
+	for _, tt := range tests {
+		project := &Project{Executable: "mvn", Modules: append([]Module(nil), tt.modules...)}
+		cmd := BuildCommand(project, nil, tt.options)
+		if got := strings.Join(cmd.Args, " "); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCommand_WithResumeFrom(t *testing.T) {
+	cmd := Command{
+		Executable: "mvn",
+		Args:       []string{"-P", "ci", "-pl", ":core,:web", "-rf", ":core", "--resume-from=:api", "-q", "-Dmsg=two words", "verify"},
+		Env:        []string{"JAVA_HOME=/opt/jdk-21"},
+	}
+	resumed := cmd.WithResumeFrom(":web")
+
+	want := []string{"-P", "ci", "-pl", ":core,:web", "-q", "-Dmsg=two words", "verify", "-rf", ":web"}
+	if !reflect.DeepEqual(resumed.Args, want) {
+		t.Errorf("Args = %q, want %q", resumed.Args, want)
+	}
+	if want := `-P ci -pl :core,:web -q '-Dmsg=two words' verify -rf :web`; resumed.PrettyArgs != want {
+		t.Errorf("PrettyArgs = %s, want %s", resumed.PrettyArgs, want)
+	}
+	if resumed.Executable != "mvn" || !reflect.DeepEqual(resumed.Env, cmd.Env) {
+		t.Errorf("Expected the executable and environment to be kept, got %+v", resumed)
+	}
+	if len(cmd.Args) != 10 {
+		t.Errorf("Expected the original command to be unchanged, got %q", cmd.Args)
+	}
+}
+
+func TestValidThreads(t *testing.T) {
+	for value, want := range map[string]bool{"1": true, "4": true, "1C": true, "1.5C": true, "0": false, "0C": false, "C": false, "four": false, "-2": false} {
+		if got := ValidThreads(value); got != want {
+			t.Errorf("ValidThreads(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
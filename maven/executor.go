@@ -1,63 +1,179 @@
 package maven
 
 import (
-	"bufio"
+	"bytes"
 	"context"
-	"io"
+	"fmt"
 	"os"
 	"os/exec"
+	"sync"
 	"time"
 )
 
+// DefaultGracePeriod is how long a cancelled build may take to exit after
+// SIGINT before its process group is killed
+const DefaultGracePeriod = 5 * time.Second
+
+// StopStage records how a cancelled command was brought down
+type StopStage int
+
+const (
+	NotStopped  StopStage = iota // The command ran to completion
+	Interrupted                  // The process group exited after SIGINT
+	Killed                       // The grace period expired and the group was sent SIGKILL
+)
+
+// String returns a short description of the stage
+func (s StopStage) String() string {
+	switch s {
+	case Interrupted:
+		return "interrupted (SIGINT)"
+	case Killed:
+		return "killed (SIGKILL)"
+	default:
+		return "not stopped"
+	}
+}
+
 // ExecutionResult represents the result of executing a Maven command
 type ExecutionResult struct {
 	Command   Command
 	ExitCode  int
 	Duration  time.Duration
 	StartTime time.Time
-	Output    []string
+	Log       *LogStore
+	Stopped   StopStage // How the command was stopped if it was cancelled
 	Error     error
+
+	// ResumeFrom is the module a failed reactor build can be resumed from,
+	// e.g. :billing-service, or empty if there is none. The executor leaves
+	// it to whoever follows the build with a ReactorProgress.
+	ResumeFrom string
+}
+
+// OutputStream identifies which pipe a line of output came from
+type OutputStream int
+
+const (
+	Stdout OutputStream = iota
+	Stderr
+)
+
+// String returns the conventional name of the stream
+func (s OutputStream) String() string {
+	if s == Stderr {
+		return "stderr"
+	}
+	return "stdout"
+}
+
+// OutputLine is a single line of process output tagged with its stream
+type OutputLine struct {
+	Stream OutputStream
+	Text   string
 }
 
-// OutputHandler is called for each line of output
-type OutputHandler func(line string)
+// OutputHandler is called for each line of output, in the order the lines were received
+type OutputHandler func(line OutputLine)
+
+// ExecuteOptions controls where Execute stores output and how it reports it
+type ExecuteOptions struct {
+	Log           *LogStore     // Destination for output lines; a new store is created if nil
+	OutputHandler OutputHandler // Called for each line after it is stored
+	GracePeriod   time.Duration // Time between SIGINT and SIGKILL on cancel; DefaultGracePeriod if zero
+}
 
 // Execute runs a Maven command and streams output
 func Execute(ctx context.Context, cmd Command, workDir string, outputHandler OutputHandler) (*ExecutionResult, error) {
+	return ExecuteWithOptions(ctx, cmd, workDir, ExecuteOptions{OutputHandler: outputHandler})
+}
+
+// ExecuteWithOptions runs a Maven command and streams output.
+// Stdout and stderr are merged into a single ordered line channel that is
+// consumed by one goroutine, so the handler is never called concurrently and
+// result.Log matches the order the handler saw. It only returns once both
+// pipes have been drained.
+//
+// Maven runs in its own process group. Cancelling ctx sends SIGINT to the
+// whole group, and if it has not exited after the grace period the group is
+// killed with SIGKILL, so forked test JVMs and application servers don't
+// outlive the build.
+func ExecuteWithOptions(ctx context.Context, cmd Command, workDir string, opts ExecuteOptions) (*ExecutionResult, error) {
+	log := opts.Log
+	if log == nil {
+		log = NewLogStore(DefaultLogWindow)
+	}
+
 	result := &ExecutionResult{
 		Command:   cmd,
 		StartTime: time.Now(),
-		Output:    []string{},
+		Log:       log,
+	}
+
+	gracePeriod := opts.GracePeriod
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultGracePeriod
 	}
 
-	execCmd := exec.CommandContext(ctx, cmd.Executable, cmd.Args...)
+	execCmd := cmd.execCommand()
 	execCmd.Dir = workDir
+	startInProcessGroup(execCmd)
 
-	// Connect stdin to allow interactive input (e.g., Scanner in Java)
-	execCmd.Stdin = os.Stdin
+	// Stdin is left unconnected: a background process group reading from the
+	// terminal would be stopped, and the TUI owns the terminal anyway.
+	// Interactive programs run under a pseudo-terminal instead, through
+	// PTYSession or PTYCommand.
 
-	stdout, err := execCmd.StdoutPipe()
-	if err != nil {
-		result.Error = err
-		return result, err
-	}
+	lines := make(chan OutputLine, 256)
+	stdout := &lineWriter{stream: Stdout, lines: lines}
+	stderr := &lineWriter{stream: Stderr, lines: lines}
+	execCmd.Stdout = stdout
+	execCmd.Stderr = stderr
 
-	stderr, err := execCmd.StderrPipe()
-	if err != nil {
-		result.Error = err
-		return result, err
-	}
+	// Collect output on a single goroutine to keep ordering and avoid races
+	collected := make(chan struct{})
+	go func() {
+		defer close(collected)
+		for line := range lines {
+			log.Append(line.Text)
+			if opts.OutputHandler != nil {
+				opts.OutputHandler(line)
+			}
+		}
+	}()
 
 	if err := execCmd.Start(); err != nil {
+		close(lines)
+		<-collected
 		result.Error = err
 		return result, err
 	}
 
-	// Stream output
-	go streamOutput(stdout, outputHandler, &result.Output)
-	go streamOutput(stderr, outputHandler, &result.Output)
+	// Escalate from SIGINT to SIGKILL if the context is cancelled
+	exited := make(chan struct{})
+	stopped := make(chan StopStage, 1)
+	go func() {
+		stopped <- stopOnCancel(ctx, execCmd.Process, gracePeriod, exited)
+	}()
+
+	// Wait returns only after the copying goroutines have finished writing
+	// into the line writers, so no more lines can arrive once it returns
+	err := execCmd.Wait()
+	close(exited)
+	result.Stopped = <-stopped
+
+	stdout.Flush()
+	stderr.Flush()
+	close(lines)
+	<-collected
+
+	switch result.Stopped {
+	case Interrupted:
+		log.Append("", "Build cancelled: process group exited after SIGINT")
+	case Killed:
+		log.Append("", fmt.Sprintf("Build cancelled: process group killed with SIGKILL after %v grace period", gracePeriod))
+	}
 
-	err = execCmd.Wait()
 	result.Duration = time.Since(result.StartTime)
 
 	if err != nil {
@@ -73,46 +189,70 @@ func Execute(ctx context.Context, cmd Command, workDir string, outputHandler Out
 	return result, nil
 }
 
-func streamOutput(r io.Reader, handler OutputHandler, output *[]string) {
-	scanner := bufio.NewScanner(r)
-	for scanner.Scan() {
-		line := scanner.Text()
-		*output = append(*output, line)
-		if handler != nil {
-			handler(line)
-		}
+// stopOnCancel waits for ctx to be cancelled and then brings down the
+// process group, first with SIGINT and, once the grace period expires, with
+// SIGKILL. It returns as soon as exited is closed.
+func stopOnCancel(ctx context.Context, p *os.Process, gracePeriod time.Duration, exited <-chan struct{}) StopStage {
+	select {
+	case <-exited:
+		return NotStopped
+	case <-ctx.Done():
 	}
-}
 
-// ExecuteInteractive runs a Maven command in the foreground with full stdin/stdout/stderr access
-// This is used for interactive commands that need user input (e.g., programs using Scanner)
-func ExecuteInteractive(cmd Command, workDir string) (*ExecutionResult, error) {
-	result := &ExecutionResult{
-		Command:   cmd,
-		StartTime: time.Now(),
-		Output:    []string{},
-	}
+	interruptProcessGroup(p)
 
-	execCmd := exec.Command(cmd.Executable, cmd.Args...)
-	execCmd.Dir = workDir
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
 
-	// Connect stdin, stdout, and stderr directly to the terminal
-	execCmd.Stdin = os.Stdin
-	execCmd.Stdout = os.Stdout
-	execCmd.Stderr = os.Stderr
+	select {
+	case <-exited:
+		// Reap any children that ignored the interrupt
+		killProcessGroup(p)
+		return Interrupted
+	case <-timer.C:
+		killProcessGroup(p)
+		<-exited
+		return Killed
+	}
+}
 
-	err := execCmd.Run()
-	result.Duration = time.Since(result.StartTime)
+// lineWriter splits written bytes into lines and forwards them to a channel
+type lineWriter struct {
+	mu      sync.Mutex
+	stream  OutputStream
+	lines   chan<- OutputLine
+	partial []byte
+}
 
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			result.ExitCode = exitErr.ExitCode()
-		} else {
-			result.Error = err
+// Write implements io.Writer
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		idx := bytes.IndexByte(w.partial, '\n')
+		if idx < 0 {
+			break
 		}
-	} else {
-		result.ExitCode = 0
+		w.emit(w.partial[:idx])
+		w.partial = w.partial[idx+1:]
 	}
+	return len(p), nil
+}
 
-	return result, nil
+// Flush emits any trailing output that was not terminated by a newline
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.partial) > 0 {
+		w.emit(w.partial)
+		w.partial = nil
+	}
+}
+
+func (w *lineWriter) emit(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	w.lines <- OutputLine{Stream: w.stream, Text: string(line)}
 }
@@ -2,13 +2,19 @@ package maven
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 	"time"
 )
 
 func TestExecute(t *testing.T) {
+	if _, err := exec.LookPath("mvn"); err != nil {
+		t.Skip("mvn not found on PATH")
+	}
+
 	// Create a temporary test project
 	tmpDir := t.TempDir()
 
@@ -62,9 +68,9 @@ public class App {
 	defer cancel()
 
 	var outputLines []string
-	result, err := Execute(ctx, cmd, tmpDir, func(line string) {
-		outputLines = append(outputLines, line)
-		t.Logf("Output: %s", line)
+	result, err := Execute(ctx, cmd, tmpDir, func(line OutputLine) {
+		outputLines = append(outputLines, line.Text)
+		t.Logf("Output: %s", line.Text)
 	})
 
 	if err != nil && result.Error == nil {
@@ -77,18 +83,19 @@ public class App {
 
 	t.Logf("Exit code: %d", result.ExitCode)
 	t.Logf("Duration: %v", result.Duration)
-	t.Logf("Output lines: %d", len(result.Output))
+	t.Logf("Output lines: %d", result.Log.Len())
 
 	// Maven validate should succeed
 	if result.ExitCode != 0 {
 		t.Errorf("Expected exit code 0, got %d", result.ExitCode)
-		for _, line := range result.Output {
+		lines, _ := result.Log.All()
+		for _, line := range lines {
 			t.Logf("  %s", line)
 		}
 	}
 
 	// Should have captured some output
-	if len(result.Output) == 0 {
+	if result.Log.Len() == 0 {
 		t.Error("Expected some output, got none")
 	}
 }
@@ -143,3 +150,143 @@ func TestExecuteCancel(t *testing.T) {
 
 	t.Logf("Cancellation test completed in %v with exit code %d", duration, result.ExitCode)
 }
+
+// writeFakeMaven creates an executable shell script that stands in for mvn
+func writeFakeMaven(t *testing.T, dir, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, "fake-mvn")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("Failed to write fake Maven script: %v", err)
+	}
+	return path
+}
+
+func TestExecuteCollectsInterleavedStreams(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Write heavily to both pipes at once to provoke races in output collection
+	fakeMvn := writeFakeMaven(t, tmpDir, `
+i=1
+while [ $i -le 500 ]; do
+  echo "[INFO] out $i"
+  echo "[WARNING] err $i" >&2
+  i=$((i+1))
+done
+printf "[INFO] no trailing newline"
+exit 3
+`)
+
+	var handled []OutputLine
+	result, err := Execute(context.Background(), Command{Executable: fakeMvn}, tmpDir, func(line OutputLine) {
+		handled = append(handled, line)
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	if result.ExitCode != 3 {
+		t.Errorf("Expected exit code 3, got %d", result.ExitCode)
+	}
+
+	output, err := result.Log.All()
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+
+	// 500 lines per stream plus the unterminated final line
+	if len(output) != 1001 {
+		t.Fatalf("Expected 1001 lines, got %d", len(output))
+	}
+	if len(handled) != len(output) {
+		t.Fatalf("Handler saw %d lines but result has %d", len(handled), len(output))
+	}
+
+	// The handler and the stored log must agree on the merged order
+	for i, line := range handled {
+		if output[i] != line.Text {
+			t.Fatalf("Line %d differs: handler %q, result %q", i, line.Text, output[i])
+		}
+	}
+
+	// Each stream must keep its own ordering and be tagged correctly
+	nextOut, nextErr := 1, 1
+	for _, line := range handled {
+		switch line.Stream {
+		case Stdout:
+			if line.Text == "[INFO] no trailing newline" {
+				continue
+			}
+			if want := fmt.Sprintf("[INFO] out %d", nextOut); line.Text != want {
+				t.Fatalf("Expected stdout line %q, got %q", want, line.Text)
+			}
+			nextOut++
+		case Stderr:
+			if want := fmt.Sprintf("[WARNING] err %d", nextErr); line.Text != want {
+				t.Fatalf("Expected stderr line %q, got %q", want, line.Text)
+			}
+			nextErr++
+		}
+	}
+	if nextOut != 501 || nextErr != 501 {
+		t.Errorf("Expected 500 lines per stream, got stdout=%d stderr=%d", nextOut-1, nextErr-1)
+	}
+
+	if last := handled[len(handled)-1]; last.Text != "[INFO] no trailing newline" {
+		t.Errorf("Expected unterminated line to be flushed last, got %q", last.Text)
+	}
+}
+
+func TestExecuteDrainsOutputBeforeReturning(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Exit immediately after a burst of output; every line must be collected
+	fakeMvn := writeFakeMaven(t, tmpDir, `
+seq 1 2000
+echo "done" >&2
+`)
+
+	for run := 0; run < 5; run++ {
+		result, err := Execute(context.Background(), Command{Executable: fakeMvn}, tmpDir, nil)
+		if err != nil {
+			t.Fatalf("Execute returned error: %v", err)
+		}
+		if result.Log.Len() != 2001 {
+			t.Fatalf("Run %d: expected 2001 lines, got %d", run, result.Log.Len())
+		}
+	}
+}
+
+func TestExecuteWithOptionsSpillsToProvidedLog(t *testing.T) {
+	tmpDir := t.TempDir()
+	fakeMvn := writeFakeMaven(t, tmpDir, "seq 1 100\n")
+
+	log := NewLogStore(10)
+	defer log.Close()
+	log.Append("Executing: fake-mvn")
+
+	result, err := ExecuteWithOptions(context.Background(), Command{Executable: fakeMvn}, tmpDir, ExecuteOptions{Log: log})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if result.Log != log {
+		t.Fatal("Expected result to reference the provided log store")
+	}
+
+	lines, err := log.All()
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	if len(lines) != 101 || lines[0] != "Executing: fake-mvn" || lines[1] != "1" || lines[100] != "100" {
+		t.Errorf("Unexpected log contents: %d lines, first %q, last %q", len(lines), lines[0], lines[len(lines)-1])
+	}
+}
+
+func TestExecuteStartFailure(t *testing.T) {
+	result, err := Execute(context.Background(), Command{Executable: "/nonexistent/mvn"}, t.TempDir(), nil)
+	if err == nil {
+		t.Fatal("Expected an error for a missing executable")
+	}
+	if result == nil || result.Error == nil {
+		t.Error("Expected result.Error to be set")
+	}
+}
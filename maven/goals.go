@@ -0,0 +1,100 @@
+package maven
+
+import (
+	"sort"
+	"strings"
+)
+
+// LifecyclePhases are the phases of Maven's clean, default and site
+// lifecycles, in order
+var LifecyclePhases = []string{
+	"pre-clean", "clean", "post-clean",
+	"validate", "initialize", "generate-sources", "process-sources",
+	"generate-resources", "process-resources", "compile", "process-classes",
+	"generate-test-sources", "process-test-sources", "generate-test-resources",
+	"process-test-resources", "test-compile", "process-test-classes", "test",
+	"prepare-package", "package", "pre-integration-test", "integration-test",
+	"post-integration-test", "verify", "install", "deploy",
+	"pre-site", "site", "post-site", "site-deploy",
+}
+
+// pluginGoals lists the goals of well-known plugins by prefix. Plugins not
+// listed still offer their help goal.
+var pluginGoals = map[string][]string{
+	"clean":       {"clean"},
+	"compiler":    {"compile", "testCompile"},
+	"surefire":    {"test"},
+	"failsafe":    {"integration-test", "verify"},
+	"jar":         {"jar", "test-jar"},
+	"war":         {"war", "exploded"},
+	"install":     {"install", "install-file"},
+	"deploy":      {"deploy", "deploy-file"},
+	"resources":   {"resources", "testResources", "copy-resources"},
+	"source":      {"jar", "jar-no-fork", "test-jar"},
+	"javadoc":     {"javadoc", "jar", "aggregate"},
+	"site":        {"site", "run", "stage"},
+	"dependency":  {"tree", "analyze", "resolve", "resolve-plugins", "list", "copy-dependencies", "go-offline", "purge-local-repository", "sources"},
+	"help":        {"effective-pom", "effective-settings", "active-profiles", "describe", "evaluate", "system"},
+	"versions":    {"display-dependency-updates", "display-plugin-updates", "display-property-updates", "display-parent-updates", "use-latest-releases", "set", "commit", "revert"},
+	"enforcer":    {"enforce", "display-info"},
+	"release":     {"prepare", "perform", "rollback", "clean"},
+	"shade":       {"shade"},
+	"assembly":    {"single"},
+	"exec":        {"java", "exec"},
+	"spring-boot": {"run", "start", "stop", "repackage", "build-image", "build-info"},
+	"jacoco":      {"prepare-agent", "report", "check"},
+	"spotless":    {"apply", "check"},
+	"checkstyle":  {"check", "checkstyle"},
+	"pmd":         {"check", "pmd", "cpd-check"},
+	"spotbugs":    {"check", "spotbugs", "gui"},
+	"flyway":      {"migrate", "info", "validate", "clean", "repair"},
+	"liquibase":   {"update", "status", "rollback"},
+	"quarkus":     {"dev", "build", "test"},
+	"jib":         {"build", "dockerBuild", "buildTar"},
+}
+
+// prefixPlugins are resolved by prefix without being declared in the POM
+var prefixPlugins = []string{"dependency", "help", "versions"}
+
+// PluginGoals returns prefix:goal pairs for the plugins declared in the
+// project and the ones Maven resolves by prefix on its own, sorted
+func (p *Project) PluginGoals() []string {
+	prefixes := append([]string(nil), prefixPlugins...)
+	for _, plugin := range p.Plugins {
+		prefixes = append(prefixes, pluginPrefix(plugin.ArtifactID))
+	}
+
+	seen := make(map[string]bool)
+	var goals []string
+	for _, prefix := range prefixes {
+		known, ok := pluginGoals[prefix]
+		if !ok {
+			known = []string{"help"}
+		}
+		for _, goal := range known {
+			name := prefix + ":" + goal
+			if !seen[name] {
+				seen[name] = true
+				goals = append(goals, name)
+			}
+		}
+	}
+	sort.Strings(goals)
+	return goals
+}
+
+// CompleteGoal returns the candidates that start with word, in the order
+// given and without duplicates
+func CompleteGoal(word string, candidates ...[]string) []string {
+	seen := make(map[string]bool)
+	var matches []string
+	for _, list := range candidates {
+		for _, candidate := range list {
+			if strings.HasPrefix(candidate, word) && !seen[candidate] {
+				seen[candidate] = true
+				matches = append(matches, candidate)
+			}
+		}
+	}
+	return matches
+}
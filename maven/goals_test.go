@@ -0,0 +1,83 @@
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestProject_PluginGoals(t *testing.T) {
+	dir := t.TempDir()
+	pom := `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0.0</version>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>3.2.5</version>
+      </plugin>
+      <plugin>
+        <groupId>org.springframework.boot</groupId>
+        <artifactId>spring-boot-maven-plugin</artifactId>
+      </plugin>
+      <plugin>
+        <groupId>com.example</groupId>
+        <artifactId>acme-maven-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`
+	if err := os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(pom), 0644); err != nil {
+		t.Fatalf("Failed to write pom.xml: %v", err)
+	}
+	project, err := LoadProject(dir)
+	if err != nil {
+		t.Fatalf("Failed to load project: %v", err)
+	}
+
+	want := []Plugin{
+		{GroupID: "org.apache.maven.plugins", ArtifactID: "maven-surefire-plugin", Version: "3.2.5"},
+		{GroupID: "org.springframework.boot", ArtifactID: "spring-boot-maven-plugin"},
+		{GroupID: "com.example", ArtifactID: "acme-maven-plugin"},
+	}
+	if !reflect.DeepEqual(project.Plugins, want) {
+		t.Errorf("Plugins = %+v, want %+v", project.Plugins, want)
+	}
+
+	goals := project.PluginGoals()
+	for _, goal := range []string{"surefire:test", "spring-boot:run", "acme:help", "dependency:tree", "versions:display-dependency-updates"} {
+		if !slices.Contains(goals, goal) {
+			t.Errorf("Expected %s among %v", goal, goals)
+		}
+	}
+	if slices.Contains(goals, "jacoco:report") {
+		t.Error("Expected goals only for plugins the project declares")
+	}
+}
+
+func TestCompleteGoal(t *testing.T) {
+	used := []string{"dependency:tree", "package"}
+	plugins := []string{"dependency:analyze", "dependency:tree"}
+
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"pack", []string{"package"}},
+		{"dep", []string{"dependency:tree", "deploy", "dependency:analyze"}},
+		{"pre-", []string{"pre-clean", "pre-integration-test", "pre-site"}},
+		{"nothing", nil},
+	}
+	for _, tt := range tests {
+		if got := CompleteGoal(tt.word, used, LifecyclePhases, plugins); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("CompleteGoal(%q) = %v, want %v", tt.word, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,448 @@
+package maven
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// LogEvent is a structured event recognised in Maven output
+type LogEvent interface {
+	logEvent()
+}
+
+// ReactorOrderEvent lists the modules of the reactor in build order, from the
+// "Reactor Build Order:" block Maven prints before a multi-module build
+type ReactorOrderEvent struct {
+	Modules []string // Project names as printed by Maven
+}
+
+// ModuleStartEvent marks the start of a module in the reactor
+// ([INFO] Building core 1.0-SNAPSHOT [2/5])
+type ModuleStartEvent struct {
+	Name       string // Project name as printed by Maven
+	Version    string
+	GroupID    string // From the "< groupId:artifactId >" banner, when present
+	ArtifactID string
+	Index      int // Position in the reactor, starting at 1 (0 if not printed)
+	Total      int // Number of modules in the reactor (0 if not printed)
+}
+
+// ModuleStatus is the outcome of a module in the Reactor Summary
+type ModuleStatus string
+
+const (
+	ModuleSuccess ModuleStatus = "SUCCESS"
+	ModuleFailure ModuleStatus = "FAILURE"
+	ModuleSkipped ModuleStatus = "SKIPPED"
+)
+
+// ModuleFinishEvent is a module's line in the Reactor Summary
+type ModuleFinishEvent struct {
+	Name     string
+	Status   ModuleStatus
+	Duration time.Duration
+}
+
+// PluginExecutionEvent is a plugin execution header
+// (--- maven-compiler-plugin:3.11.0:compile (default-compile) @ core ---)
+type PluginExecutionEvent struct {
+	Plugin      string // Plugin artifactId or prefix
+	Version     string
+	Goal        string
+	ExecutionID string
+	Module      string // ArtifactId of the module the plugin runs in
+}
+
+// Severity is the level of a problem reported by Maven
+type Severity string
+
+const (
+	SeverityError   Severity = "ERROR"
+	SeverityWarning Severity = "WARNING"
+)
+
+// ProblemEvent is an [ERROR] or [WARNING] line, with the source location
+// when the message points at a file
+type ProblemEvent struct {
+	Severity Severity
+	Message  string
+	File     string
+	Line     int
+	Column   int
+	Module   string // ArtifactId of the module being built, if known
+}
+
+// TestFailureEvent is a failing test from the Surefire or Failsafe results
+// ([ERROR]   InvoiceTest.rounding:42 expected: <1.00> but was: <0.99>)
+type TestFailureEvent struct {
+	Class   string // As printed, usually the simple class name
+	Method  string // Empty for failures outside a test method
+	Line    int    // Line in the test class, if reported
+	Message string
+	Error   bool   // True for errors (unexpected exceptions), false for assertion failures
+	Module  string // ArtifactId of the module being built, if known
+}
+
+// DownloadEvent reports artifact transfer activity
+type DownloadEvent struct {
+	Done       bool   // True for "Downloaded from", false for "Downloading from"
+	Repository string // Repository ID, e.g. "central"
+	URL        string
+	Size       string // Transferred size, e.g. "2.3 kB" (Done only)
+	Rate       string // Transfer rate, e.g. "45 kB/s" (Done only)
+	Progress   string // Raw progress text from "Progress (n): ..." lines
+}
+
+// ResumeEvent is the module Maven suggests resuming a failed reactor build
+// from ([ERROR]   mvn <args> -rf :billing-service)
+type ResumeEvent struct {
+	Module string // The -rf argument, usually :artifactId
+}
+
+// BuildResultEvent is the final BUILD SUCCESS/FAILURE block
+type BuildResultEvent struct {
+	Success    bool
+	TotalTime  time.Duration
+	FinishedAt string
+}
+
+func (ReactorOrderEvent) logEvent()    {}
+func (ModuleStartEvent) logEvent()     {}
+func (ModuleFinishEvent) logEvent()    {}
+func (PluginExecutionEvent) logEvent() {}
+func (ProblemEvent) logEvent()         {}
+func (TestFailureEvent) logEvent()     {}
+func (DownloadEvent) logEvent()        {}
+func (ResumeEvent) logEvent()          {}
+func (BuildResultEvent) logEvent()     {}
+
+var (
+	logANSIPattern        = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+	logLevelPattern       = regexp.MustCompile(`^\[(INFO|WARNING|WARN|ERROR|DEBUG)\] ?(.*)$`)
+	logCoordinatesPattern = regexp.MustCompile(`^-+< ([^:\s]+):([^:\s]+) >-+$`)
+	logBuildingPattern    = regexp.MustCompile(`^Building ([^:]+?) (\S+?)(?:\s+\[(\d+)/(\d+)\])?$`)
+	logPluginPattern      = regexp.MustCompile(`^--- ([\w.-]+):([\w.-]+):([\w.-]+) (?:\(([^)]*)\) )?@ ([\w.-]+) ---$`)
+	logOrderEntryPattern  = regexp.MustCompile(`^(.+?)(?:\s+\[[\w-]+\])?$`)
+	logSummaryPattern     = regexp.MustCompile(`^(.+?) [. ]*\s(SUCCESS|FAILURE|SKIPPED)(?: \[\s*(.+?)\])?$`)
+	logDownloadPattern    = regexp.MustCompile(`^(Downloading|Downloaded) from ([^:]+): (\S+)(?: \((.+?)(?: at (.+?))?\))?$`)
+	logProgressPattern    = regexp.MustCompile(`^Progress \(\d+\): (.+)$`)
+	logTotalTimePattern   = regexp.MustCompile(`^Total time:\s+(.+)$`)
+	logFinishedAtPattern  = regexp.MustCompile(`^Finished at:\s+(.+)$`)
+	logTestEntryPattern   = regexp.MustCompile(`^([\w$.]+?)(?::(\d+))?\s+(.*)$`)
+	logResumePattern      = regexp.MustCompile(`^mvn .* -rf (\S+)$`)
+
+	// javac style: /path/Foo.java:[42,17] message or /path/Foo.java:[42] message
+	logJavacLocationPattern = regexp.MustCompile(`^(.+?\.\w+):\[(\d+)(?:,(\d+))?\] (.*)$`)
+	// Generic style used by kotlinc, checkstyle and others: /path/Foo.kt:42:17: message
+	logColonLocationPattern = regexp.MustCompile(`^(?:file://)?(.+?\.\w+):(\d+)(?::(\d+))?:? (.*)$`)
+)
+
+// LogParser turns a stream of Maven output lines into typed events.
+// It keeps the state needed to attribute problems to modules and to
+// assemble multi-line blocks, so feed it every line in order.
+type LogParser struct {
+	module          string // ArtifactId of the module currently being built
+	pendingGroup    string // Coordinates from the banner preceding "Building ..."
+	pendingArtifact string
+	inOrder         bool
+	order           []string
+	inSummary       bool
+	inTests         string // "Failures" or "Errors" while reading test results
+	result          *BuildResultEvent
+	resultEmitted   bool
+}
+
+// NewLogParser creates a parser for a single build's output
+func NewLogParser() *LogParser {
+	return &LogParser{}
+}
+
+// Parse consumes one line of output and returns the events it completes
+func (p *LogParser) Parse(line string) []LogEvent {
+	line = logANSIPattern.ReplaceAllString(line, "")
+	line = strings.TrimRight(line, " \r")
+
+	level, msg := "", line
+	if matches := logLevelPattern.FindStringSubmatch(line); matches != nil {
+		level, msg = matches[1], matches[2]
+	}
+
+	switch level {
+	case "ERROR", "WARNING", "WARN":
+		return p.parseProblem(level, msg)
+	case "INFO", "":
+		return p.parseInfo(msg)
+	}
+	return nil
+}
+
+// Flush returns events for blocks that were cut off by the end of the log
+func (p *LogParser) Flush() []LogEvent {
+	if p.result != nil && !p.resultEmitted {
+		p.resultEmitted = true
+		return []LogEvent{*p.result}
+	}
+	return nil
+}
+
+// Module returns the artifactId of the module currently being built
+func (p *LogParser) Module() string {
+	return p.module
+}
+
+func (p *LogParser) parseInfo(msg string) []LogEvent {
+	trimmed := strings.TrimSpace(msg)
+
+	if strings.HasPrefix(trimmed, "Reactor Build Order") {
+		p.inOrder = true
+		p.order = nil
+		return nil
+	}
+
+	if p.inOrder {
+		// The block is a blank line, the module names, then a blank line or banner
+		if trimmed != "" && !strings.HasPrefix(trimmed, "---") {
+			if matches := logOrderEntryPattern.FindStringSubmatch(trimmed); matches != nil {
+				p.order = append(p.order, matches[1])
+			}
+			return nil
+		}
+		if len(p.order) > 0 {
+			p.inOrder = false
+			return []LogEvent{ReactorOrderEvent{Modules: p.order}}
+		}
+		if trimmed != "" {
+			p.inOrder = false
+		}
+		return nil
+	}
+
+	if matches := logCoordinatesPattern.FindStringSubmatch(trimmed); matches != nil {
+		p.pendingGroup, p.pendingArtifact = matches[1], matches[2]
+		return nil
+	}
+
+	// The name can't contain a colon, which excludes "Building jar: target/x.jar"
+	if matches := logBuildingPattern.FindStringSubmatch(trimmed); matches != nil {
+		event := ModuleStartEvent{
+			Name:       matches[1],
+			Version:    matches[2],
+			GroupID:    p.pendingGroup,
+			ArtifactID: p.pendingArtifact,
+		}
+		event.Index, _ = strconv.Atoi(matches[3])
+		event.Total, _ = strconv.Atoi(matches[4])
+		p.pendingGroup, p.pendingArtifact = "", ""
+		p.module = event.ArtifactID
+		if p.module == "" {
+			p.module = event.Name
+		}
+		return []LogEvent{event}
+	}
+
+	if matches := logPluginPattern.FindStringSubmatch(trimmed); matches != nil {
+		p.module = matches[5]
+		return []LogEvent{PluginExecutionEvent{
+			Plugin:      matches[1],
+			Version:     matches[2],
+			Goal:        matches[3],
+			ExecutionID: matches[4],
+			Module:      matches[5],
+		}}
+	}
+
+	if strings.HasPrefix(trimmed, "Reactor Summary") {
+		p.inSummary = true
+		return nil
+	}
+
+	if p.inSummary {
+		if matches := logSummaryPattern.FindStringSubmatch(trimmed); matches != nil {
+			return []LogEvent{ModuleFinishEvent{
+				Name:     strings.TrimSpace(matches[1]),
+				Status:   ModuleStatus(matches[2]),
+				Duration: parseMavenDuration(matches[3]),
+			}}
+		}
+	}
+
+	switch trimmed {
+	case "BUILD SUCCESS", "BUILD FAILURE":
+		p.inSummary = false
+		p.result = &BuildResultEvent{Success: trimmed == "BUILD SUCCESS"}
+		p.resultEmitted = false
+		return nil
+	}
+
+	if p.result != nil && !p.resultEmitted {
+		if matches := logTotalTimePattern.FindStringSubmatch(trimmed); matches != nil {
+			p.result.TotalTime = parseMavenDuration(matches[1])
+			return nil
+		}
+		if matches := logFinishedAtPattern.FindStringSubmatch(trimmed); matches != nil {
+			p.result.FinishedAt = matches[1]
+			p.resultEmitted = true
+			return []LogEvent{*p.result}
+		}
+	}
+
+	if matches := logDownloadPattern.FindStringSubmatch(trimmed); matches != nil {
+		return []LogEvent{DownloadEvent{
+			Done:       matches[1] == "Downloaded",
+			Repository: matches[2],
+			URL:        matches[3],
+			Size:       matches[4],
+			Rate:       matches[5],
+		}}
+	}
+
+	if matches := logProgressPattern.FindStringSubmatch(trimmed); matches != nil {
+		return []LogEvent{DownloadEvent{Progress: matches[1]}}
+	}
+
+	return nil
+}
+
+func (p *LogParser) parseProblem(level, msg string) []LogEvent {
+	severity := SeverityError
+	if level != "ERROR" {
+		severity = SeverityWarning
+	}
+
+	if events, ok := p.parseTestResult(msg); ok {
+		return events
+	}
+
+	msg = strings.TrimSpace(msg)
+	if msg == "" || strings.HasPrefix(msg, "->") {
+		return nil
+	}
+
+	if matches := logResumePattern.FindStringSubmatch(msg); matches != nil {
+		return []LogEvent{ResumeEvent{Module: matches[1]}}
+	}
+
+	event := ProblemEvent{
+		Severity: severity,
+		Message:  msg,
+		Module:   p.module,
+	}
+
+	if matches := logJavacLocationPattern.FindStringSubmatch(msg); matches != nil {
+		event.File = matches[1]
+		event.Line, _ = strconv.Atoi(matches[2])
+		event.Column, _ = strconv.Atoi(matches[3])
+		event.Message = matches[4]
+	} else if matches := logColonLocationPattern.FindStringSubmatch(msg); matches != nil && looksLikePath(matches[1]) {
+		event.File = matches[1]
+		event.Line, _ = strconv.Atoi(matches[2])
+		event.Column, _ = strconv.Atoi(matches[3])
+		event.Message = matches[4]
+	}
+
+	return []LogEvent{event}
+}
+
+// parseTestResult handles the Failures: and Errors: blocks Surefire and
+// Failsafe print after running tests. It reports whether msg belonged to them.
+func (p *LogParser) parseTestResult(msg string) ([]LogEvent, bool) {
+	trimmed := strings.TrimSpace(msg)
+	switch trimmed {
+	case "Failures:", "Errors:":
+		p.inTests = strings.TrimSuffix(trimmed, ":")
+		return nil, true
+	}
+
+	// Entries are indented; anything else ends the block
+	if p.inTests == "" || !strings.HasPrefix(msg, "  ") {
+		p.inTests = ""
+		return nil, false
+	}
+
+	// Reruns of flaky tests are listed as "Run 1: ..." under the test
+	if strings.HasPrefix(trimmed, "Run ") || trimmed == "" {
+		return nil, true
+	}
+
+	matches := logTestEntryPattern.FindStringSubmatch(trimmed)
+	if matches == nil {
+		return nil, true
+	}
+
+	event := TestFailureEvent{
+		Class:   matches[1],
+		Message: strings.TrimSpace(strings.TrimPrefix(matches[3], "»")),
+		Error:   p.inTests == "Errors",
+		Module:  p.module,
+	}
+	event.Line, _ = strconv.Atoi(matches[2])
+
+	// Class.method, unless the last segment is itself a class name
+	if i := strings.LastIndex(event.Class, "."); i >= 0 {
+		if method := event.Class[i+1:]; method != "" && !unicode.IsUpper(rune(method[0])) {
+			event.Class, event.Method = event.Class[:i], method
+		}
+	}
+
+	return []LogEvent{event}, true
+}
+
+// looksLikePath reports whether s is plausibly a source file path rather
+// than, say, a Maven coordinate or a URL
+func looksLikePath(s string) bool {
+	if strings.Contains(s, " ") || strings.Contains(s, "://") {
+		return false
+	}
+	return strings.ContainsAny(s, `/\`)
+}
+
+// parseMavenDuration parses the durations Maven prints in the build summary,
+// e.g. "1.234 s", "1.234s", "01:05 min" and "1:02 h"
+func parseMavenDuration(s string) time.Duration {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+
+	if value, ok := strings.CutSuffix(s, "s"); ok && !strings.Contains(value, ":") {
+		seconds, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return 0
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	unit := time.Minute
+	value, ok := strings.CutSuffix(s, " min")
+	if !ok {
+		value, ok = strings.CutSuffix(s, " h")
+		if !ok {
+			return 0
+		}
+		unit = time.Hour
+	}
+
+	major, minor, found := strings.Cut(value, ":")
+	if !found {
+		return 0
+	}
+	m, err1 := strconv.Atoi(major)
+	sec, err2 := strconv.ParseFloat(minor, 64)
+	if err1 != nil || err2 != nil {
+		return 0
+	}
+	return time.Duration(m)*unit + time.Duration(sec*float64(unit/60))
+}
+
+// ParseLog parses a complete log and returns all events in order
+func ParseLog(lines []string) []LogEvent {
+	parser := NewLogParser()
+	var events []LogEvent
+	for _, line := range lines {
+		events = append(events, parser.Parse(line)...)
+	}
+	return append(events, parser.Flush()...)
+}
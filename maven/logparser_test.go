@@ -0,0 +1,260 @@
+package maven
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseTestdataLog parses a recorded Maven log from testdata
+func parseTestdataLog(t *testing.T, name string) []LogEvent {
+	t.Helper()
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", name, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return ParseLog(lines)
+}
+
+// eventsOfType returns the events of type T in order
+func eventsOfType[T LogEvent](events []LogEvent) []T {
+	var matched []T
+	for _, event := range events {
+		if e, ok := event.(T); ok {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+func TestParseLog_MultiModuleSuccess(t *testing.T) {
+	events := parseTestdataLog(t, "multimodule-success.log")
+
+	orders := eventsOfType[ReactorOrderEvent](events)
+	if len(orders) != 1 || strings.Join(orders[0].Modules, ",") != "shop-parent,shop-core,shop-web" {
+		t.Errorf("Unexpected reactor order: %+v", orders)
+	}
+
+	starts := eventsOfType[ModuleStartEvent](events)
+	if len(starts) != 3 {
+		t.Fatalf("Expected 3 module starts, got %d: %+v", len(starts), starts)
+	}
+	want := ModuleStartEvent{Name: "Shop Web Frontend", Version: "1.4.0-SNAPSHOT", GroupID: "com.example.shop", ArtifactID: "shop-web", Index: 3, Total: 3}
+	if starts[2] != want {
+		t.Errorf("Third module start = %+v, want %+v", starts[2], want)
+	}
+
+	plugins := eventsOfType[PluginExecutionEvent](events)
+	if len(plugins) != 10 {
+		t.Errorf("Expected 10 plugin executions, got %d", len(plugins))
+	}
+	compile := PluginExecutionEvent{Plugin: "compiler", Version: "3.11.0", Goal: "compile", ExecutionID: "default-compile", Module: "shop-core"}
+	found := false
+	for _, p := range plugins {
+		if p == compile {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected plugin execution %+v", compile)
+	}
+
+	problems := eventsOfType[ProblemEvent](events)
+	if len(problems) != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %+v", len(problems), problems)
+	}
+	warning := problems[0]
+	if warning.Severity != SeverityWarning || warning.Line != 31 || warning.Column != 24 || warning.Module != "shop-core" {
+		t.Errorf("Unexpected warning: %+v", warning)
+	}
+	if filepath.Base(warning.File) != "LegacyPricing.java" {
+		t.Errorf("Expected warning file LegacyPricing.java, got %q", warning.File)
+	}
+
+	downloads := eventsOfType[DownloadEvent](events)
+	if len(downloads) != 2 || downloads[0].Done || !downloads[1].Done {
+		t.Fatalf("Expected a download start and finish, got %+v", downloads)
+	}
+	if downloads[1].Repository != "central" || downloads[1].Size != "2.8 kB" || downloads[1].Rate != "31 kB/s" {
+		t.Errorf("Unexpected download event: %+v", downloads[1])
+	}
+
+	finishes := eventsOfType[ModuleFinishEvent](events)
+	if len(finishes) != 3 {
+		t.Fatalf("Expected 3 module summaries, got %d", len(finishes))
+	}
+	if finishes[2].Name != "Shop Web Frontend" || finishes[2].Status != ModuleSuccess || finishes[2].Duration != 62*time.Second {
+		t.Errorf("Unexpected module summary: %+v", finishes[2])
+	}
+	if finishes[1].Duration != 4518*time.Millisecond {
+		t.Errorf("Expected 4.518s for shop-core, got %v", finishes[1].Duration)
+	}
+
+	results := eventsOfType[BuildResultEvent](events)
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 build result, got %d", len(results))
+	}
+	if !results[0].Success || results[0].TotalTime != 67*time.Second || results[0].FinishedAt != "2024-03-18T09:41:27+01:00" {
+		t.Errorf("Unexpected build result: %+v", results[0])
+	}
+}
+
+func TestParseLog_CompileFailure(t *testing.T) {
+	events := parseTestdataLog(t, "compile-failure.log")
+
+	var located []ProblemEvent
+	for _, p := range eventsOfType[ProblemEvent](events) {
+		if p.File != "" {
+			located = append(located, p)
+		}
+	}
+	if len(located) != 4 {
+		t.Fatalf("Expected 4 located errors (reported twice by Maven), got %d: %+v", len(located), located)
+	}
+
+	first := located[0]
+	want := ProblemEvent{
+		Severity: SeverityError,
+		Message:  "cannot find symbol",
+		File:     "/work/billing/billing-service/src/main/java/com/acme/billing/InvoiceService.java",
+		Line:     42,
+		Column:   17,
+		Module:   "billing-service",
+	}
+	if first != want {
+		t.Errorf("First error = %+v, want %+v", first, want)
+	}
+
+	finishes := eventsOfType[ModuleFinishEvent](events)
+	statuses := map[string]ModuleStatus{}
+	for _, f := range finishes {
+		statuses[f.Name] = f.Status
+	}
+	if statuses["billing-service"] != ModuleFailure || statuses["billing-app"] != ModuleSkipped || statuses["billing-api"] != ModuleSuccess {
+		t.Errorf("Unexpected reactor statuses: %v", statuses)
+	}
+
+	if resumes := eventsOfType[ResumeEvent](events); len(resumes) != 1 || resumes[0].Module != ":billing-service" {
+		t.Errorf("Expected Maven's suggestion to resume from :billing-service, got %+v", resumes)
+	}
+
+	results := eventsOfType[BuildResultEvent](events)
+	if len(results) != 1 || results[0].Success {
+		t.Fatalf("Expected a single failed build result, got %+v", results)
+	}
+	if results[0].TotalTime != 2749*time.Millisecond {
+		t.Errorf("Expected total time 2.749s, got %v", results[0].TotalTime)
+	}
+}
+
+func TestParseLog_LegacySingleModule(t *testing.T) {
+	events := parseTestdataLog(t, "single-module-legacy.log")
+
+	starts := eventsOfType[ModuleStartEvent](events)
+	if len(starts) != 1 || starts[0].Name != "demo" || starts[0].Index != 0 {
+		t.Fatalf("Expected one module start without reactor position, got %+v", starts)
+	}
+
+	problems := eventsOfType[ProblemEvent](events)
+	if len(problems) != 2 {
+		t.Fatalf("Expected 2 warnings, got %d: %+v", len(problems), problems)
+	}
+	if problems[0].File != "" {
+		t.Errorf("Expected encoding warning without a file, got %q", problems[0].File)
+	}
+	kotlin := problems[1]
+	if kotlin.File != "/tmp/demo/src/main/kotlin/demo/Util.kt" || kotlin.Line != 7 || kotlin.Column != 13 {
+		t.Errorf("Unexpected kotlin warning location: %+v", kotlin)
+	}
+
+	// The log ends without "Finished at", so the result comes from Flush
+	results := eventsOfType[BuildResultEvent](events)
+	if len(results) != 1 || !results[0].Success || results[0].TotalTime != 3012*time.Millisecond {
+		t.Errorf("Expected flushed successful build result, got %+v", results)
+	}
+}
+
+func TestLogParser_StripsANSIColors(t *testing.T) {
+	parser := NewLogParser()
+	events := parser.Parse("\x1b[1;31mERROR\x1b[m] x")
+	if len(events) != 0 {
+		t.Errorf("Expected no events for a mangled level, got %+v", events)
+	}
+
+	events = parser.Parse("[\x1b[1;31mERROR\x1b[m] /src/App.java:[3,1] class, interface, or enum expected")
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	problem := events[0].(ProblemEvent)
+	if problem.File != "/src/App.java" || problem.Line != 3 {
+		t.Errorf("Unexpected problem: %+v", problem)
+	}
+}
+
+func TestParseMavenDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{"0.212 s", 212 * time.Millisecond},
+		{"3.012s", 3012 * time.Millisecond},
+		{"01:02 min", 62 * time.Second},
+		{"1:30 h", 90 * time.Minute},
+		{"", 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseMavenDuration(tt.input); got != tt.want {
+			t.Errorf("parseMavenDuration(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseLog_TestFailures(t *testing.T) {
+	events := parseTestdataLog(t, "test-failure.log")
+
+	failures := eventsOfType[TestFailureEvent](events)
+	if len(failures) != 2 {
+		t.Fatalf("Expected 2 test failures, got %d: %+v", len(failures), failures)
+	}
+
+	want := TestFailureEvent{Class: "InvoiceTest", Method: "rounding", Line: 42, Message: "expected: <1.00> but was: <0.99>", Module: "billing-core"}
+	if failures[0] != want {
+		t.Errorf("First failure = %+v, want %+v", failures[0], want)
+	}
+	if !failures[1].Error || failures[1].Method != "taxFor" || failures[1].Line != 57 {
+		t.Errorf("Unexpected test error: %+v", failures[1])
+	}
+	if !strings.HasPrefix(failures[1].Message, "NullPointer Cannot invoke") {
+		t.Errorf("Expected the exception summary as message, got %q", failures[1].Message)
+	}
+
+	// Entries of the results block are not reported as plain problems too
+	for _, p := range eventsOfType[ProblemEvent](events) {
+		if strings.Contains(p.Message, "InvoiceTest.rounding:42") {
+			t.Errorf("Test failure also reported as problem: %+v", p)
+		}
+	}
+}
+
+func TestLogParser_TestEntryClassOnly(t *testing.T) {
+	parser := NewLogParser()
+	parser.Parse("[ERROR] Errors: ")
+	events := parser.Parse("[ERROR]   com.acme.DbIT » IllegalState Failed to load ApplicationContext")
+
+	failures := eventsOfType[TestFailureEvent](events)
+	if len(failures) != 1 || failures[0].Class != "com.acme.DbIT" || failures[0].Method != "" {
+		t.Errorf("Expected a class-level failure for com.acme.DbIT, got %+v", failures)
+	}
+}
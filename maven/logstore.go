@@ -0,0 +1,224 @@
+package maven
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DefaultLogWindow is the number of lines a LogStore keeps in memory by default
+const DefaultLogWindow = 10000
+
+// LogStore holds the output of a command.
+// The most recent lines are kept in an in-memory ring buffer. Once the buffer
+// is full, the oldest line is spilled to a temporary file, so arbitrarily
+// long builds use a bounded amount of memory while every line stays readable.
+// A LogStore is safe for concurrent use.
+type LogStore struct {
+	mu      sync.Mutex
+	ring    []string // In-memory window, used as a ring buffer once full
+	head    int      // Index of the oldest in-memory line once the ring is full
+	spilled int      // Number of lines moved to the spill file
+	spill   *os.File
+	offsets []int64 // Offset of each spilled line in the spill file
+	size    int64   // Bytes written to the spill file
+	err     error   // First spill error, after which spilled lines are dropped
+}
+
+// NewLogStore creates a log store that keeps at most window lines in memory.
+// A window of zero or less uses DefaultLogWindow.
+func NewLogStore(window int) *LogStore {
+	if window <= 0 {
+		window = DefaultLogWindow
+	}
+	return &LogStore{ring: make([]string, 0, window)}
+}
+
+// Append adds lines to the end of the log. Lines containing newlines are split.
+func (s *LogStore) Append(lines ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, line := range lines {
+		for _, l := range strings.Split(line, "\n") {
+			s.appendLine(l)
+		}
+	}
+}
+
+func (s *LogStore) appendLine(line string) {
+	if len(s.ring) < cap(s.ring) {
+		s.ring = append(s.ring, line)
+		return
+	}
+
+	// Ring is full: spill the oldest line and reuse its slot
+	s.spillLine(s.ring[s.head])
+	s.ring[s.head] = line
+	s.head = (s.head + 1) % len(s.ring)
+}
+
+func (s *LogStore) spillLine(line string) {
+	s.spilled++
+	if s.err != nil {
+		return
+	}
+
+	if s.spill == nil {
+		f, err := os.CreateTemp("", "mvn-tui-log-*.txt")
+		if err != nil {
+			s.err = fmt.Errorf("failed to create log spill file: %w", err)
+			return
+		}
+		s.spill = f
+	}
+
+	n, err := s.spill.WriteString(line + "\n")
+	if err != nil {
+		s.err = fmt.Errorf("failed to write log spill file: %w", err)
+		return
+	}
+	s.offsets = append(s.offsets, s.size)
+	s.size += int64(n)
+}
+
+// Len returns the total number of lines in the log
+func (s *LogStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.spilled + len(s.ring)
+}
+
+// Lines returns the lines in the half-open range [start, end), reading
+// spilled lines back from disk as needed. The range is clamped to the log.
+func (s *LogStore) Lines(start, end int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := s.spilled + len(s.ring)
+	start = max(start, 0)
+	end = min(end, total)
+	if start >= end {
+		return []string{}, nil
+	}
+
+	lines := make([]string, 0, end-start)
+
+	// Lines that have been spilled to disk
+	if start < s.spilled {
+		spilledEnd := min(end, s.spilled)
+		onDisk, err := s.readSpilled(start, spilledEnd)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, onDisk...)
+		start = spilledEnd
+	}
+
+	// Lines still in memory
+	for i := start; i < end; i++ {
+		lines = append(lines, s.ring[(s.head+i-s.spilled)%len(s.ring)])
+	}
+
+	return lines, nil
+}
+
+// readSpilled reads spilled lines [start, end) from the spill file
+func (s *LogStore) readSpilled(start, end int) ([]string, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	endOffset := s.size
+	if end < len(s.offsets) {
+		endOffset = s.offsets[end]
+	}
+
+	section := io.NewSectionReader(s.spill, s.offsets[start], endOffset-s.offsets[start])
+	lines := make([]string, 0, end-start)
+	scanner := bufio.NewScanner(section)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log spill file: %w", err)
+	}
+	return lines, nil
+}
+
+// All returns every line in the log
+func (s *LogStore) All() ([]string, error) {
+	return s.Lines(0, s.Len())
+}
+
+// logReadChunk is how many lines WriteTo and Scan read at a time
+const logReadChunk = 1000
+
+// Scan calls fn with each line of the log in order until fn returns false.
+// The log is read a chunk at a time, so it is never held in memory in full.
+func (s *LogStore) Scan(fn func(line string) bool) error {
+	for start := 0; start < s.Len(); start += logReadChunk {
+		lines, err := s.Lines(start, start+logReadChunk)
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			if !fn(line) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// WriteTo writes every line of the log to w, each followed by a newline. The
+// log is read a chunk at a time, so it is never held in memory in full.
+func (s *LogStore) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var written int64
+	for start := 0; start < s.Len(); start += logReadChunk {
+		lines, err := s.Lines(start, start+logReadChunk)
+		if err != nil {
+			return written, err
+		}
+		for _, line := range lines {
+			n, err := bw.WriteString(line + "\n")
+			written += int64(n)
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, bw.Flush()
+}
+
+// Err returns the first error encountered while spilling lines to disk
+func (s *LogStore) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close releases the spill file, if any. The in-memory window stays readable.
+func (s *LogStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Any further spilling is dropped rather than creating a new file
+	if s.err == nil {
+		s.err = fmt.Errorf("log store closed")
+	}
+
+	if s.spill == nil {
+		return nil
+	}
+
+	name := s.spill.Name()
+	s.spill.Close()
+	s.spill = nil
+	return os.Remove(name)
+}
@@ -0,0 +1,162 @@
+package maven
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLogStore_InMemoryOnly(t *testing.T) {
+	store := NewLogStore(10)
+	defer store.Close()
+
+	store.Append("one", "two", "three")
+
+	if store.Len() != 3 {
+		t.Fatalf("Expected 3 lines, got %d", store.Len())
+	}
+	if store.spill != nil {
+		t.Error("Expected no spill file while under the window size")
+	}
+
+	lines, err := store.Lines(1, 3)
+	if err != nil {
+		t.Fatalf("Lines returned error: %v", err)
+	}
+	if len(lines) != 2 || lines[0] != "two" || lines[1] != "three" {
+		t.Errorf("Unexpected lines: %v", lines)
+	}
+}
+
+func TestLogStore_SpillsOlderLines(t *testing.T) {
+	store := NewLogStore(5)
+	defer store.Close()
+
+	for i := 0; i < 23; i++ {
+		store.Append(fmt.Sprintf("line %d", i))
+	}
+
+	if store.Len() != 23 {
+		t.Fatalf("Expected 23 lines, got %d", store.Len())
+	}
+	if len(store.ring) != 5 {
+		t.Errorf("Expected in-memory window of 5 lines, got %d", len(store.ring))
+	}
+
+	all, err := store.All()
+	if err != nil {
+		t.Fatalf("All returned error: %v", err)
+	}
+	for i, line := range all {
+		if want := fmt.Sprintf("line %d", i); line != want {
+			t.Fatalf("Line %d = %q, want %q", i, line, want)
+		}
+	}
+
+	// A range spanning disk and memory
+	lines, err := store.Lines(15, 20)
+	if err != nil {
+		t.Fatalf("Lines returned error: %v", err)
+	}
+	if len(lines) != 5 || lines[0] != "line 15" || lines[4] != "line 19" {
+		t.Errorf("Unexpected lines across spill boundary: %v", lines)
+	}
+}
+
+func TestLogStore_WriteToIncludesSpilledLines(t *testing.T) {
+	store := NewLogStore(5)
+	defer store.Close()
+
+	var want strings.Builder
+	for i := 0; i < 2*logReadChunk+3; i++ {
+		store.Append(fmt.Sprintf("line %d", i))
+		fmt.Fprintf(&want, "line %d\n", i)
+	}
+
+	var got strings.Builder
+	n, err := store.WriteTo(&got)
+	if err != nil {
+		t.Fatalf("WriteTo returned error: %v", err)
+	}
+	if got.String() != want.String() || n != int64(want.Len()) {
+		t.Errorf("Expected every line in order (%d bytes), wrote %d bytes", want.Len(), n)
+	}
+}
+
+func TestLogStore_ScanStopsWhenAsked(t *testing.T) {
+	store := NewLogStore(5)
+	defer store.Close()
+	for i := 0; i < logReadChunk+10; i++ {
+		store.Append(fmt.Sprintf("line %d", i))
+	}
+
+	var seen []string
+	err := store.Scan(func(line string) bool {
+		seen = append(seen, line)
+		return len(seen) < logReadChunk+2
+	})
+	if err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+	if len(seen) != logReadChunk+2 || seen[0] != "line 0" || seen[logReadChunk+1] != fmt.Sprintf("line %d", logReadChunk+1) {
+		t.Errorf("Expected the first %d lines in order, got %d", logReadChunk+2, len(seen))
+	}
+}
+
+func TestLogStore_SplitsEmbeddedNewlines(t *testing.T) {
+	store := NewLogStore(2)
+	defer store.Close()
+
+	store.Append("a\nb\nc", "d")
+
+	all, err := store.All()
+	if err != nil {
+		t.Fatalf("All returned error: %v", err)
+	}
+	if len(all) != 4 || all[0] != "a" || all[3] != "d" {
+		t.Errorf("Unexpected lines: %v", all)
+	}
+}
+
+func TestLogStore_ClampsRanges(t *testing.T) {
+	store := NewLogStore(3)
+	defer store.Close()
+	store.Append("a", "b")
+
+	lines, err := store.Lines(-5, 100)
+	if err != nil {
+		t.Fatalf("Lines returned error: %v", err)
+	}
+	if len(lines) != 2 {
+		t.Errorf("Expected clamped range of 2 lines, got %v", lines)
+	}
+
+	lines, _ = store.Lines(5, 1)
+	if len(lines) != 0 {
+		t.Errorf("Expected empty range, got %v", lines)
+	}
+}
+
+func TestLogStore_CloseRemovesSpillFile(t *testing.T) {
+	store := NewLogStore(1)
+	store.Append("a", "b", "c")
+
+	if store.spill == nil {
+		t.Fatal("Expected a spill file to be created")
+	}
+	path := store.spill.Name()
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected spill file %s to be removed", path)
+	}
+
+	// The in-memory window remains readable
+	lines, err := store.Lines(2, 3)
+	if err != nil || len(lines) != 1 || lines[0] != "c" {
+		t.Errorf("Expected in-memory line after Close, got %v (%v)", lines, err)
+	}
+}
@@ -0,0 +1,111 @@
+package maven
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// loadModules walks the module tree below the root POM. Each aggregator
+// comes before its own modules, which are one level deeper. Modules are
+// named by their path from rootPath, with forward slashes.
+func loadModules(rootPath string, root *POM) []Module {
+	var modules []Module
+	visited := map[string]bool{filepath.Clean(rootPath): true}
+
+	var walk func(dir string, names []string, parent string, depth int)
+	walk = func(dir string, names []string, parent string, depth int) {
+		for _, name := range names {
+			// A module names its directory, or the POM file itself
+			moduleDir := filepath.Join(dir, filepath.FromSlash(strings.TrimSpace(name)))
+			pomPath := filepath.Join(moduleDir, "pom.xml")
+			if strings.HasSuffix(moduleDir, ".xml") {
+				pomPath, moduleDir = moduleDir, filepath.Dir(moduleDir)
+			}
+			if visited[moduleDir] {
+				continue
+			}
+			visited[moduleDir] = true
+
+			relative, err := filepath.Rel(rootPath, moduleDir)
+			if err != nil {
+				relative = moduleDir
+			}
+			module := Module{
+				Name:      filepath.ToSlash(relative),
+				Path:      moduleDir,
+				Packaging: "jar",
+				Parent:    parent,
+				Depth:     depth,
+				Selected:  true,
+			}
+
+			// A module without a readable POM is still listed, so Maven reports the problem
+			pom, err := LoadPOM(pomPath)
+			if err == nil {
+				module.Model = pom
+				module.GroupID = pom.GroupID
+				module.ArtifactID = pom.ArtifactID
+				if pom.Packaging != "" {
+					module.Packaging = pom.Packaging
+				}
+			}
+			modules = append(modules, module)
+			if err == nil {
+				walk(moduleDir, pom.Modules, module.Name, depth+1)
+			}
+		}
+	}
+	walk(rootPath, root.Modules, "", 0)
+	return modules
+}
+
+// Label returns the module's name within its aggregator
+func (m Module) Label() string {
+	if m.Parent == "" {
+		return m.Name
+	}
+	if label, ok := strings.CutPrefix(m.Name, m.Parent+"/"); ok {
+		return label
+	}
+	return path.Base(m.Name)
+}
+
+// HasModules reports whether the module at index aggregates other modules
+func (p *Project) HasModules(index int) bool {
+	return index >= 0 && index+1 < len(p.Modules) && p.Modules[index+1].Depth > p.Modules[index].Depth
+}
+
+// subtree returns the end of the modules below the module at index, which
+// follow it in Modules
+func (p *Project) subtree(index int) int {
+	end := index + 1
+	for end < len(p.Modules) && p.Modules[end].Depth > p.Modules[index].Depth {
+		end++
+	}
+	return end
+}
+
+// SelectedProjects returns the -pl values of the selected modules:
+// :artifactId, or groupId:artifactId when modules share an artifactId.
+// Modules whose POM couldn't be read are given by path.
+func (p *Project) SelectedProjects() []string {
+	count := make(map[string]int, len(p.Modules))
+	for _, mod := range p.Modules {
+		count[mod.ArtifactID]++
+	}
+
+	var projects []string
+	for _, mod := range p.Modules {
+		switch {
+		case !mod.Selected:
+		case mod.ArtifactID == "":
+			projects = append(projects, mod.Name)
+		case count[mod.ArtifactID] > 1 && mod.GroupID != "":
+			projects = append(projects, mod.GroupID+":"+mod.ArtifactID)
+		default:
+			projects = append(projects, ":"+mod.ArtifactID)
+		}
+	}
+	return projects
+}
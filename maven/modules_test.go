@@ -0,0 +1,95 @@
+package maven
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// pomWithModules returns a POM with the given coordinates and modules
+func pomWithModules(groupID, artifactID, packaging string, modules ...string) string {
+	var sb strings.Builder
+	sb.WriteString("<project>\n")
+	if groupID != "" {
+		sb.WriteString("  <groupId>" + groupID + "</groupId>\n")
+	}
+	sb.WriteString("  <artifactId>" + artifactID + "</artifactId>\n  <version>1.0</version>\n")
+	if packaging != "" {
+		sb.WriteString("  <packaging>" + packaging + "</packaging>\n")
+	}
+	if len(modules) > 0 {
+		sb.WriteString("  <modules>\n")
+		for _, module := range modules {
+			sb.WriteString("    <module>" + module + "</module>\n")
+		}
+		sb.WriteString("  </modules>\n")
+	}
+	sb.WriteString("</project>\n")
+	return sb.String()
+}
+
+func TestLoadProject_NestedModules(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pom.xml":                       pomWithModules("com.example", "shop", "pom", "core", "services", "missing"),
+		"core/pom.xml":                  pomWithModules("com.example", "shop-core", ""),
+		"services/pom.xml":              pomWithModules("com.example", "shop-services", "pom", "orders", "billing/pom.xml"),
+		"services/orders/pom.xml":       pomWithModules("com.example", "orders", "pom", "api"),
+		"services/orders/api/pom.xml":   pomWithModules("com.example", "shop-core", "jar"),
+		"services/billing/pom.xml":      pomWithModules("com.example.billing", "billing", "war"),
+		"services/billing/docs/pom.xml": pomWithModules("com.example", "never-listed", ""),
+	})
+
+	project, err := LoadProject(dir)
+	if err != nil {
+		t.Fatalf("Failed to load project: %v", err)
+	}
+
+	want := []Module{
+		{Name: "core", GroupID: "com.example", ArtifactID: "shop-core", Packaging: "jar"},
+		{Name: "services", GroupID: "com.example", ArtifactID: "shop-services", Packaging: "pom"},
+		{Name: "services/orders", GroupID: "com.example", ArtifactID: "orders", Packaging: "pom", Parent: "services", Depth: 1},
+		{Name: "services/orders/api", GroupID: "com.example", ArtifactID: "shop-core", Packaging: "jar", Parent: "services/orders", Depth: 2},
+		{Name: "services/billing", GroupID: "com.example.billing", ArtifactID: "billing", Packaging: "war", Parent: "services", Depth: 1},
+		{Name: "missing", Packaging: "jar"},
+	}
+	for i := range want {
+		want[i].Path = filepath.Join(dir, filepath.FromSlash(want[i].Name))
+		want[i].Selected = true
+	}
+	modules := make([]Module, len(project.Modules))
+	for i, mod := range project.Modules {
+		if (mod.Model == nil) != (mod.Name == "missing") {
+			t.Errorf("Expected the POM of %s to be kept only if readable", mod.Name)
+		}
+		mod.Model = nil
+		modules[i] = mod
+	}
+	if !reflect.DeepEqual(modules, want) {
+		t.Errorf("Modules =\n%+v\nwant\n%+v", modules, want)
+	}
+	if label := project.Modules[3].Label(); label != "api" {
+		t.Errorf("Expected the label within its aggregator, got %q", label)
+	}
+
+	// Deselecting an aggregator deselects the modules below it
+	project.ToggleModule(1)
+	cmd := BuildCommand(project, []string{"install"}, BuildOptions{})
+	if got := strings.Join(cmd.Args, " "); got != "-pl com.example:shop-core,missing install" {
+		t.Errorf("Expected the core module by coordinates, got %q", got)
+	}
+
+	project.ToggleModule(0)
+	project.ToggleModule(3)
+	cmd = BuildCommand(project, []string{"install"}, BuildOptions{})
+	if got := strings.Join(cmd.Args, " "); got != "-pl com.example:shop-core,missing install" || project.Modules[0].Selected || !project.Modules[3].Selected {
+		t.Errorf("Expected the nested module by coordinates, got %q", got)
+	}
+	project.ToggleModule(5)
+	project.ToggleModule(4)
+	cmd = BuildCommand(project, []string{"install"}, BuildOptions{})
+	if got := strings.Join(cmd.Args, " "); got != "-pl com.example:shop-core,:billing install" {
+		t.Errorf("Expected the nested modules by artifactId, got %q", got)
+	}
+}
@@ -0,0 +1,74 @@
+package maven
+
+// PluginTask is a task suggested for a build plugin the project uses
+type PluginTask struct {
+	Name        string
+	Description string
+	Goals       []string
+}
+
+// pluginTasks maps the artifact IDs of well-known plugins to the tasks
+// suggested for them. Tasks named "Run (...)" open in the terminal pane.
+var pluginTasks = map[string][]PluginTask{
+	"spring-boot-maven-plugin": {
+		{Name: "Run (Spring Boot)", Description: "Run Spring Boot application", Goals: []string{"spring-boot:run"}},
+	},
+	"jetty-maven-plugin": {
+		{Name: "Run (Jetty)", Description: "Run the web app on embedded Jetty", Goals: []string{"jetty:run"}},
+	},
+	"tomcat7-maven-plugin": {
+		{Name: "Run (Tomcat)", Description: "Run WAR on embedded Tomcat", Goals: []string{"tomcat7:run"}},
+	},
+	"quarkus-maven-plugin": {
+		{Name: "Run (Quarkus dev)", Description: "Run Quarkus in dev mode with live reload", Goals: []string{"quarkus:dev"}},
+	},
+	"exec-maven-plugin": {
+		{Name: "Run (exec:exec)", Description: "Run the program configured for exec:exec", Goals: []string{"exec:exec"}},
+	},
+	"jib-maven-plugin": {
+		{Name: "Jib Build", Description: "Build and push a container image", Goals: []string{"compile", "jib:build"}},
+		{Name: "Jib Docker Build", Description: "Build a container image for the local Docker daemon", Goals: []string{"compile", "jib:dockerBuild"}},
+	},
+	"native-maven-plugin": {
+		{Name: "Native Compile", Description: "Build a GraalVM native executable", Goals: []string{"native:compile"}},
+	},
+	"flyway-maven-plugin": {
+		{Name: "Flyway Migrate", Description: "Apply pending database migrations", Goals: []string{"flyway:migrate"}},
+		{Name: "Flyway Info", Description: "Show the state of database migrations", Goals: []string{"flyway:info"}},
+	},
+	"liquibase-maven-plugin": {
+		{Name: "Liquibase Update", Description: "Apply pending changesets", Goals: []string{"liquibase:update"}},
+		{Name: "Liquibase Status", Description: "List changesets not yet applied", Goals: []string{"liquibase:status"}},
+	},
+	"spotless-maven-plugin": {
+		{Name: "Spotless Apply", Description: "Format the sources", Goals: []string{"spotless:apply"}},
+		{Name: "Spotless Check", Description: "Check the sources are formatted", Goals: []string{"spotless:check"}},
+	},
+	"maven-checkstyle-plugin": {
+		{Name: "Checkstyle", Description: "Check coding style", Goals: []string{"checkstyle:check"}},
+	},
+	"jacoco-maven-plugin": {
+		{Name: "Coverage Report", Description: "Run tests and write the JaCoCo report", Goals: []string{"test", "jacoco:report"}},
+	},
+	"versions-maven-plugin": {
+		{Name: "Dependency Updates", Description: "List newer dependency versions", Goals: []string{"versions:display-dependency-updates"}},
+		{Name: "Plugin Updates", Description: "List newer plugin versions", Goals: []string{"versions:display-plugin-updates"}},
+		{Name: "Property Updates", Description: "List newer versions for version properties", Goals: []string{"versions:display-property-updates"}},
+	},
+}
+
+// PluginTasks returns the tasks suggested for the plugins the project builds
+// with, in the order the plugins appear in <build><plugins>. Plugins only
+// listed under pluginManagement aren't part of the build, so they add none.
+func (p *Project) PluginTasks() []PluginTask {
+	var tasks []PluginTask
+	seen := make(map[string]bool)
+	for _, plugin := range p.Plugins {
+		if plugin.Managed || seen[plugin.ArtifactID] {
+			continue
+		}
+		seen[plugin.ArtifactID] = true
+		tasks = append(tasks, pluginTasks[plugin.ArtifactID]...)
+	}
+	return tasks
+}
@@ -0,0 +1,80 @@
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadProject_PluginsAndPluginManagement(t *testing.T) {
+	dir := t.TempDir()
+	pom := `<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>shop</artifactId>
+  <version>1.0.0</version>
+  <packaging>war</packaging>
+  <build>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <groupId>org.eclipse.jetty</groupId>
+          <artifactId>jetty-maven-plugin</artifactId>
+          <version>11.0.20</version>
+        </plugin>
+        <plugin>
+          <groupId>org.flywaydb</groupId>
+          <artifactId>flyway-maven-plugin</artifactId>
+          <version>10.10.0</version>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+    <plugins>
+      <plugin>
+        <groupId>org.flywaydb</groupId>
+        <artifactId>flyway-maven-plugin</artifactId>
+      </plugin>
+      <plugin>
+        <groupId>org.springframework.boot</groupId>
+        <artifactId>spring-boot-maven-plugin</artifactId>
+      </plugin>
+      <plugin>
+        <artifactId>maven-checkstyle-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`
+	if err := os.WriteFile(filepath.Join(dir, "pom.xml"), []byte(pom), 0644); err != nil {
+		t.Fatalf("Failed to write pom.xml: %v", err)
+	}
+	project, err := LoadProject(dir)
+	if err != nil {
+		t.Fatalf("Failed to load project: %v", err)
+	}
+
+	wantPlugins := []Plugin{
+		{GroupID: "org.flywaydb", ArtifactID: "flyway-maven-plugin", Version: "10.10.0"},
+		{GroupID: "org.springframework.boot", ArtifactID: "spring-boot-maven-plugin"},
+		{GroupID: "org.apache.maven.plugins", ArtifactID: "maven-checkstyle-plugin"},
+		{GroupID: "org.eclipse.jetty", ArtifactID: "jetty-maven-plugin", Version: "11.0.20", Managed: true},
+	}
+	if !reflect.DeepEqual(project.Plugins, wantPlugins) {
+		t.Errorf("Plugins = %+v, want %+v", project.Plugins, wantPlugins)
+	}
+	if !project.HasSpringBoot {
+		t.Error("Expected the Spring Boot plugin to mark a Spring Boot project")
+	}
+
+	var names []string
+	for _, task := range project.PluginTasks() {
+		names = append(names, task.Name)
+	}
+	// Jetty is only managed, so it neither runs nor suggests tasks
+	wantNames := []string{"Flyway Migrate", "Flyway Info", "Run (Spring Boot)", "Checkstyle"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("PluginTasks = %v, want %v", names, wantNames)
+	}
+}
@@ -0,0 +1,513 @@
+package maven
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxParentDepth bounds the chain of parents followed on disk
+const maxParentDepth = 16
+
+const (
+	defaultPluginGroup = "org.apache.maven.plugins" // groupId of plugins that don't name one
+	springBootGroup    = "org.springframework.boot"
+)
+
+// POM is the model of a pom.xml. LoadPOM fills in what the POM inherits from
+// parents found on disk and interpolates ${...} expressions; ReadPOM leaves
+// the file as written.
+type POM struct {
+	XMLName              xml.Name     `xml:"project"`
+	GroupID              string       `xml:"groupId"`
+	ArtifactID           string       `xml:"artifactId"`
+	Version              string       `xml:"version"`
+	Packaging            string       `xml:"packaging"`
+	Name                 string       `xml:"name"`
+	Parent               Parent       `xml:"parent"`
+	Properties           Properties   `xml:"properties"`
+	Modules              []string     `xml:"modules>module"`
+	DependencyManagement []Dependency `xml:"dependencyManagement>dependencies>dependency"`
+	Dependencies         []Dependency `xml:"dependencies>dependency"`
+	Build                Build        `xml:"build"`
+	Profiles             []POMProfile `xml:"profiles>profile"`
+
+	Path      string `xml:"-"` // File the POM was read from
+	ParentPOM *POM   `xml:"-"` // Parent found on disk, nil if it wasn't
+}
+
+// Parent is the parent element of a POM
+type Parent struct {
+	GroupID      string  `xml:"groupId"`
+	ArtifactID   string  `xml:"artifactId"`
+	Version      string  `xml:"version"`
+	RelativePath *string `xml:"relativePath"` // Nil when not given, so ../pom.xml is used
+}
+
+// Dependency is a dependency element, in dependencies or dependencyManagement
+type Dependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Type       string `xml:"type"`
+	Scope      string `xml:"scope"`
+}
+
+// Build is the build element of a POM or profile
+type Build struct {
+	Plugins          []Plugin `xml:"plugins>plugin"`
+	PluginManagement []Plugin `xml:"pluginManagement>plugins>plugin"`
+}
+
+// POMProfile is a profile declared in a POM
+type POMProfile struct {
+	ID           string       `xml:"id"`
+	Activation   Activation   `xml:"activation"`
+	Properties   Properties   `xml:"properties"`
+	Modules      []string     `xml:"modules>module"`
+	Dependencies []Dependency `xml:"dependencies>dependency"`
+	Build        Build        `xml:"build"`
+}
+
+// Activation holds the conditions that activate a profile
+type Activation struct {
+	ActiveByDefault bool                `xml:"activeByDefault"`
+	JDK             string              `xml:"jdk"`
+	OS              *ActivationOS       `xml:"os"`
+	Property        *ActivationProperty `xml:"property"`
+	File            *ActivationFile     `xml:"file"`
+}
+
+// ActivationOS activates a profile on matching operating systems
+type ActivationOS struct {
+	Name    string `xml:"name"`
+	Family  string `xml:"family"`
+	Arch    string `xml:"arch"`
+	Version string `xml:"version"`
+}
+
+// ActivationProperty activates a profile when a property is set, or set to a value
+type ActivationProperty struct {
+	Name  string `xml:"name"`
+	Value string `xml:"value"`
+}
+
+// ActivationFile activates a profile when a file exists or is missing
+type ActivationFile struct {
+	Exists  string `xml:"exists"`
+	Missing string `xml:"missing"`
+}
+
+// Properties are the properties element of a POM or profile
+type Properties map[string]string
+
+// UnmarshalXML reads each child element as a property
+func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	if *p == nil {
+		*p = make(Properties)
+	}
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			var value string
+			if err := d.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			(*p)[t.Name.Local] = strings.TrimSpace(value)
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// ReadPOM parses the pom.xml at path as written
+func ReadPOM(path string) (*POM, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+
+	var pom POM
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	pom.Path = path
+	return &pom, nil
+}
+
+// LoadPOM reads the pom.xml at path, applies what it inherits from parents
+// found on disk and interpolates ${...} expressions from its properties,
+// the -D options of .mvn/maven.config and the environment. Maven isn't run,
+// so parents only found in a repository are left out.
+func LoadPOM(path string) (*POM, error) {
+	pom, err := readWithParents(path, 0)
+	if err != nil {
+		return nil, err
+	}
+	// Declared plugins without a version take the managed one
+	for i := range pom.Build.Plugins {
+		plugin := &pom.Build.Plugins[i]
+		for _, managed := range pom.Build.PluginManagement {
+			if plugin.Version == "" && managed.key() == plugin.key() {
+				plugin.Version = managed.Version
+			}
+		}
+	}
+	newInterpolator(pom, MavenConfigProperties(filepath.Dir(path))).apply(pom)
+	return pom, nil
+}
+
+// readWithParents reads the POM at path and the chain of parents on disk,
+// merging inherited elements into each POM
+func readWithParents(path string, depth int) (*POM, error) {
+	pom, err := ReadPOM(path)
+	if err != nil {
+		return nil, err
+	}
+	// The coordinates come from the parent element even if it isn't on disk
+	if pom.GroupID == "" {
+		pom.GroupID = pom.Parent.GroupID
+	}
+	if pom.Version == "" {
+		pom.Version = pom.Parent.Version
+	}
+	if depth >= maxParentDepth {
+		return pom, nil
+	}
+
+	parentPath := pom.parentPath()
+	if parentPath == "" {
+		return pom, nil
+	}
+	// A file that isn't the declared parent is ignored, as Maven does
+	parent, err := readWithParents(parentPath, depth+1)
+	if err != nil || parent.GroupID != pom.Parent.GroupID || parent.ArtifactID != pom.Parent.ArtifactID {
+		return pom, nil
+	}
+	pom.ParentPOM = parent
+	pom.inherit(parent)
+	return pom, nil
+}
+
+// parentPath returns the file the parent is looked for in, or "" if the POM
+// has no parent or its relativePath is empty
+func (p *POM) parentPath() string {
+	if p.Parent.ArtifactID == "" {
+		return ""
+	}
+	relative := "../pom.xml"
+	if p.Parent.RelativePath != nil {
+		relative = strings.TrimSpace(*p.Parent.RelativePath)
+	}
+	if relative == "" {
+		return ""
+	}
+
+	path := filepath.Join(filepath.Dir(p.Path), filepath.FromSlash(relative))
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = filepath.Join(path, "pom.xml")
+	}
+	return path
+}
+
+// inherit merges the properties, dependencies and plugins a POM inherits
+// from its parent. Modules, packaging and profiles are not inherited.
+func (p *POM) inherit(parent *POM) {
+	properties := make(Properties, len(parent.Properties)+len(p.Properties))
+	for name, value := range parent.Properties {
+		properties[name] = value
+	}
+	for name, value := range p.Properties {
+		properties[name] = value
+	}
+	p.Properties = properties
+
+	p.DependencyManagement = mergeDependencies(parent.DependencyManagement, p.DependencyManagement)
+	p.Dependencies = mergeDependencies(parent.Dependencies, p.Dependencies)
+	p.Build.Plugins = mergePlugins(parent.Build.Plugins, p.Build.Plugins)
+	p.Build.PluginManagement = mergePlugins(parent.Build.PluginManagement, p.Build.PluginManagement)
+}
+
+// mergeDependencies returns the inherited dependencies followed by the
+// declared ones, which replace inherited ones with the same coordinates
+func mergeDependencies(inherited, declared []Dependency) []Dependency {
+	key := func(d Dependency) string { return d.GroupID + ":" + d.ArtifactID + ":" + d.Type }
+	replaced := make(map[string]bool, len(declared))
+	for _, dep := range declared {
+		replaced[key(dep)] = true
+	}
+
+	var merged []Dependency
+	for _, dep := range inherited {
+		if !replaced[key(dep)] {
+			merged = append(merged, dep)
+		}
+	}
+	return append(merged, declared...)
+}
+
+// mergePlugins returns the inherited plugins followed by the declared ones,
+// which take the inherited version when they don't give one
+func mergePlugins(inherited, declared []Plugin) []Plugin {
+	versions := make(map[string]string, len(inherited))
+	for _, plugin := range inherited {
+		versions[plugin.key()] = plugin.Version
+	}
+
+	var merged []Plugin
+	for _, plugin := range inherited {
+		if !containsPlugin(declared, plugin) {
+			merged = append(merged, plugin)
+		}
+	}
+	for _, plugin := range declared {
+		if plugin.Version == "" {
+			plugin.Version = versions[plugin.key()]
+		}
+		merged = append(merged, plugin)
+	}
+	return merged
+}
+
+// containsPlugin reports whether plugins include one with the coordinates of plugin
+func containsPlugin(plugins []Plugin, plugin Plugin) bool {
+	for _, p := range plugins {
+		if p.key() == plugin.key() {
+			return true
+		}
+	}
+	return false
+}
+
+// key identifies a plugin by groupId and artifactId; groupId defaults to
+// Maven's own plugins
+func (p Plugin) key() string {
+	groupID := p.GroupID
+	if groupID == "" {
+		groupID = defaultPluginGroup
+	}
+	return groupID + ":" + p.ArtifactID
+}
+
+// HasSpringBoot reports whether the POM builds a Spring Boot application:
+// it has a Spring Boot parent, imports the Spring Boot BOM, depends on a
+// starter or uses the Spring Boot plugin
+func (p *POM) HasSpringBoot() bool {
+	for pom := p; pom != nil; pom = pom.ParentPOM {
+		if pom.Parent.GroupID == springBootGroup && pom.Parent.ArtifactID == "spring-boot-starter-parent" {
+			return true
+		}
+	}
+	for _, dep := range p.DependencyManagement {
+		if dep.GroupID == springBootGroup && dep.ArtifactID == "spring-boot-dependencies" && dep.Scope == "import" {
+			return true
+		}
+	}
+	for _, dep := range p.Dependencies {
+		if dep.GroupID == springBootGroup && strings.HasPrefix(dep.ArtifactID, "spring-boot-starter") {
+			return true
+		}
+	}
+	for _, plugin := range p.Build.Plugins {
+		if plugin.ArtifactID == "spring-boot-maven-plugin" {
+			return true
+		}
+	}
+	return false
+}
+
+// MavenConfigProperties returns the properties defined with -D in the
+// .mvn/maven.config of the project containing dir, or nil if it has none
+func MavenConfigProperties(dir string) map[string]string {
+	args := MavenConfigArgs(dir)
+	properties := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		var definition string
+		switch arg := args[i]; {
+		case arg == "-D" || arg == "--define":
+			if i+1 == len(args) {
+				continue
+			}
+			i++
+			definition = args[i]
+		case strings.HasPrefix(arg, "--define="):
+			definition = strings.TrimPrefix(arg, "--define=")
+		case strings.HasPrefix(arg, "-D"):
+			definition = strings.TrimPrefix(arg, "-D")
+		default:
+			continue
+		}
+
+		name, value, found := strings.Cut(definition, "=")
+		if !found {
+			value = "true"
+		}
+		properties[name] = value
+	}
+	return properties
+}
+
+// MavenConfigArgs returns the arguments in the .mvn/maven.config of the
+// project containing dir. Maven reads the file from the nearest directory
+// above the project that has a .mvn directory.
+func MavenConfigArgs(dir string) []string {
+	root := dir
+	for {
+		if info, err := os.Stat(filepath.Join(root, ".mvn")); err == nil && info.IsDir() {
+			break
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return nil
+		}
+		root = parent
+	}
+
+	f, err := os.Open(filepath.Join(root, ".mvn", "maven.config"))
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var args []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words, err := SplitArgs(line)
+		if err != nil {
+			words = strings.Fields(line)
+		}
+		args = append(args, words...)
+	}
+	return args
+}
+
+// expressionPattern matches a ${...} expression
+var expressionPattern = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// maxInterpolationDepth bounds expressions whose values hold expressions
+const maxInterpolationDepth = 10
+
+// interpolator expands ${...} expressions for one POM
+type interpolator struct {
+	values map[string]string
+}
+
+// newInterpolator collects the values expressions in pom can refer to.
+// Properties from maven.config win over the POM's, as -D options do.
+func newInterpolator(pom *POM, userProperties map[string]string) *interpolator {
+	values := make(map[string]string)
+	for _, prefix := range []string{"project.", "pom."} {
+		values[prefix+"groupId"] = pom.GroupID
+		values[prefix+"artifactId"] = pom.ArtifactID
+		values[prefix+"version"] = pom.Version
+		values[prefix+"packaging"] = pom.Packaging
+		values[prefix+"name"] = pom.Name
+		values[prefix+"basedir"] = filepath.Dir(pom.Path)
+		values[prefix+"parent.groupId"] = pom.Parent.GroupID
+		values[prefix+"parent.artifactId"] = pom.Parent.ArtifactID
+		values[prefix+"parent.version"] = pom.Parent.Version
+	}
+	if values["project.packaging"] == "" {
+		values["project.packaging"] = "jar"
+		values["pom.packaging"] = "jar"
+	}
+	values["basedir"] = filepath.Dir(pom.Path)
+	if home, err := os.UserHomeDir(); err == nil {
+		values["user.home"] = home
+	}
+	for name, value := range pom.Properties {
+		values[name] = value
+	}
+	for name, value := range userProperties {
+		values[name] = value
+	}
+	return &interpolator{values: values}
+}
+
+// expand replaces the expressions in s that have a value, leaving the rest
+func (in *interpolator) expand(s string) string {
+	for depth := 0; depth < maxInterpolationDepth && strings.Contains(s, "${"); depth++ {
+		expanded := expressionPattern.ReplaceAllStringFunc(s, func(expression string) string {
+			name := expression[2 : len(expression)-1]
+			if value, ok := in.values[name]; ok {
+				return value
+			}
+			if variable, ok := strings.CutPrefix(name, "env."); ok {
+				if value, ok := os.LookupEnv(variable); ok {
+					return value
+				}
+			}
+			return expression
+		})
+		if expanded == s {
+			break
+		}
+		s = expanded
+	}
+	return s
+}
+
+// apply interpolates the coordinates, properties, modules, dependencies and
+// plugins of pom and its profiles
+func (in *interpolator) apply(pom *POM) {
+	pom.GroupID = in.expand(pom.GroupID)
+	pom.ArtifactID = in.expand(pom.ArtifactID)
+	pom.Version = in.expand(pom.Version)
+	pom.Packaging = in.expand(pom.Packaging)
+	pom.Name = in.expand(pom.Name)
+	pom.Parent.Version = in.expand(pom.Parent.Version)
+	for name, value := range pom.Properties {
+		pom.Properties[name] = in.expand(value)
+	}
+	in.modules(pom.Modules)
+	in.dependencies(pom.DependencyManagement)
+	in.dependencies(pom.Dependencies)
+	in.build(&pom.Build)
+	for i := range pom.Profiles {
+		profile := &pom.Profiles[i]
+		for name, value := range profile.Properties {
+			profile.Properties[name] = in.expand(value)
+		}
+		in.modules(profile.Modules)
+		in.dependencies(profile.Dependencies)
+		in.build(&profile.Build)
+	}
+}
+
+func (in *interpolator) modules(modules []string) {
+	for i := range modules {
+		modules[i] = in.expand(modules[i])
+	}
+}
+
+func (in *interpolator) dependencies(deps []Dependency) {
+	for i := range deps {
+		deps[i].GroupID = in.expand(deps[i].GroupID)
+		deps[i].ArtifactID = in.expand(deps[i].ArtifactID)
+		deps[i].Version = in.expand(deps[i].Version)
+		deps[i].Type = in.expand(deps[i].Type)
+		deps[i].Scope = in.expand(deps[i].Scope)
+	}
+}
+
+func (in *interpolator) build(build *Build) {
+	for _, plugins := range [][]Plugin{build.Plugins, build.PluginManagement} {
+		for i := range plugins {
+			plugins[i].GroupID = in.expand(plugins[i].GroupID)
+			plugins[i].ArtifactID = in.expand(plugins[i].ArtifactID)
+			plugins[i].Version = in.expand(plugins[i].Version)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles writes files under dir, creating directories as needed
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+}
+
+func TestLoadPOM_InheritsAndInterpolates(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("SHOP_DB", "postgres")
+	writeFiles(t, dir, map[string]string{
+		".mvn/maven.config": "-Drevision=2.1.0\n--define changelist=-SNAPSHOT\n-B\n",
+		"build/parent/pom.xml": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>shop-parent</artifactId>
+  <version>${revision}${changelist}</version>
+  <packaging>pom</packaging>
+  <properties>
+    <java.version>17</java.version>
+    <flyway.version>10.10.0</flyway.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.springframework.boot</groupId>
+        <artifactId>spring-boot-dependencies</artifactId>
+        <version>3.2.4</version>
+        <type>pom</type>
+        <scope>import</scope>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <build>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <groupId>org.flywaydb</groupId>
+          <artifactId>flyway-maven-plugin</artifactId>
+          <version>${flyway.version}</version>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+  </build>
+  <profiles>
+    <profile><id>parent-only</id></profile>
+  </profiles>
+</project>`,
+		"pom.xml": `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>shop-parent</artifactId>
+    <version>${revision}${changelist}</version>
+    <relativePath>build/parent</relativePath>
+  </parent>
+  <artifactId>shop</artifactId>
+  <name>${project.artifactId} on Java ${java.version}</name>
+  <properties>
+    <java.version>21</java.version>
+    <db>${env.SHOP_DB}</db>
+  </properties>
+  <modules>
+    <module>shop-${db}</module>
+  </modules>
+  <dependencies>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>shop-api</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.flywaydb</groupId>
+        <artifactId>flyway-maven-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>`,
+	})
+
+	pom, err := LoadPOM(filepath.Join(dir, "pom.xml"))
+	if err != nil {
+		t.Fatalf("Failed to load POM: %v", err)
+	}
+
+	if pom.GroupID != "com.example" || pom.Version != "2.1.0-SNAPSHOT" {
+		t.Errorf("Expected coordinates from the parent and maven.config, got %s:%s", pom.GroupID, pom.Version)
+	}
+	if pom.Name != "shop on Java 21" {
+		t.Errorf("Expected the child's property to win, got name %q", pom.Name)
+	}
+	if !reflect.DeepEqual(pom.Modules, []string{"shop-postgres"}) {
+		t.Errorf("Expected the module named from the environment, got %v", pom.Modules)
+	}
+	if dep := pom.Dependencies[0]; dep.GroupID != "com.example" || dep.Version != "2.1.0-SNAPSHOT" {
+		t.Errorf("Expected the dependency interpolated, got %+v", dep)
+	}
+	if plugin := pom.Build.Plugins[0]; plugin.Version != "10.10.0" {
+		t.Errorf("Expected the plugin version from the parent's management, got %+v", plugin)
+	}
+	if len(pom.Profiles) != 0 {
+		t.Errorf("Expected profiles not to be inherited, got %+v", pom.Profiles)
+	}
+	if pom.ParentPOM == nil || pom.ParentPOM.ArtifactID != "shop-parent" {
+		t.Fatal("Expected the parent to be resolved on disk")
+	}
+	if !pom.HasSpringBoot() {
+		t.Error("Expected the imported Spring Boot BOM to mark a Spring Boot project")
+	}
+}
+
+func TestLoadPOM_ParentNotOnDisk(t *testing.T) {
+	tests := []struct {
+		name   string
+		parent string
+	}{
+		{"empty relativePath", `<relativePath/>`},
+		{"other project in ../pom.xml", ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{
+				"pom.xml": `<project><groupId>org.other</groupId><artifactId>unrelated</artifactId><version>9</version>
+  <properties><greeting>hello</greeting></properties></project>`,
+				"app/pom.xml": `<project>
+  <parent>
+    <groupId>org.springframework.boot</groupId>
+    <artifactId>spring-boot-starter-parent</artifactId>
+    <version>3.2.4</version>
+    ` + tt.parent + `
+  </parent>
+  <artifactId>app</artifactId>
+  <name>${greeting}</name>
+</project>`,
+			})
+
+			pom, err := LoadPOM(filepath.Join(dir, "app", "pom.xml"))
+			if err != nil {
+				t.Fatalf("Failed to load POM: %v", err)
+			}
+			if pom.ParentPOM != nil || pom.Name != "${greeting}" {
+				t.Errorf("Expected the unrelated POM to be ignored, got name %q", pom.Name)
+			}
+			if pom.GroupID != "org.springframework.boot" || pom.Version != "3.2.4" {
+				t.Errorf("Expected coordinates from the parent element, got %s:%s", pom.GroupID, pom.Version)
+			}
+			if !pom.HasSpringBoot() {
+				t.Error("Expected the Spring Boot parent to mark a Spring Boot project")
+			}
+		})
+	}
+}
+
+func TestPOM_HasSpringBootIgnoresManagedPlugin(t *testing.T) {
+	// A company parent managing the plugin for the projects that use it
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pom.xml": `<project><groupId>com.example</groupId><artifactId>library</artifactId><version>1.0</version>
+  <build>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <groupId>org.springframework.boot</groupId>
+          <artifactId>spring-boot-maven-plugin</artifactId>
+          <version>3.2.4</version>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+  </build>
+</project>`,
+	})
+
+	pom, err := LoadPOM(filepath.Join(dir, "pom.xml"))
+	if err != nil {
+		t.Fatalf("Failed to load POM: %v", err)
+	}
+	if pom.HasSpringBoot() {
+		t.Error("Expected a plugin only under pluginManagement not to mark a Spring Boot project")
+	}
+}
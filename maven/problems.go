@@ -0,0 +1,233 @@
+package maven
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProblemKind classifies an entry in the problems list
+type ProblemKind int
+
+const (
+	ProblemError ProblemKind = iota
+	ProblemWarning
+	ProblemTestFailure
+)
+
+// Problem is a compiler diagnostic or failing test reported by a build
+type Problem struct {
+	Kind    ProblemKind
+	Module  string // ArtifactId of the module that reported it, if known
+	File    string // Source file, empty if the problem has no location
+	Line    int
+	Column  int
+	Message string
+	Test    string // Class.method of a failing test
+	Class   string // Class of a failing test, to look up its source
+	Count   int    // How many times Maven reported it
+}
+
+// Location returns the problem's position, e.g. Foo.java:42:17 or FooTest.bar:42
+func (p Problem) Location() string {
+	if p.File == "" {
+		if p.Test != "" && p.Line > 0 {
+			return p.Test + ":" + strconv.Itoa(p.Line)
+		}
+		return p.Test
+	}
+	location := filepath.Base(p.File)
+	if p.Line > 0 {
+		location += ":" + strconv.Itoa(p.Line)
+		if p.Column > 0 {
+			location += ":" + strconv.Itoa(p.Column)
+		}
+	}
+	return location
+}
+
+// problemNoise lists Maven's boilerplate around a failed build, which is
+// reported at ERROR level but doesn't describe a problem in the code
+var problemNoise = []string{
+	"COMPILATION ERROR",
+	"Tests run:",
+	"There are test failures",
+	"Please refer to ",
+	"To see the full stack trace",
+	"Re-run Maven using",
+	"For more information about the errors",
+	"[Help ",
+	"After correcting the problems",
+	"mvn <args>",
+	"symbol:",
+	"location:",
+}
+
+// isProblemNoise reports whether an unlocated message is Maven boilerplate
+func isProblemNoise(msg string) bool {
+	if strings.Contains(msg, "<<< FAILURE!") || strings.Contains(msg, "<<< ERROR!") {
+		return true
+	}
+	for _, prefix := range problemNoise {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectProblems gathers the compiler errors, warnings and failing tests in
+// a build log. Problems Maven repeats, such as compiler errors echoed in the
+// final failure message, are reported once. The result is grouped by module,
+// in build order, then sorted by file and position. The log is read a chunk
+// at a time, so spilled lines aren't loaded in full.
+func CollectProblems(log *LogStore) ([]Problem, error) {
+	c := newProblemCollector()
+	parser := NewLogParser()
+	err := log.Scan(func(line string) bool {
+		for _, event := range parser.Parse(line) {
+			c.apply(event)
+		}
+		return true
+	})
+	for _, event := range parser.Flush() {
+		c.apply(event)
+	}
+	return c.result(), err
+}
+
+// problemCollector turns log events into problems as they are parsed
+type problemCollector struct {
+	problems    []Problem
+	seen        map[Problem]int // Index of each problem, keyed without the module if it has a file
+	moduleOrder map[string]int
+}
+
+// newProblemCollector creates a collector with no problems yet
+func newProblemCollector() *problemCollector {
+	return &problemCollector{seen: map[Problem]int{}, moduleOrder: map[string]int{}}
+}
+
+// add records a problem, or counts it again if Maven repeated it
+func (c *problemCollector) add(p Problem) {
+	if _, ok := c.moduleOrder[p.Module]; !ok {
+		c.moduleOrder[p.Module] = len(c.moduleOrder)
+	}
+
+	// Maven echoes compiler errors without the module, so match on location
+	key := p
+	if p.File != "" {
+		key.Module = ""
+	}
+	if i, ok := c.seen[key]; ok {
+		c.problems[i].Count++
+		return
+	}
+	p.Count = 1
+	c.seen[key] = len(c.problems)
+	c.problems = append(c.problems, p)
+}
+
+// apply records the problem a log event reports, if any
+func (c *problemCollector) apply(event LogEvent) {
+	switch e := event.(type) {
+	case ProblemEvent:
+		if e.File == "" && isProblemNoise(e.Message) {
+			return
+		}
+		kind := ProblemError
+		if e.Severity == SeverityWarning {
+			kind = ProblemWarning
+		}
+		c.add(Problem{
+			Kind:    kind,
+			Module:  e.Module,
+			File:    e.File,
+			Line:    e.Line,
+			Column:  e.Column,
+			Message: e.Message,
+		})
+
+	case TestFailureEvent:
+		test := e.Class
+		if e.Method != "" {
+			test += "." + e.Method
+		}
+		c.add(Problem{
+			Kind:    ProblemTestFailure,
+			Module:  e.Module,
+			Line:    e.Line,
+			Message: e.Message,
+			Test:    test,
+			Class:   e.Class,
+		})
+	}
+}
+
+// result returns the problems grouped by module, then sorted by position
+func (c *problemCollector) result() []Problem {
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		a, b := c.problems[i], c.problems[j]
+		if a.Module != b.Module {
+			return c.moduleOrder[a.Module] < c.moduleOrder[b.Module]
+		}
+		if a.File != b.File {
+			// Problems without a file go after those with one
+			if a.File == "" || b.File == "" {
+				return b.File == ""
+			}
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return c.problems
+}
+
+// testSourceExtensions are the languages test classes are looked up in
+var testSourceExtensions = []string{".java", ".kt", ".groovy", ".scala"}
+
+// FindTestSource looks for the source file of a test class under a module's
+// src/test directory. The class may be simple or fully qualified. It returns
+// an empty string if no source is found.
+func FindTestSource(moduleDir, class string) string {
+	// Inner classes live in the file of their outermost class
+	class, _, _ = strings.Cut(class, "$")
+	simple := class[strings.LastIndex(class, ".")+1:]
+	packageDir := ""
+	if i := strings.LastIndex(class, "."); i >= 0 {
+		packageDir = filepath.FromSlash(strings.ReplaceAll(class[:i], ".", "/"))
+	}
+
+	var found, fallback string
+	root := filepath.Join(moduleDir, "src", "test")
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		for _, ext := range testSourceExtensions {
+			if name != simple+ext {
+				continue
+			}
+			if packageDir == "" || strings.HasSuffix(filepath.Dir(path), string(filepath.Separator)+packageDir) {
+				found = path
+				return fs.SkipAll
+			}
+			if fallback == "" {
+				fallback = path
+			}
+		}
+		return nil
+	})
+
+	if found != "" {
+		return found
+	}
+	return fallback
+}
@@ -0,0 +1,132 @@
+package maven
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// readTestdataLog reads the lines of a recorded Maven log from testdata
+func readTestdataLog(t *testing.T, name string) []string {
+	t.Helper()
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", name, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
+
+// collectTestdataProblems collects the problems in a recorded Maven log, kept
+// in a store small enough to spill most of it to disk
+func collectTestdataProblems(t *testing.T, name string) []Problem {
+	t.Helper()
+	log := NewLogStore(8)
+	defer log.Close()
+	log.Append(readTestdataLog(t, name)...)
+
+	problems, err := CollectProblems(log)
+	if err != nil {
+		t.Fatalf("Failed to collect problems: %v", err)
+	}
+	return problems
+}
+
+func TestCollectProblems_CompileFailure(t *testing.T) {
+	problems := collectTestdataProblems(t, "compile-failure.log")
+
+	var located []Problem
+	for _, p := range problems {
+		if p.File != "" {
+			located = append(located, p)
+		}
+	}
+
+	// Each compiler error is reported twice by Maven but listed once
+	if len(located) != 2 {
+		t.Fatalf("Expected 2 located errors, got %d: %+v", len(located), located)
+	}
+	if located[0].Line != 42 || located[1].Line != 88 || located[0].Count != 2 {
+		t.Errorf("Unexpected errors: %+v", located)
+	}
+	if got := located[0].Location(); got != "InvoiceService.java:42:17" {
+		t.Errorf("Expected location InvoiceService.java:42:17, got %q", got)
+	}
+
+	// Maven's closing advice is not a problem
+	for _, p := range problems {
+		if isProblemNoise(p.Message) {
+			t.Errorf("Boilerplate listed as a problem: %q", p.Message)
+		}
+	}
+}
+
+func TestCollectProblems_TestFailures(t *testing.T) {
+	problems := collectTestdataProblems(t, "test-failure.log")
+
+	var tests, warnings int
+	for _, p := range problems {
+		switch p.Kind {
+		case ProblemTestFailure:
+			tests++
+			if p.Module != "billing-core" {
+				t.Errorf("Expected test failure in billing-core, got %q", p.Module)
+			}
+		case ProblemWarning:
+			warnings++
+		}
+	}
+	if tests != 2 || warnings != 1 {
+		t.Errorf("Expected 2 test failures and 1 warning, got %d and %d: %+v", tests, warnings, problems)
+	}
+
+	// Located problems sort ahead of those without a file
+	if problems[0].File == "" {
+		t.Errorf("Expected the deprecation warning first, got %+v", problems[0])
+	}
+}
+
+func TestFindTestSource(t *testing.T) {
+	moduleDir := t.TempDir()
+	for _, path := range []string{
+		"src/test/java/com/acme/other/InvoiceTest.java",
+		"src/test/java/com/acme/billing/InvoiceTest.java",
+		"src/test/kotlin/com/acme/billing/MoneyTest.kt",
+	} {
+		full := filepath.Join(moduleDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		class string
+		want  string
+	}{
+		{"com.acme.billing.InvoiceTest", "src/test/java/com/acme/billing/InvoiceTest.java"},
+		{"com.acme.billing.InvoiceTest$Nested", "src/test/java/com/acme/billing/InvoiceTest.java"},
+		{"MoneyTest", "src/test/kotlin/com/acme/billing/MoneyTest.kt"},
+		{"MissingTest", ""},
+	}
+
+	for _, tt := range tests {
+		got := FindTestSource(moduleDir, tt.class)
+		want := ""
+		if tt.want != "" {
+			want = filepath.Join(moduleDir, filepath.FromSlash(tt.want))
+		}
+		if got != want {
+			t.Errorf("FindTestSource(%q) = %q, want %q", tt.class, got, want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !windows
+
+package maven
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// startInProcessGroup makes the command the leader of a new process group so
+// that forked JVMs and other children can be signalled together
+func startInProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// interruptProcessGroup sends SIGINT to every process in the group
+func interruptProcessGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGINT)
+}
+
+// killProcessGroup sends SIGKILL to every process in the group
+func killProcessGroup(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGKILL)
+}
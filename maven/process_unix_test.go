@@ -0,0 +1,147 @@
+//go:build !windows
+
+package maven
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// waitForFile polls until path exists, failing the test after a timeout
+func waitForFile(t *testing.T, path string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(path); err == nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %s", path)
+}
+
+func TestExecuteCancelInterruptsProcessGroup(t *testing.T) {
+	tmpDir := t.TempDir()
+	ready := filepath.Join(tmpDir, "ready")
+
+	// Exit cleanly on SIGINT, like Maven does
+	fakeMvn := writeFakeMaven(t, tmpDir, `
+trap 'echo "[INFO] interrupted"; exit 130' INT
+touch `+ready+`
+while true; do sleep 0.05; done
+`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		waitForFile(t, ready)
+		cancel()
+	}()
+
+	result, err := ExecuteWithOptions(ctx, Command{Executable: fakeMvn}, tmpDir, ExecuteOptions{GracePeriod: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	if result.Stopped != Interrupted {
+		t.Errorf("Expected build to be stopped by SIGINT, got %v", result.Stopped)
+	}
+	if result.Duration > 4*time.Second {
+		t.Errorf("Expected SIGINT to stop the build before the grace period, took %v", result.Duration)
+	}
+
+	lines, _ := result.Log.All()
+	joined := strings.Join(lines, "\n")
+	if !strings.Contains(joined, "[INFO] interrupted") {
+		t.Error("Expected the trap handler's output to be collected")
+	}
+	if !strings.Contains(joined, "exited after SIGINT") {
+		t.Errorf("Expected the log to record the SIGINT stage, got:\n%s", joined)
+	}
+}
+
+func TestExecuteCancelEscalatesToKill(t *testing.T) {
+	tmpDir := t.TempDir()
+	ready := filepath.Join(tmpDir, "ready")
+
+	// Ignore SIGINT so only SIGKILL can stop the build
+	fakeMvn := writeFakeMaven(t, tmpDir, `
+trap '' INT
+touch `+ready+`
+while true; do sleep 0.05; done
+`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		waitForFile(t, ready)
+		cancel()
+	}()
+
+	result, err := ExecuteWithOptions(ctx, Command{Executable: fakeMvn}, tmpDir, ExecuteOptions{GracePeriod: 200 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	if result.Stopped != Killed {
+		t.Errorf("Expected build to be killed after the grace period, got %v", result.Stopped)
+	}
+	if result.ExitCode == 0 {
+		t.Error("Expected non-zero exit code after SIGKILL")
+	}
+
+	lines, _ := result.Log.All()
+	if last := lines[len(lines)-1]; !strings.Contains(last, "SIGKILL") {
+		t.Errorf("Expected the log to record the SIGKILL stage, got %q", last)
+	}
+}
+
+func TestExecuteCancelKillsForkedChildren(t *testing.T) {
+	tmpDir := t.TempDir()
+	pidFile := filepath.Join(tmpDir, "child.pid")
+
+	// Fork a child that ignores SIGINT and holds no pipes, like a detached JVM
+	fakeMvn := writeFakeMaven(t, tmpDir, `
+(trap '' INT; exec sleep 30) >/dev/null 2>&1 &
+echo $! > `+pidFile+`.tmp && mv `+pidFile+`.tmp `+pidFile+`
+trap 'exit 130' INT
+while true; do sleep 0.05; done
+`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		waitForFile(t, pidFile)
+		cancel()
+	}()
+
+	if _, err := ExecuteWithOptions(ctx, Command{Executable: fakeMvn}, tmpDir, ExecuteOptions{GracePeriod: time.Second}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("Failed to read child pid: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatalf("Invalid child pid %q: %v", data, err)
+	}
+
+	// The child may linger briefly as a zombie until its parent reaps it
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if err := syscall.Kill(pid, 0); err != nil {
+			return
+		}
+		if state, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat")); err == nil && strings.Contains(string(state), ") Z ") {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	syscall.Kill(pid, syscall.SIGKILL)
+	t.Errorf("Expected forked child %d to be killed with the process group", pid)
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package maven
+
+import (
+	"os"
+	"os/exec"
+)
+
+// startInProcessGroup is a no-op on Windows, which has no POSIX process groups
+func startInProcessGroup(cmd *exec.Cmd) {}
+
+// interruptProcessGroup terminates the process; Windows cannot deliver SIGINT
+// to another console process
+func interruptProcessGroup(p *os.Process) error {
+	return p.Kill()
+}
+
+// killProcessGroup terminates the process
+func killProcessGroup(p *os.Process) error {
+	return p.Kill()
+}
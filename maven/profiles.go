@@ -0,0 +1,77 @@
+package maven
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// loadProfiles lists the profiles of the root POM, of every module, of the
+// parents of both found on disk and of the user and global settings.xml. A profile declared in
+// several places is listed once with all its sources; its activation is
+// the first one's.
+func (p *Project) loadProfiles() {
+	seen := make(map[string]bool) // POM files already read
+	index := make(map[string]int) // Position of each profile ID in p.Profiles
+
+	add := func(declared []POMProfile, source, basedir string) {
+		for i, prof := range declared {
+			id := strings.TrimSpace(prof.ID)
+			if id == "" {
+				continue
+			}
+			if at, ok := index[id]; ok {
+				p.Profiles[at].Sources = append(p.Profiles[at].Sources, source)
+				continue
+			}
+			profile := Profile{ID: id, Sources: []string{source}, basedir: basedir}
+			if prof.Activation != (Activation{}) {
+				profile.Activation = &declared[i].Activation
+			}
+			index[id] = len(p.Profiles)
+			p.Profiles = append(p.Profiles, profile)
+		}
+	}
+	addPOMs := func(pom *POM) {
+		for ; pom != nil; pom = pom.ParentPOM {
+			path := filepath.Clean(pom.Path)
+			if seen[path] {
+				return
+			}
+			seen[path] = true
+			add(pom.Profiles, p.sourceLabel(path), filepath.Dir(path))
+		}
+	}
+
+	addPOMs(p.Model)
+	for _, mod := range p.Modules {
+		addPOMs(mod.Model)
+	}
+	for _, settings := range []*Settings{p.Settings, p.GlobalSettings} {
+		if settings != nil {
+			add(settings.Profiles, p.sourceLabel(settings.Path), p.RootPath)
+		}
+	}
+}
+
+// sourceLabel names a file profiles are declared in by its path from the
+// project root, or from the home directory if it is outside the project
+func (p *Project) sourceLabel(path string) string {
+	if relative, err := filepath.Rel(p.RootPath, path); err == nil && !strings.HasPrefix(relative, "..") {
+		return filepath.ToSlash(relative)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if relative, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(relative, "..") {
+			return "~/" + filepath.ToSlash(relative)
+		}
+	}
+	if relative, err := filepath.Rel(p.RootPath, path); err == nil {
+		return filepath.ToSlash(relative)
+	}
+	return path
+}
+
+// Source describes where the profile is declared, e.g. "pom.xml, core/pom.xml"
+func (p Profile) Source() string {
+	return strings.Join(p.Sources, ", ")
+}
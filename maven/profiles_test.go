@@ -0,0 +1,191 @@
+package maven
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadProject_CollectsProfilesFromEverySource(t *testing.T) {
+	workspace := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("MAVEN_HOME", filepath.Join(home, "maven"))
+	t.Setenv("JAVA_HOME", "")
+
+	writeFiles(t, workspace, map[string]string{
+		"parent/pom.xml": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0</version>
+  <packaging>pom</packaging>
+  <profiles>
+    <profile><id>release</id></profile>
+  </profiles>
+</project>`,
+		"app/pom.xml": `<project>
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0</version>
+    <relativePath>../parent</relativePath>
+  </parent>
+  <artifactId>app</artifactId>
+  <packaging>pom</packaging>
+  <modules><module>core</module><module>web</module></modules>
+  <profiles>
+    <profile><id>ci</id></profile>
+    <profile>
+      <id>local</id>
+      <activation><activeByDefault>true</activeByDefault></activation>
+    </profile>
+  </profiles>
+</project>`,
+		"app/core/pom.xml": `<project>
+  <parent><groupId>com.example</groupId><artifactId>app</artifactId><version>1.0</version></parent>
+  <artifactId>core</artifactId>
+  <profiles>
+    <profile><id>ci</id></profile>
+    <profile>
+      <id>docker</id>
+      <activation><file><exists>Dockerfile</exists></file></activation>
+    </profile>
+  </profiles>
+</project>`,
+		"app/core/Dockerfile": "FROM scratch\n",
+		"app/web/pom.xml": `<project>
+  <parent><groupId>com.example</groupId><artifactId>app</artifactId><version>1.0</version></parent>
+  <artifactId>web</artifactId>
+  <profiles>
+    <profile>
+      <id>dev-server</id>
+      <activation><activeByDefault>true</activeByDefault></activation>
+    </profile>
+  </profiles>
+</project>`,
+	})
+	writeFiles(t, home, map[string]string{".m2/settings.xml": `<settings>
+  <profiles>
+    <profile><id>nexus</id></profile>
+    <profile><id>ci</id></profile>
+  </profiles>
+  <activeProfiles><activeProfile>nexus</activeProfile></activeProfiles>
+</settings>`})
+
+	project, err := LoadProject(filepath.Join(workspace, "app"))
+	if err != nil {
+		t.Fatalf("Failed to load project: %v", err)
+	}
+
+	type summary struct {
+		ID, Source, Reason string
+		AutoActive         bool
+	}
+	var got []summary
+	for _, prof := range project.Profiles {
+		got = append(got, summary{prof.ID, prof.Source(), prof.Reason, prof.AutoActive})
+	}
+	want := []summary{
+		{"ci", "pom.xml, core/pom.xml, ~/.m2/settings.xml", "", false},
+		{"local", "pom.xml", "activeByDefault", true},
+		{"release", "../parent/pom.xml", "", false},
+		{"docker", "core/pom.xml", "Dockerfile exists", true},
+		{"dev-server", "web/pom.xml", "activeByDefault", true},
+		{"nexus", "~/.m2/settings.xml", "activeProfiles in settings.xml", true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Profiles =\n%+v\nwant\n%+v", got, want)
+	}
+
+	// A profile found in several files is toggled once
+	project.ToggleProfile(0)
+	cmd := BuildCommand(project, []string{"verify"}, BuildOptions{})
+	if args := strings.Join(cmd.Args, " "); args != "-P ci,local,dev-server verify" {
+		t.Errorf("Expected ci enabled once, got %q", args)
+	}
+}
+
+func TestSettingsPaths_PreferOptionsThenMavenConfig(t *testing.T) {
+	dir := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("MAVEN_HOME", filepath.Join(home, "maven"))
+
+	if got, want := UserSettingsPath(dir, BuildOptions{}), filepath.Join(home, ".m2", "settings.xml"); got != want {
+		t.Errorf("UserSettingsPath() = %q, want %q", got, want)
+	}
+	if got, want := GlobalSettingsPath(dir, BuildOptions{}), filepath.Join(home, "maven", "conf", "settings.xml"); got != want {
+		t.Errorf("GlobalSettingsPath() = %q, want %q", got, want)
+	}
+
+	writeFiles(t, dir, map[string]string{".mvn/maven.config": "-B\n-s .mvn/settings.xml\n--global-settings=/etc/maven/settings.xml\n"})
+	if got, want := UserSettingsPath(dir, BuildOptions{}), filepath.Join(dir, ".mvn", "settings.xml"); got != want {
+		t.Errorf("UserSettingsPath() = %q, want %q", got, want)
+	}
+	if got, want := GlobalSettingsPath(dir, BuildOptions{}), "/etc/maven/settings.xml"; got != want {
+		t.Errorf("GlobalSettingsPath() = %q, want %q", got, want)
+	}
+
+	// The options view wins over maven.config
+	options := BuildOptions{Settings: "ci/settings.xml", GlobalSettings: "ci/global.xml"}
+	if got, want := UserSettingsPath(dir, options), filepath.Join(dir, "ci", "settings.xml"); got != want {
+		t.Errorf("UserSettingsPath() = %q, want %q", got, want)
+	}
+	if got, want := GlobalSettingsPath(dir, options), filepath.Join(dir, "ci", "global.xml"); got != want {
+		t.Errorf("GlobalSettingsPath() = %q, want %q", got, want)
+	}
+}
+
+func TestProject_LoadSettingsFollowsOptions(t *testing.T) {
+	dir := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("MAVEN_HOME", filepath.Join(home, "maven"))
+	t.Setenv("JAVA_HOME", "")
+
+	writeFiles(t, dir, map[string]string{
+		"pom.xml": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <profiles><profile><id>local</id></profile></profiles>
+</project>`,
+		"ci/settings.xml": `<settings>
+  <profiles><profile><id>mirror</id></profile></profiles>
+  <activeProfiles><activeProfile>mirror</activeProfile></activeProfiles>
+</settings>`,
+	})
+	writeFiles(t, home, map[string]string{
+		".m2/settings.xml":        `<settings><profiles><profile><id>nexus</id></profile></profiles></settings>`,
+		"maven/conf/settings.xml": `<settings><profiles><profile><id>corporate</id></profile></profiles></settings>`,
+	})
+
+	project, err := LoadProject(dir)
+	if err != nil {
+		t.Fatalf("Failed to load project: %v", err)
+	}
+	project.EnableProfile("local")
+	project.EnableProfile("nexus")
+
+	options := BuildOptions{Settings: "ci/settings.xml"}
+	project.LoadSettings(options)
+	project.EvaluateProfiles(NewActivationContext(project, options))
+
+	var ids []string
+	for _, prof := range project.Profiles {
+		ids = append(ids, prof.ID)
+	}
+	if got := strings.Join(ids, ","); got != "local,mirror,corporate,nexus" {
+		t.Errorf("Expected the chosen settings' profiles, got %s", got)
+	}
+	if args := strings.Join(project.ProfileArgs(), ","); args != "local,nexus" {
+		t.Errorf("Expected the profiles turned on to stay on, got %s", args)
+	}
+	if !project.Profiles[1].AutoActive {
+		t.Errorf("Expected mirror to be activated by the chosen settings.xml, got %+v", project.Profiles[1])
+	}
+}
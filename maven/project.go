@@ -2,7 +2,6 @@ package maven
 
 import (
 	"bufio"
-	"encoding/xml"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,56 +11,61 @@ import (
 
 // Project represents a Maven project
 type Project struct {
-	RootPath      string
-	PomPath       string
-	GroupID       string
-	ArtifactID    string
-	Version       string
-	Packaging     string
-	Modules       []Module
-	Profiles      []Profile
-	Executable    string
-	HasSpringBoot bool
+	RootPath       string
+	PomPath        string
+	GroupID        string
+	ArtifactID     string
+	Version        string
+	Packaging      string
+	Modules        []Module
+	Profiles       []Profile
+	Plugins        []Plugin // Declared or managed in the POM's build section
+	Executable     string
+	HasSpringBoot  bool
+	Model          *POM      // The root POM with its parents applied and interpolated
+	Settings       *Settings // The user settings.xml Maven reads, nil if there is none
+	GlobalSettings *Settings // The global settings.xml Maven reads, nil if there is none
 }
 
 // Module represents a Maven module
 type Module struct {
-	Name     string
-	Path     string
-	Selected bool
+	Name       string // Path from the project root, e.g. services/orders
+	Path       string
+	GroupID    string
+	ArtifactID string
+	Packaging  string
+	Parent     string // Name of the aggregator listing the module, empty for the root's
+	Depth      int    // Levels of aggregators between the module and the root
+	Selected   bool
+	Hidden     bool // Left out of the modules pane, but still built with the reactor
+	Model      *POM // The module's POM, nil if it couldn't be read
 }
 
 // Profile represents a Maven profile
 type Profile struct {
-	ID      string
-	Enabled bool
+	ID         string
+	Enabled    bool        // Turned on with -P id
+	Disabled   bool        // Turned off with -P !id, though Maven would activate it
+	AutoActive bool        // Maven activates it on its own; see EvaluateProfiles
+	ByDefault  bool        // AutoActive through activeByDefault, which -P turns off
+	Reason     string      // Why Maven activates it, or why activeByDefault doesn't apply
+	Activation *Activation // Conditions from the POM, nil if it has none
+	Sources    []string    // Files declaring it, e.g. pom.xml or ~/.m2/settings.xml
+
+	basedir string // Directory file conditions are relative to, the root if empty
 }
 
-// POM represents the minimal structure we need from pom.xml
-type POM struct {
-	XMLName    xml.Name `xml:"project"`
-	GroupID    string   `xml:"groupId"`
-	ArtifactID string   `xml:"artifactId"`
-	Version    string   `xml:"version"`
-	Packaging  string   `xml:"packaging"`
-	Modules    struct {
-		Module []string `xml:"module"`
-	} `xml:"modules"`
-	Profiles struct {
-		Profile []struct {
-			ID string `xml:"id"`
-		} `xml:"profile"`
-	} `xml:"profiles"`
-	Dependencies struct {
-		Dependency []struct {
-			GroupID    string `xml:"groupId"`
-			ArtifactID string `xml:"artifactId"`
-		} `xml:"dependency"`
-	} `xml:"dependencies"`
-	Parent struct {
-		GroupID    string `xml:"groupId"`
-		ArtifactID string `xml:"artifactId"`
-	} `xml:"parent"`
+// Active reports whether Maven builds with the profile
+func (p Profile) Active() bool {
+	return p.Enabled || p.AutoActive && !p.Disabled
+}
+
+// Plugin is a build plugin declared in the POM
+type Plugin struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Managed    bool   `xml:"-"` // Only listed under pluginManagement
 }
 
 // FindProjectRoot locates the project root by walking up from the current directory
@@ -94,15 +98,9 @@ func FindMavenExecutable(projectRoot string) string {
 // LoadProject loads a Maven project from the given root directory
 func LoadProject(rootPath string) (*Project, error) {
 	pomPath := filepath.Join(rootPath, "pom.xml")
-
-	data, err := os.ReadFile(pomPath)
+	pom, err := LoadPOM(pomPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
-	}
-
-	var pom POM
-	if err := xml.Unmarshal(data, &pom); err != nil {
-		return nil, fmt.Errorf("failed to parse pom.xml: %w", err)
+		return nil, err
 	}
 
 	// Default packaging to jar if not specified
@@ -111,18 +109,6 @@ func LoadProject(rootPath string) (*Project, error) {
 		packaging = "jar"
 	}
 
-	// Detect Spring Boot
-	hasSpringBoot := false
-	for _, dep := range pom.Dependencies.Dependency {
-		if dep.GroupID == "org.springframework.boot" && dep.ArtifactID == "spring-boot-starter" {
-			hasSpringBoot = true
-			break
-		}
-	}
-	if pom.Parent.GroupID == "org.springframework.boot" && pom.Parent.ArtifactID == "spring-boot-starter-parent" {
-		hasSpringBoot = true
-	}
-
 	project := &Project{
 		RootPath:      rootPath,
 		PomPath:       pomPath,
@@ -131,41 +117,116 @@ func LoadProject(rootPath string) (*Project, error) {
 		Version:       pom.Version,
 		Packaging:     packaging,
 		Executable:    FindMavenExecutable(rootPath),
-		HasSpringBoot: hasSpringBoot,
+		HasSpringBoot: pom.HasSpringBoot(),
+		Model:         pom,
 	}
 
-	// Load modules
-	for _, modName := range pom.Modules.Module {
-		project.Modules = append(project.Modules, Module{
-			Name:     modName,
-			Path:     filepath.Join(rootPath, modName),
-			Selected: true,
-		})
-	}
+	// Load modules, following nested aggregators
+	project.Modules = loadModules(rootPath, pom)
 
-	// Load profiles
-	for _, prof := range pom.Profiles.Profile {
-		project.Profiles = append(project.Profiles, Profile{
-			ID:      prof.ID,
-			Enabled: false,
-		})
+	// Load profiles from the POMs and settings.xml, and work out which Maven
+	// activates on this machine
+	project.LoadSettings(BuildOptions{})
+	project.EvaluateProfiles(NewActivationContext(project, BuildOptions{}))
+
+	// Load build plugins, then the managed ones not declared, which still
+	// resolve by prefix
+	project.Plugins = pomPlugins(pom.Build.Plugins, false)
+	for _, managed := range pomPlugins(pom.Build.PluginManagement, true) {
+		if !containsPlugin(project.Plugins, managed) {
+			project.Plugins = append(project.Plugins, managed)
+		}
 	}
 
 	return project, nil
 }
 
-// ToggleModule toggles the selected state of a module
+// pomPlugins returns plugin elements with their groupId filled in
+func pomPlugins(elements []Plugin, managed bool) []Plugin {
+	plugins := make([]Plugin, len(elements))
+	for i, plugin := range elements {
+		if plugin.GroupID == "" {
+			plugin.GroupID = defaultPluginGroup
+		}
+		plugin.Managed = managed
+		plugins[i] = plugin
+	}
+	return plugins
+}
+
+// ToggleModule toggles the selected state of a module, and of the modules
+// below it if it is an aggregator
 func (p *Project) ToggleModule(index int) {
 	if index >= 0 && index < len(p.Modules) {
-		p.Modules[index].Selected = !p.Modules[index].Selected
+		selected := !p.Modules[index].Selected
+		for i := index; i < p.subtree(index); i++ {
+			p.Modules[i].Selected = selected
+		}
 	}
 }
 
-// ToggleProfile toggles the enabled state of a profile
+// ToggleProfile toggles a profile: one Maven activates on its own is
+// disabled or left to Maven again, any other is enabled or disabled
 func (p *Project) ToggleProfile(index int) {
-	if index >= 0 && index < len(p.Profiles) {
-		p.Profiles[index].Enabled = !p.Profiles[index].Enabled
+	if index < 0 || index >= len(p.Profiles) {
+		return
+	}
+	prof := &p.Profiles[index]
+	if prof.AutoActive && !prof.Enabled {
+		prof.Disabled = !prof.Disabled
+		return
+	}
+	prof.Enabled = !prof.Enabled
+	prof.Disabled = false
+}
+
+// SelectModules selects exactly the named modules, or every module if names
+// is empty. Nothing changes if a name is not a module of the project.
+func (p *Project) SelectModules(names []string) error {
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !p.hasModule(name) {
+			return fmt.Errorf("unknown module %q", name)
+		}
+		selected[name] = true
 	}
+
+	for i := range p.Modules {
+		p.Modules[i].Selected = len(names) == 0 || selected[p.Modules[i].Name]
+	}
+	return nil
+}
+
+// hasModule reports whether the project has a module with the given name
+func (p *Project) hasModule(name string) bool {
+	for _, mod := range p.Modules {
+		if mod.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EnableProfile enables the profile with the given ID. Profiles not declared
+// in the POM, such as ones from settings.xml, are added so Maven still gets them.
+func (p *Project) EnableProfile(id string) {
+	for i := range p.Profiles {
+		if p.Profiles[i].ID == id {
+			p.Profiles[i].Enabled = true
+			p.Profiles[i].Disabled = false
+			return
+		}
+	}
+	p.Profiles = append(p.Profiles, Profile{ID: id, Enabled: true})
+}
+
+// Clone returns a copy of the project whose module and profile state can be
+// changed without affecting p
+func (p *Project) Clone() *Project {
+	clone := *p
+	clone.Modules = append([]Module(nil), p.Modules...)
+	clone.Profiles = append([]Profile(nil), p.Profiles...)
+	return &clone
 }
 
 // GetSelectedModules returns the names of selected modules
@@ -190,6 +251,25 @@ func (p *Project) GetEnabledProfiles() []string {
 	return enabled
 }
 
+// ProfileArgs returns the value of -P: the enabled profiles and, prefixed
+// with !, the disabled ones. Naming any profile in -P turns off the
+// activeByDefault ones, so those are named too to keep them active.
+func (p *Project) ProfileArgs() []string {
+	enabled := len(p.GetEnabledProfiles()) > 0
+	var args []string
+	for _, prof := range p.Profiles {
+		switch {
+		case prof.Enabled:
+			args = append(args, prof.ID)
+		case prof.Disabled:
+			args = append(args, "!"+prof.ID)
+		case enabled && prof.ByDefault:
+			args = append(args, prof.ID)
+		}
+	}
+	return args
+}
+
 // FindMainClass searches for a Java class with a main method in the project
 // It returns the fully qualified class name (e.g., "com.example.App")
 func (p *Project) FindMainClass() string {
@@ -0,0 +1,95 @@
+package maven
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Property is a -D system property kept in the properties panel or set by
+// the config files
+type Property struct {
+	Name     string `json:"name"`
+	Value    string `json:"value,omitempty"`
+	HasValue bool   `json:"hasValue,omitempty"` // An = was given, so an empty value passes -Dname=
+	Enabled  bool   `json:"enabled"`
+}
+
+// String returns the property as written after -D. Without a value it is
+// the name alone, which Maven sets to true.
+func (p Property) String() string {
+	if p.Value == "" && !p.HasValue {
+		return p.Name
+	}
+	return p.Name + "=" + p.Value
+}
+
+// ParseProperty parses name=value, or a name alone, as written after -D.
+// The value is taken as is, spaces included, and name= sets it empty.
+func ParseProperty(s string) (Property, error) {
+	name, value, found := strings.Cut(s, "=")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Property{}, fmt.Errorf("enter a property, e.g. test=MyTest")
+	}
+	if strings.ContainsAny(name, " \t") {
+		return Property{}, fmt.Errorf("property name %q contains a space", name)
+	}
+	return Property{Name: name, Value: value, HasValue: found, Enabled: true}, nil
+}
+
+// MergeProperties returns base with the enabled properties of more added,
+// each replacing an earlier property of the same name. Base isn't modified.
+func MergeProperties(base []Property, more ...Property) []Property {
+	merged := slices.Clone(base)
+	for _, prop := range more {
+		if !prop.Enabled {
+			continue
+		}
+		if i := slices.IndexFunc(merged, func(p Property) bool { return p.Name == prop.Name }); i >= 0 {
+			merged[i] = prop
+		} else {
+			merged = append(merged, prop)
+		}
+	}
+	return merged
+}
+
+// EnabledProperties returns the enabled properties, once per name. A later
+// property replaces an earlier one of the same name.
+func EnabledProperties(properties []Property) []Property {
+	return MergeProperties(nil, properties...)
+}
+
+// DeclaredProperties returns the names of the properties declared in the
+// POMs of the project, its modules and their profiles, in order
+func (p *Project) DeclaredProperties() []string {
+	seen := make(map[string]bool)
+	add := func(properties Properties) {
+		for name := range properties {
+			seen[name] = true
+		}
+	}
+	addPOM := func(pom *POM) {
+		if pom == nil {
+			return
+		}
+		add(pom.Properties)
+		for _, prof := range pom.Profiles {
+			add(prof.Properties)
+		}
+	}
+
+	addPOM(p.Model)
+	for _, mod := range p.Modules {
+		addPOM(mod.Model)
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,63 @@
+package maven
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseProperty(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Property
+		wantErr bool
+	}{
+		{input: "test=MyTest", want: Property{Name: "test", Value: "MyTest", HasValue: true, Enabled: true}},
+		{input: "skipITs", want: Property{Name: "skipITs", Enabled: true}},
+		{input: "suffix=", want: Property{Name: "suffix", HasValue: true, Enabled: true}},
+		{input: " msg=two words ", want: Property{Name: "msg", Value: "two words ", HasValue: true, Enabled: true}},
+		{input: "argLine=-Da=b", want: Property{Name: "argLine", Value: "-Da=b", HasValue: true, Enabled: true}},
+		{input: "=value", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "two words=x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseProperty(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseProperty(%q): error %v, want error %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseProperty(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestProject_DeclaredProperties(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pom.xml": `<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <properties><java.version>21</java.version><skipITs>true</skipITs></properties>
+  <modules><module>core</module></modules>
+  <profiles>
+    <profile><id>ci</id><properties><sonar.host.url>https://sonar</sonar.host.url></properties></profile>
+  </profiles>
+</project>`,
+		"core/pom.xml": `<project>
+  <parent><groupId>com.example</groupId><artifactId>app</artifactId><version>1.0</version></parent>
+  <artifactId>core</artifactId>
+  <properties><jacoco.skip>false</jacoco.skip></properties>
+</project>`,
+	})
+
+	project, err := LoadProject(dir)
+	if err != nil {
+		t.Fatalf("Failed to load project: %v", err)
+	}
+	want := []string{"jacoco.skip", "java.version", "skipITs", "sonar.host.url"}
+	if got := project.DeclaredProperties(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DeclaredProperties() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package maven
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// PTYCommand runs a command in the foreground under a pseudo-terminal, so
+// interactive programs (e.g. ones reading from a Scanner) behave as they would
+// in a shell. The user's terminal is relayed to the command and everything
+// shown on screen is recorded in a log. The command is started directly,
+// without a shell, so arguments are passed through unchanged.
+//
+// PTYCommand implements bubbletea's ExecCommand, so it can be run with tea.Exec
+// while the TUI is suspended.
+type PTYCommand struct {
+	Command Command
+	Dir     string
+	Log     *LogStore
+	Result  *ExecutionResult // Set once Run returns
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// NewPTYCommand creates a PTYCommand that records its transcript into log.
+// A nil log gets a new LogStore.
+func NewPTYCommand(cmd Command, workDir string, log *LogStore) *PTYCommand {
+	if log == nil {
+		log = NewLogStore(DefaultLogWindow)
+	}
+	return &PTYCommand{
+		Command: cmd,
+		Dir:     workDir,
+		Log:     log,
+		stdin:   os.Stdin,
+		stdout:  os.Stdout,
+		stderr:  os.Stderr,
+	}
+}
+
+// SetStdin sets the terminal input relayed to the command
+func (c *PTYCommand) SetStdin(r io.Reader) { c.stdin = r }
+
+// SetStdout sets where the command's terminal output is shown
+func (c *PTYCommand) SetStdout(w io.Writer) { c.stdout = w }
+
+// SetStderr is accepted for ExecCommand; a terminal has a single output
+func (c *PTYCommand) SetStderr(w io.Writer) { c.stderr = w }
+
+// Run starts the command, relays the terminal until it exits and records
+// the result. The returned error is the command's exit error, if any.
+func (c *PTYCommand) Run() error {
+	c.Result = &ExecutionResult{
+		Command:   c.Command,
+		StartTime: time.Now(),
+		Log:       c.Log,
+	}
+
+	transcript := newTerminalTranscript(c.Log)
+	err := c.run(transcript)
+	transcript.Flush()
+	c.Result.Duration = time.Since(c.Result.StartTime)
+
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			c.Result.ExitCode = exitErr.ExitCode()
+		} else {
+			c.Result.ExitCode = 1
+			c.Result.Error = err
+		}
+	}
+	return err
+}
+
+// ErrTerminalUnsupported is returned where embedded terminals are not supported
+var ErrTerminalUnsupported = errors.New("embedded terminals are not supported on this platform")
+
+// fileDescriptor is implemented by terminals such as *os.File
+type fileDescriptor interface {
+	Fd() uintptr
+}
@@ -0,0 +1,168 @@
+//go:build !windows
+
+package maven
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/creack/pty"
+)
+
+// PTYSession is a command running in the background on a pseudo-terminal,
+// for interactive programs shown inside the TUI rather than in place of it.
+// Keystrokes are sent with Write; output is recorded as a plain transcript in
+// Log and, with colors kept, in Screen for display.
+type PTYSession struct {
+	Command Command
+	Log     *LogStore
+	Screen  *LogStore
+
+	mu         sync.Mutex // Guards transcript
+	transcript *terminalTranscript
+	ptmx       *os.File
+	done       chan struct{}
+	result     *ExecutionResult
+}
+
+// StartPTYSession starts cmd on a new pseudo-terminal of the given size.
+// onOutput is called after each chunk of output has been recorded. Cancelling
+// ctx stops the command like a cancelled build: SIGINT, then SIGKILL after
+// DefaultGracePeriod.
+func StartPTYSession(ctx context.Context, cmd Command, workDir string, log *LogStore, cols, rows int, onOutput func()) (*PTYSession, error) {
+	if log == nil {
+		log = NewLogStore(DefaultLogWindow)
+	}
+	s := &PTYSession{
+		Command: cmd,
+		Log:     log,
+		Screen:  NewLogStore(DefaultLogWindow),
+		done:    make(chan struct{}),
+		result: &ExecutionResult{
+			Command:   cmd,
+			StartTime: time.Now(),
+			Log:       log,
+		},
+	}
+	s.transcript = newTerminalTranscript(log)
+	s.transcript.styled = s.Screen
+
+	execCmd := cmd.execCommand()
+	execCmd.Dir = workDir
+
+	// The command leads a new session, and so its own process group
+	ptmx, err := pty.StartWithSize(execCmd, &pty.Winsize{Cols: uint16(max(cols, 1)), Rows: uint16(max(rows, 1))})
+	if err != nil {
+		return nil, err
+	}
+	s.ptmx = ptmx
+
+	go s.run(ctx, execCmd, onOutput)
+	return s, nil
+}
+
+// run records output until the command exits, then settles the result
+func (s *PTYSession) run(ctx context.Context, execCmd *exec.Cmd, onOutput func()) {
+	defer close(s.done)
+	defer s.ptmx.Close()
+
+	exited := make(chan struct{})
+	stopped := make(chan StopStage, 1)
+	go func() {
+		stopped <- stopOnCancel(ctx, execCmd.Process, DefaultGracePeriod, exited)
+	}()
+
+	// Reading fails with EIO once every process has closed the terminal
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := s.ptmx.Read(buf)
+			if n > 0 {
+				s.mu.Lock()
+				s.transcript.Write(buf[:n])
+				s.mu.Unlock()
+				if onOutput != nil {
+					onOutput()
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	err := execCmd.Wait()
+	close(exited)
+	s.result.Stopped = <-stopped
+
+	select {
+	case <-drained:
+	case <-time.After(ptyDrainTimeout):
+		s.ptmx.Close()
+		<-drained
+	}
+
+	s.mu.Lock()
+	s.transcript.Flush()
+	s.mu.Unlock()
+
+	switch s.result.Stopped {
+	case Interrupted:
+		s.Log.Append("", "Stopped: process group exited after SIGINT")
+	case Killed:
+		s.Log.Append("", fmt.Sprintf("Stopped: process group killed with SIGKILL after %v grace period", DefaultGracePeriod))
+	}
+
+	s.result.Duration = time.Since(s.result.StartTime)
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			s.result.ExitCode = exitErr.ExitCode()
+		} else {
+			s.result.ExitCode = 1
+			s.result.Error = err
+		}
+	}
+	if onOutput != nil {
+		onOutput()
+	}
+}
+
+// Write sends input to the command as if typed at its terminal
+func (s *PTYSession) Write(p []byte) (int, error) {
+	select {
+	case <-s.done:
+		return 0, fmt.Errorf("terminal session has exited")
+	default:
+	}
+	return s.ptmx.Write(p)
+}
+
+// Resize changes the size of the pseudo-terminal
+func (s *PTYSession) Resize(cols, rows int) error {
+	select {
+	case <-s.done:
+		return nil
+	default:
+	}
+	return pty.Setsize(s.ptmx, &pty.Winsize{Cols: uint16(max(cols, 1)), Rows: uint16(max(rows, 1))})
+}
+
+// PendingLine returns the line the command is currently writing, with its
+// colors, e.g. a prompt waiting for input
+func (s *PTYSession) PendingLine() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.transcript.styledLine()
+}
+
+// Wait blocks until the command has exited and returns its result
+func (s *PTYSession) Wait() *ExecutionResult {
+	<-s.done
+	return s.result
+}
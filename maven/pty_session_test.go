@@ -0,0 +1,102 @@
+//go:build !windows
+
+package maven
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// waitForOutput polls until cond holds for the session's output
+func waitForOutput(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %s", what)
+}
+
+// logContains reports whether any line of log contains s
+func logContains(log *LogStore, s string) bool {
+	lines, _ := log.All()
+	return strings.Contains(strings.Join(lines, "\n"), s)
+}
+
+func TestPTYSession_InteractiveInput(t *testing.T) {
+	tmpDir := t.TempDir()
+	fakeMvn := writeFakeMaven(t, tmpDir, `
+printf '\033[32mStarted\033[0m\n'
+printf "Name: "
+read name
+echo "Hello $name"
+`)
+
+	outputs := make(chan struct{}, 100)
+	session, err := StartPTYSession(context.Background(), Command{Executable: fakeMvn}, tmpDir, nil, 80, 24, func() {
+		select {
+		case outputs <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+
+	// The prompt has no newline yet, so it is the pending line
+	waitForOutput(t, "the prompt", func() bool { return session.PendingLine() == "Name: " })
+
+	if _, err := session.Write([]byte("Bob\r")); err != nil {
+		t.Fatalf("Failed to send input: %v", err)
+	}
+
+	result := session.Wait()
+	if result.ExitCode != 0 || result.Error != nil {
+		t.Errorf("Expected a clean exit, got %d (%v)", result.ExitCode, result.Error)
+	}
+	if !logContains(session.Log, "Hello Bob") || !logContains(session.Log, "Name: Bob") {
+		t.Errorf("Expected the transcript to include the echoed input and reply")
+	}
+	if logContains(session.Log, "\x1b") {
+		t.Error("Expected a plain transcript")
+	}
+	if !logContains(session.Screen, "\x1b[32mStarted") {
+		t.Error("Expected the screen to keep colors")
+	}
+	if len(outputs) == 0 {
+		t.Error("Expected output notifications")
+	}
+}
+
+func TestPTYSession_CancelStopsCommand(t *testing.T) {
+	tmpDir := t.TempDir()
+	fakeMvn := writeFakeMaven(t, tmpDir, `
+trap 'echo "shutting down"; exit 130' INT
+echo "ready"
+while true; do sleep 0.05; done
+`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session, err := StartPTYSession(ctx, Command{Executable: fakeMvn}, tmpDir, nil, 80, 24, nil)
+	if err != nil {
+		t.Fatalf("Failed to start session: %v", err)
+	}
+	waitForOutput(t, "the command to start", func() bool { return logContains(session.Log, "ready") })
+
+	cancel()
+	result := session.Wait()
+	if result.Stopped != Interrupted || result.ExitCode != 130 {
+		t.Errorf("Expected exit 130 after SIGINT, got %d (%v)", result.ExitCode, result.Stopped)
+	}
+	if !logContains(session.Log, "shutting down") {
+		t.Error("Expected output written while stopping to be recorded")
+	}
+	if _, err := session.Write([]byte("x")); err == nil {
+		t.Error("Expected writing to an exited session to fail")
+	}
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package maven
+
+import "context"
+
+// PTYSession is not available on Windows; see StartPTYSession
+type PTYSession struct {
+	Command Command
+	Log     *LogStore
+	Screen  *LogStore
+}
+
+// StartPTYSession always fails on Windows; callers fall back to PTYCommand
+func StartPTYSession(ctx context.Context, cmd Command, workDir string, log *LogStore, cols, rows int, onOutput func()) (*PTYSession, error) {
+	return nil, ErrTerminalUnsupported
+}
+
+func (s *PTYSession) Write(p []byte) (int, error) { return 0, ErrTerminalUnsupported }
+func (s *PTYSession) Resize(cols, rows int) error { return ErrTerminalUnsupported }
+func (s *PTYSession) PendingLine() string         { return "" }
+func (s *PTYSession) Wait() *ExecutionResult      { return nil }
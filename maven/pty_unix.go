@@ -0,0 +1,93 @@
+//go:build !windows
+
+package maven
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/creack/pty"
+	"github.com/muesli/cancelreader"
+)
+
+// ptyDrainTimeout bounds how long output is read after the command exits,
+// in case a stray child keeps the terminal open
+const ptyDrainTimeout = 2 * time.Second
+
+// run starts the command on a new pseudo-terminal and relays the terminal
+// until the command exits
+func (c *PTYCommand) run(transcript io.Writer) error {
+	execCmd := c.Command.execCommand()
+	execCmd.Dir = c.Dir
+
+	// Size the pseudo-terminal like the user's terminal
+	input, isTerminal := c.stdin.(fileDescriptor)
+	isTerminal = isTerminal && term.IsTerminal(input.Fd())
+	var size *pty.Winsize
+	if isTerminal {
+		if width, height, err := term.GetSize(input.Fd()); err == nil {
+			size = &pty.Winsize{Cols: uint16(width), Rows: uint16(height)}
+		}
+	}
+
+	ptmx, err := pty.StartWithSize(execCmd, size)
+	if err != nil {
+		return err
+	}
+	defer ptmx.Close()
+
+	// Pass keys through untouched; the pseudo-terminal does echo and line editing
+	if isTerminal {
+		state, err := term.MakeRaw(input.Fd())
+		if err == nil {
+			defer term.Restore(input.Fd(), state)
+		}
+
+		// Follow resizes of the user's terminal
+		resized := make(chan os.Signal, 1)
+		signal.Notify(resized, syscall.SIGWINCH)
+		defer func() {
+			signal.Stop(resized)
+			close(resized)
+		}()
+		go func() {
+			for range resized {
+				if width, height, err := term.GetSize(input.Fd()); err == nil {
+					pty.Setsize(ptmx, &pty.Winsize{Cols: uint16(width), Rows: uint16(height)})
+				}
+			}
+		}()
+	}
+
+	// Relay input until the command exits. The reader is cancelled afterwards
+	// so it doesn't swallow the first key meant for the TUI.
+	if c.stdin != nil {
+		reader, err := cancelreader.NewReader(c.stdin)
+		if err == nil {
+			defer reader.Cancel()
+			go io.Copy(ptmx, reader)
+		}
+	}
+
+	// Show output and record it; reading fails with EIO once the terminal closes
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		io.Copy(io.MultiWriter(c.stdout, transcript), ptmx)
+	}()
+
+	waitErr := execCmd.Wait()
+
+	select {
+	case <-drained:
+	case <-time.After(ptyDrainTimeout):
+		ptmx.Close()
+		<-drained
+	}
+
+	return waitErr
+}
@@ -0,0 +1,71 @@
+//go:build !windows
+
+package maven
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPTYCommand_RelaysInputAndRecordsTranscript(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Arguments are passed without a shell, so spaces and quotes survive
+	fakeMvn := writeFakeMaven(t, tmpDir, `
+printf '\033[1m%s\033[0m\n' "$1"
+if [ -t 0 ]; then echo "stdin is a terminal"; fi
+printf "Name: "
+read name
+echo "Hello $name"
+exit 4
+`)
+
+	log := NewLogStore(0)
+	defer log.Close()
+
+	var screen bytes.Buffer
+	c := NewPTYCommand(Command{Executable: fakeMvn, Args: []string{"-Dmsg=it's a \"test\""}}, tmpDir, log)
+	c.SetStdin(strings.NewReader("Alice\n"))
+	c.SetStdout(&screen)
+
+	err := c.Run()
+	if err == nil {
+		t.Fatal("Expected an exit error")
+	}
+	if c.Result.ExitCode != 4 || c.Result.Error != nil {
+		t.Errorf("Expected exit code 4 without error, got %d (%v)", c.Result.ExitCode, c.Result.Error)
+	}
+
+	lines, err := log.All()
+	if err != nil {
+		t.Fatalf("Failed to read log: %v", err)
+	}
+	transcript := strings.Join(lines, "\n")
+	for _, want := range []string{`-Dmsg=it's a "test"`, "stdin is a terminal", "Hello Alice"} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("Expected transcript to contain %q, got:\n%s", want, transcript)
+		}
+	}
+	if strings.Contains(transcript, "\x1b") {
+		t.Errorf("Expected escape sequences to be stripped, got %q", transcript)
+	}
+
+	// The user's screen gets the raw output
+	if !strings.Contains(screen.String(), "\x1b[1m") {
+		t.Errorf("Expected raw output on screen, got %q", screen.String())
+	}
+}
+
+func TestPTYCommand_StartFailure(t *testing.T) {
+	c := NewPTYCommand(Command{Executable: "/nonexistent/mvn"}, t.TempDir(), nil)
+	c.SetStdin(strings.NewReader(""))
+	c.SetStdout(&bytes.Buffer{})
+
+	if err := c.Run(); err == nil {
+		t.Fatal("Expected an error for a missing executable")
+	}
+	if c.Result.Error == nil || c.Result.ExitCode == 0 {
+		t.Errorf("Expected a failed result, got %+v", c.Result)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package maven
+
+import (
+	"io"
+)
+
+// run starts the command attached to the console. Windows has no
+// pseudo-terminal support here, so output is recorded as it is relayed.
+func (c *PTYCommand) run(transcript io.Writer) error {
+	execCmd := c.Command.execCommand()
+	execCmd.Dir = c.Dir
+	execCmd.Stdin = c.stdin
+
+	// A single writer makes exec share one pipe, keeping the transcript ordered
+	output := io.MultiWriter(c.stdout, transcript)
+	execCmd.Stdout = output
+	execCmd.Stderr = output
+	return execCmd.Run()
+}
@@ -0,0 +1,275 @@
+package maven
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// ModuleState is the build state of a module in the reactor
+type ModuleState int
+
+const (
+	ModuleStatePending ModuleState = iota
+	ModuleStateBuilding
+	ModuleStateSucceeded
+	ModuleStateFailed
+	ModuleStateSkipped
+)
+
+// String returns a human readable module state
+func (s ModuleState) String() string {
+	switch s {
+	case ModuleStatePending:
+		return "pending"
+	case ModuleStateBuilding:
+		return "building"
+	case ModuleStateSucceeded:
+		return "success"
+	case ModuleStateFailed:
+		return "failed"
+	case ModuleStateSkipped:
+		return "skipped"
+	default:
+		return "unknown"
+	}
+}
+
+// ModuleProgress is the progress of one module in a running build
+type ModuleProgress struct {
+	Name       string // Project name as printed by Maven
+	ArtifactID string
+	State      ModuleState
+	StartTime  time.Time
+	Duration   time.Duration // Set once the module has finished
+	Goal       string        // Plugin goal currently executing, e.g. "compiler:compile"
+}
+
+// Elapsed returns how long the module has been building, or its final duration
+func (m *ModuleProgress) Elapsed(now time.Time) time.Duration {
+	if m.State == ModuleStateBuilding {
+		return now.Sub(m.StartTime)
+	}
+	return m.Duration
+}
+
+// ReactorProgress follows the log of a build and tracks the state of each
+// module in the reactor. A module is considered finished when the next one
+// starts; the Reactor Summary then supplies the exact states and timings.
+type ReactorProgress struct {
+	Modules    []*ModuleProgress // In reactor order once Maven has printed it
+	parser     *LogParser
+	current    *ModuleProgress
+	resumeFrom string // The -rf argument Maven suggested after a failure
+	reported   bool   // Maven printed reactor progress, which quiet mode suppresses
+	finished   bool
+}
+
+// NewReactorProgress creates a tracker with the given modules pending, in
+// declaration order until the build reports its reactor order
+func NewReactorProgress(modules []Module) *ReactorProgress {
+	r := &ReactorProgress{parser: NewLogParser()}
+	for _, module := range modules {
+		artifactID := module.ArtifactID
+		if artifactID == "" {
+			artifactID = path.Base(module.Name)
+		}
+		r.Modules = append(r.Modules, &ModuleProgress{
+			Name:       module.Name,
+			ArtifactID: artifactID,
+		})
+	}
+	return r
+}
+
+// Feed parses a line of build output and updates the module states
+func (r *ReactorProgress) Feed(line string, now time.Time) {
+	for _, event := range r.parser.Parse(line) {
+		r.Apply(event, now)
+	}
+}
+
+// Apply updates the module states from a parsed log event
+func (r *ReactorProgress) Apply(event LogEvent, now time.Time) {
+	switch e := event.(type) {
+	case ReactorOrderEvent:
+		r.reported = true
+		r.reorder(e.Modules)
+
+	case ModuleStartEvent:
+		r.reported = true
+		r.finishCurrent(ModuleStateSucceeded, now)
+		module := r.find(e.Name, e.ArtifactID)
+		if module == nil {
+			module = &ModuleProgress{}
+			r.Modules = append(r.Modules, module)
+		}
+		module.Name = e.Name
+		if e.ArtifactID != "" {
+			module.ArtifactID = e.ArtifactID
+		}
+		module.State = ModuleStateBuilding
+		module.StartTime = now
+		module.Duration = 0
+		module.Goal = ""
+		r.current = module
+
+	case PluginExecutionEvent:
+		if r.current != nil {
+			r.current.Goal = pluginPrefix(e.Plugin) + ":" + e.Goal
+		}
+
+	case ModuleFinishEvent:
+		module := r.find(e.Name, "")
+		if module == nil {
+			return
+		}
+		switch e.Status {
+		case ModuleSuccess:
+			module.State = ModuleStateSucceeded
+		case ModuleFailure:
+			module.State = ModuleStateFailed
+		case ModuleSkipped:
+			module.State = ModuleStateSkipped
+		}
+		if e.Duration > 0 {
+			module.Duration = e.Duration
+		}
+		module.Goal = ""
+		if module == r.current {
+			r.current = nil
+		}
+
+	case ResumeEvent:
+		r.resumeFrom = e.Module
+
+	case BuildResultEvent:
+		r.Finish(e.Success, now)
+	}
+}
+
+// Finish settles the remaining modules once the build has ended. The module
+// still building is marked failed unless the build succeeded, and modules
+// that never started are marked skipped after a failure. A build result
+// still buffered by the parser takes precedence over success.
+func (r *ReactorProgress) Finish(success bool, now time.Time) {
+	if !r.finished {
+		for _, event := range r.parser.Flush() {
+			r.Apply(event, now)
+		}
+	}
+	if r.finished {
+		return
+	}
+	r.finished = true
+	if !r.reported {
+		return
+	}
+
+	if success {
+		r.finishCurrent(ModuleStateSucceeded, now)
+		return
+	}
+	r.finishCurrent(ModuleStateFailed, now)
+	for _, module := range r.Modules {
+		if module.State == ModuleStatePending {
+			module.State = ModuleStateSkipped
+		}
+	}
+}
+
+// Reported reports whether the build has printed any reactor progress.
+// Builds run with -q print none, leaving every module pending.
+func (r *ReactorProgress) Reported() bool {
+	return r.reported
+}
+
+// ResumeFrom returns the module a failed build can be resumed from with -rf:
+// the one Maven suggested or, when its message was missed, the first failed
+// module by artifactId. Like Maven, it offers none when the first module of
+// the reactor failed, as resuming would rebuild everything anyway.
+func (r *ReactorProgress) ResumeFrom() string {
+	if r.resumeFrom != "" {
+		return r.resumeFrom
+	}
+	for i, module := range r.Modules {
+		if module.State != ModuleStateFailed {
+			continue
+		}
+		if i == 0 || module.ArtifactID == "" {
+			return ""
+		}
+		return ":" + module.ArtifactID
+	}
+	return ""
+}
+
+// Current returns the module being built, or nil if none is
+func (r *ReactorProgress) Current() *ModuleProgress {
+	return r.current
+}
+
+// Completed returns the number of modules that have finished building
+func (r *ReactorProgress) Completed() int {
+	count := 0
+	for _, module := range r.Modules {
+		if module.State != ModuleStatePending && module.State != ModuleStateBuilding {
+			count++
+		}
+	}
+	return count
+}
+
+// finishCurrent ends the module being built with the given state
+func (r *ReactorProgress) finishCurrent(state ModuleState, now time.Time) {
+	if r.current == nil {
+		return
+	}
+	if r.current.State == ModuleStateBuilding {
+		r.current.State = state
+		r.current.Duration = now.Sub(r.current.StartTime)
+		r.current.Goal = ""
+	}
+	r.current = nil
+}
+
+// reorder replaces the module list with the reactor build order. Modules that
+// are not part of the reactor, e.g. when building a subset with -pl, are dropped.
+func (r *ReactorProgress) reorder(names []string) {
+	ordered := make([]*ModuleProgress, 0, len(names))
+	for _, name := range names {
+		module := r.find(name, "")
+		if module == nil {
+			module = &ModuleProgress{}
+		}
+		module.Name = name
+		ordered = append(ordered, module)
+	}
+	r.Modules = ordered
+}
+
+// find returns the module matching a project name or artifactId
+func (r *ReactorProgress) find(name, artifactID string) *ModuleProgress {
+	for _, module := range r.Modules {
+		if module.Name == name || (module.ArtifactID != "" && module.ArtifactID == name) {
+			return module
+		}
+		if artifactID != "" && module.ArtifactID == artifactID {
+			return module
+		}
+	}
+	return nil
+}
+
+// pluginPrefix shortens a plugin artifactId to its goal prefix, as newer
+// Maven versions print it (maven-compiler-plugin and compiler-maven-plugin
+// both become compiler)
+func pluginPrefix(plugin string) string {
+	if prefix, ok := strings.CutSuffix(plugin, "-maven-plugin"); ok {
+		return prefix
+	}
+	if strings.HasPrefix(plugin, "maven-") && strings.HasSuffix(plugin, "-plugin") {
+		return strings.TrimSuffix(strings.TrimPrefix(plugin, "maven-"), "-plugin")
+	}
+	return plugin
+}
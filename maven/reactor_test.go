@@ -0,0 +1,190 @@
+package maven
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// feedTestdataLog feeds a recorded Maven log to a reactor tracker, one second per line
+func feedTestdataLog(t *testing.T, r *ReactorProgress, name string, start time.Time) time.Time {
+	t.Helper()
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", name, err)
+	}
+	defer file.Close()
+
+	now := start
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		now = now.Add(time.Second)
+		r.Feed(scanner.Text(), now)
+	}
+	return now
+}
+
+func TestReactorProgress_MultiModuleSuccess(t *testing.T) {
+	// Seeded from the project's declared modules; the reactor order replaces them
+	r := NewReactorProgress([]Module{{Name: "shop-web"}, {Name: "shop-core"}})
+	feedTestdataLog(t, r, "reactor-progress.log", time.Now())
+
+	if len(r.Modules) != 3 {
+		t.Fatalf("Expected 3 modules, got %d", len(r.Modules))
+	}
+	wantNames := []string{"shop-parent", "shop-core", "Shop Web Frontend"}
+	for i, module := range r.Modules {
+		if module.Name != wantNames[i] {
+			t.Errorf("Module %d = %q, want %q", i, module.Name, wantNames[i])
+		}
+		if module.State != ModuleStateSucceeded {
+			t.Errorf("Module %s state = %v, want success", module.Name, module.State)
+		}
+	}
+
+	// Durations come from the Reactor Summary
+	if r.Modules[2].Duration != 62*time.Second {
+		t.Errorf("Expected shop-web duration 1m2s, got %v", r.Modules[2].Duration)
+	}
+	if r.Current() != nil || r.Completed() != 3 {
+		t.Errorf("Expected no current module and 3 completed, got %v and %d", r.Current(), r.Completed())
+	}
+}
+
+func TestReactorProgress_TracksRunningModule(t *testing.T) {
+	r := NewReactorProgress(nil)
+	start := time.Now()
+	lines := []string{
+		"[INFO] Reactor Build Order:",
+		"[INFO] ",
+		"[INFO] app-parent                                                        [pom]",
+		"[INFO] app-core                                                          [jar]",
+		"[INFO] app-web                                                           [jar]",
+		"[INFO] ",
+		"[INFO] Building app-parent 1.0 [1/3]",
+		"[INFO] Building app-core 1.0 [2/3]",
+		"[INFO] --- compiler:3.11.0:testCompile (default-testCompile) @ app-core ---",
+	}
+	for i, line := range lines {
+		r.Feed(line, start.Add(time.Duration(i)*time.Second))
+	}
+
+	if r.Modules[0].State != ModuleStateSucceeded || r.Modules[0].Duration != time.Second {
+		t.Errorf("Expected app-parent to be inferred finished after 1s, got %v after %v", r.Modules[0].State, r.Modules[0].Duration)
+	}
+
+	current := r.Current()
+	if current == nil || current.Name != "app-core" {
+		t.Fatalf("Expected app-core to be building, got %+v", current)
+	}
+	if current.Goal != "compiler:testCompile" {
+		t.Errorf("Expected current goal compiler:testCompile, got %q", current.Goal)
+	}
+	if elapsed := current.Elapsed(start.Add(10 * time.Second)); elapsed != 3*time.Second {
+		t.Errorf("Expected app-core elapsed 3s, got %v", elapsed)
+	}
+	if r.Modules[2].State != ModuleStatePending {
+		t.Errorf("Expected app-web pending, got %v", r.Modules[2].State)
+	}
+
+	// A cancelled build fails the running module and skips the rest
+	r.Finish(false, start.Add(20*time.Second))
+	if current.State != ModuleStateFailed || current.Duration != 13*time.Second {
+		t.Errorf("Expected app-core failed after 13s, got %v after %v", current.State, current.Duration)
+	}
+	if r.Modules[2].State != ModuleStateSkipped {
+		t.Errorf("Expected app-web skipped, got %v", r.Modules[2].State)
+	}
+}
+
+func TestReactorProgress_CompileFailure(t *testing.T) {
+	r := NewReactorProgress(nil)
+	feedTestdataLog(t, r, "compile-failure.log", time.Now())
+
+	want := map[string]ModuleState{
+		"billing-parent":  ModuleStateSucceeded,
+		"billing-api":     ModuleStateSucceeded,
+		"billing-service": ModuleStateFailed,
+		"billing-app":     ModuleStateSkipped,
+	}
+	if len(r.Modules) != len(want) {
+		t.Fatalf("Expected %d modules, got %d", len(want), len(r.Modules))
+	}
+	for _, module := range r.Modules {
+		if module.State != want[module.Name] {
+			t.Errorf("Module %s state = %v, want %v", module.Name, module.State, want[module.Name])
+		}
+	}
+}
+
+func TestReactorProgress_ResumeFrom(t *testing.T) {
+	r := NewReactorProgress(nil)
+	feedTestdataLog(t, r, "compile-failure.log", time.Now())
+	if got := r.ResumeFrom(); got != ":billing-service" {
+		t.Errorf("Expected Maven's suggestion :billing-service, got %q", got)
+	}
+
+	// Without the suggestion the first failed module is resumed by artifactId
+	r = NewReactorProgress(nil)
+	for _, line := range []string{
+		"[INFO] ----------------------< com.acme:billing-api >-----------------------",
+		"[INFO] Building Billing API 2.0.0 [1/3]",
+		"[INFO] ----------------------< com.acme:billing-service >-------------------",
+		"[INFO] Building Billing Service 2.0.0 [2/3]",
+		"[INFO] Reactor Summary for billing-parent 2.0.0:",
+		"[INFO] Billing API ........................................ SUCCESS [  0.861 s]",
+		"[INFO] Billing Service .................................... FAILURE [  1.530 s]",
+		"[INFO] Billing App ........................................ SKIPPED",
+	} {
+		r.Feed(line, time.Now())
+	}
+	r.Finish(false, time.Now())
+	if got := r.ResumeFrom(); got != ":billing-service" {
+		t.Errorf("Expected to resume from the failed module, got %q", got)
+	}
+
+	// Resuming from the first module would rebuild everything
+	r = NewReactorProgress(nil)
+	r.Feed("[INFO] ----------------------< com.acme:billing-api >-----------------------", time.Now())
+	r.Feed("[INFO] Building Billing API 2.0.0 [1/3]", time.Now())
+	r.Finish(false, time.Now())
+	if got := r.ResumeFrom(); got != "" {
+		t.Errorf("Expected no resume point when the first module failed, got %q", got)
+	}
+}
+
+func TestReactorProgress_SingleModule(t *testing.T) {
+	r := NewReactorProgress(nil)
+	now := feedTestdataLog(t, r, "single-module-legacy.log", time.Now())
+
+	if len(r.Modules) != 1 || r.Modules[0].Name != "demo" {
+		t.Fatalf("Expected the demo module, got %+v", r.Modules)
+	}
+	if r.Modules[0].Goal != "kotlin:compile" {
+		t.Errorf("Expected current goal kotlin:compile, got %q", r.Modules[0].Goal)
+	}
+
+	// Without a Reactor Summary the module is settled when the process exits
+	r.Finish(true, now)
+	if r.Modules[0].State != ModuleStateSucceeded || r.Current() != nil {
+		t.Errorf("Expected the module to succeed, got %v", r.Modules[0].State)
+	}
+}
+
+func TestReactorProgress_QuietBuild(t *testing.T) {
+	r := NewReactorProgress([]Module{{Name: "core"}, {Name: "web"}})
+	r.Feed("[ERROR] Failed to execute goal on project core", time.Now())
+	r.Finish(false, time.Now())
+
+	// Nothing is known about the modules, so none are marked skipped
+	if r.Reported() {
+		t.Error("Expected a quiet build to report no progress")
+	}
+	for _, module := range r.Modules {
+		if module.State != ModuleStatePending {
+			t.Errorf("Module %s state = %v, want pending", module.Name, module.State)
+		}
+	}
+}
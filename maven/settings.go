@@ -0,0 +1,139 @@
+package maven
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Settings is the part of a Maven settings.xml that affects profiles
+type Settings struct {
+	XMLName        xml.Name     `xml:"settings"`
+	Profiles       []POMProfile `xml:"profiles>profile"`
+	ActiveProfiles []string     `xml:"activeProfiles>activeProfile"`
+
+	Path string `xml:"-"` // File the settings were read from
+}
+
+// ReadSettings parses the settings.xml at path
+func ReadSettings(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings.xml: %w", err)
+	}
+
+	var settings Settings
+	if err := xml.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	settings.Path = path
+	return &settings, nil
+}
+
+// UserSettingsPath returns the user settings.xml Maven reads for the project
+// in dir: the one chosen with -s in options, else in .mvn/maven.config, else
+// ~/.m2/settings.xml. It returns "" if none is known.
+func UserSettingsPath(dir string, options BuildOptions) string {
+	if path := chosenSettings(dir, options.Settings, "-s", "--settings"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".m2", "settings.xml")
+}
+
+// GlobalSettingsPath returns the global settings.xml Maven reads for the
+// project in dir: the one chosen with -gs in options, else in
+// .mvn/maven.config, else conf/settings.xml of the Maven installation found
+// through $MAVEN_HOME or the mvn on the PATH. It returns "" if none is known.
+func GlobalSettingsPath(dir string, options BuildOptions) string {
+	if path := chosenSettings(dir, options.GlobalSettings, "-gs", "--global-settings"); path != "" {
+		return path
+	}
+	if home := os.Getenv("MAVEN_HOME"); home != "" {
+		return filepath.Join(home, "conf", "settings.xml")
+	}
+	mvn, err := exec.LookPath("mvn")
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(mvn); err == nil {
+		mvn = resolved
+	}
+	return filepath.Join(filepath.Dir(filepath.Dir(mvn)), "conf", "settings.xml")
+}
+
+// chosenSettings returns the settings file picked in the options, else the
+// one given by short or long in .mvn/maven.config, relative to dir, or ""
+func chosenSettings(dir, option, short, long string) string {
+	path := option
+	if path == "" {
+		args := MavenConfigArgs(dir)
+		for i, arg := range args {
+			if (arg == short || arg == long) && i+1 < len(args) {
+				path = args[i+1]
+				break
+			}
+			if value, ok := strings.CutPrefix(arg, long+"="); ok {
+				path = value
+				break
+			}
+		}
+	}
+	if path != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path
+}
+
+// LoadSettings reads the user and global settings.xml Maven would use with
+// options and lists the profiles again with theirs. Profiles turned on or
+// off with -P keep that choice; call EvaluateProfiles afterwards.
+func (p *Project) LoadSettings(options BuildOptions) {
+	p.Settings = readSettingsFile(UserSettingsPath(p.RootPath, options))
+	p.GlobalSettings = readSettingsFile(GlobalSettingsPath(p.RootPath, options))
+
+	previous := p.Profiles
+	p.Profiles = nil
+	p.loadProfiles()
+	for _, old := range previous {
+		if old.Enabled {
+			// Kept even if no file declares it any more, as EnableProfile does
+			p.EnableProfile(old.ID)
+		} else if i := slices.IndexFunc(p.Profiles, func(prof Profile) bool { return prof.ID == old.ID }); i >= 0 {
+			p.Profiles[i].Disabled = old.Disabled
+		}
+	}
+}
+
+// readSettingsFile reads the settings.xml at path, or returns nil if there
+// is none or it can't be parsed
+func readSettingsFile(path string) *Settings {
+	if path == "" {
+		return nil
+	}
+	settings, err := ReadSettings(path)
+	if err != nil {
+		return nil
+	}
+	return settings
+}
+
+// activates reports whether settings.xml lists the profile in activeProfiles
+func (s *Settings) activates(id string) bool {
+	if s == nil {
+		return false
+	}
+	for _, active := range s.ActiveProfiles {
+		if strings.TrimSpace(active) == id {
+			return true
+		}
+	}
+	return false
+}
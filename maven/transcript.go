@@ -0,0 +1,259 @@
+package maven
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// transcriptState is the escape sequence parser state of a terminalTranscript
+type transcriptState int
+
+const (
+	transcriptText         transcriptState = iota
+	transcriptEscape                       // After ESC
+	transcriptCSI                          // Inside ESC [ ... final byte
+	transcriptString                       // Inside OSC, DCS and similar, until BEL or ST
+	transcriptStringEscape                 // After ESC inside a string, expecting \
+	transcriptCharset                      // After ESC and an intermediate byte, expecting a final byte
+)
+
+// maxStyleLength caps the SGR sequences remembered for a cell; anything
+// longer is a run of attributes without a reset, of which the last is kept
+const maxStyleLength = 64
+
+// cell is a character on the current line and the SGR sequences styling it
+type cell struct {
+	r     rune
+	style string
+}
+
+// terminalTranscript turns raw terminal output into log lines, the way the
+// text would read on screen. Carriage returns and backspaces move the cursor
+// so that overwritten text is replaced, and erase-in-line truncates the line.
+// Plain lines without escape sequences go to log; if styled is set, the same
+// lines keeping their colors and text attributes go there too.
+type terminalTranscript struct {
+	log     *LogStore
+	styled  *LogStore
+	state   transcriptState
+	params  []byte // Parameters of the current CSI sequence
+	style   string // SGR sequences in effect
+	line    []cell
+	col     int
+	partial []byte // Incomplete UTF-8 sequence from the previous write
+}
+
+// newTerminalTranscript creates a transcript that appends lines to log
+func newTerminalTranscript(log *LogStore) *terminalTranscript {
+	return &terminalTranscript{log: log}
+}
+
+// Write processes a chunk of terminal output. It never fails.
+func (t *terminalTranscript) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(t.partial) > 0 {
+		p = append(t.partial, p...)
+		t.partial = nil
+	}
+
+	for len(p) > 0 {
+		b := p[0]
+		if t.state != transcriptText || b < utf8.RuneSelf {
+			t.writeByte(b)
+			p = p[1:]
+			continue
+		}
+
+		if !utf8.FullRune(p) {
+			t.partial = append([]byte(nil), p...)
+			break
+		}
+		r, size := utf8.DecodeRune(p)
+		t.put(r)
+		p = p[size:]
+	}
+
+	return n, nil
+}
+
+func (t *terminalTranscript) writeByte(b byte) {
+	switch t.state {
+	case transcriptEscape:
+		switch {
+		case b == '[':
+			t.state = transcriptCSI
+			t.params = t.params[:0]
+		case b == ']' || b == 'P' || b == 'X' || b == '^' || b == '_':
+			t.state = transcriptString
+		case b >= 0x20 && b <= 0x2f:
+			t.state = transcriptCharset
+		default:
+			t.state = transcriptText
+		}
+		return
+
+	case transcriptCSI:
+		if b >= 0x40 && b <= 0x7e {
+			t.state = transcriptText
+			t.csi(b)
+		} else {
+			t.params = append(t.params, b)
+		}
+		return
+
+	case transcriptString:
+		switch b {
+		case 0x07:
+			t.state = transcriptText
+		case 0x1b:
+			t.state = transcriptStringEscape
+		}
+		return
+
+	case transcriptStringEscape:
+		if b == '\\' {
+			t.state = transcriptText
+		} else {
+			t.state = transcriptString
+		}
+		return
+
+	case transcriptCharset:
+		if b < 0x20 || b > 0x2f {
+			t.state = transcriptText
+		}
+		return
+	}
+
+	switch b {
+	case 0x1b:
+		t.state = transcriptEscape
+	case '\n':
+		t.emit()
+	case '\r':
+		t.col = 0
+	case '\b':
+		t.col = max(t.col-1, 0)
+	case '\t':
+		t.put('\t')
+	default:
+		// Other control characters don't print
+		if b >= 0x20 && b != 0x7f {
+			t.put(rune(b))
+		}
+	}
+}
+
+// csi applies the CSI sequences that change the text of the current line
+func (t *terminalTranscript) csi(final byte) {
+	switch final {
+	case 'm':
+		// Select graphic rendition: remember it for the cells written next
+		params := string(t.params)
+		if params == "" || params == "0" {
+			t.style = ""
+		} else if seq := "\x1b[" + params + "m"; len(t.style)+len(seq) > maxStyleLength {
+			t.style = seq
+		} else {
+			t.style += seq
+		}
+	case 'K':
+		// Erase in line: 0 (default) to the end, 1 to the start, 2 the whole line
+		switch string(t.params) {
+		case "", "0":
+			t.line = t.line[:min(t.col, len(t.line))]
+		case "1":
+			for i := 0; i < min(t.col+1, len(t.line)); i++ {
+				t.line[i] = cell{r: ' '}
+			}
+		case "2":
+			t.line = t.line[:0]
+		}
+	case 'G':
+		// Cursor horizontal absolute, 1-based
+		t.col = max(atoiDefault(string(t.params), 1)-1, 0)
+	case 'D':
+		t.col = max(t.col-atoiDefault(string(t.params), 1), 0)
+	case 'C':
+		t.col += atoiDefault(string(t.params), 1)
+	}
+}
+
+// put writes a rune at the cursor, overwriting what was there
+func (t *terminalTranscript) put(r rune) {
+	for len(t.line) < t.col {
+		t.line = append(t.line, cell{r: ' '})
+	}
+	c := cell{r: r, style: t.style}
+	if t.col < len(t.line) {
+		t.line[t.col] = c
+	} else {
+		t.line = append(t.line, c)
+	}
+	t.col++
+}
+
+// emit appends the current line to the log and starts a new one
+func (t *terminalTranscript) emit() {
+	t.log.Append(t.plainLine())
+	if t.styled != nil {
+		t.styled.Append(t.styledLine())
+	}
+	t.line = t.line[:0]
+	t.col = 0
+}
+
+// plainLine returns the text of the current line
+func (t *terminalTranscript) plainLine() string {
+	var sb strings.Builder
+	for _, c := range t.line {
+		sb.WriteRune(c.r)
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// styledLine returns the current line with the SGR sequences of its cells
+func (t *terminalTranscript) styledLine() string {
+	var sb strings.Builder
+	style := ""
+	for _, c := range t.line {
+		if c.style != style {
+			sb.WriteString("\x1b[0m")
+			sb.WriteString(c.style)
+			style = c.style
+		}
+		sb.WriteRune(c.r)
+	}
+	if style != "" {
+		sb.WriteString("\x1b[0m")
+	}
+	return sb.String()
+}
+
+// Flush appends the final line if the output didn't end with a newline
+func (t *terminalTranscript) Flush() {
+	if len(t.partial) > 0 {
+		for _, r := range string(t.partial) {
+			t.put(r)
+		}
+		t.partial = nil
+	}
+	if len(t.line) > 0 {
+		t.emit()
+	}
+}
+
+// atoiDefault parses a CSI numeric parameter, returning def if it is missing
+func atoiDefault(s string, def int) int {
+	n := 0
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return def
+		}
+		n = n*10 + int(c-'0')
+	}
+	if s == "" || n == 0 {
+		return def
+	}
+	return n
+}
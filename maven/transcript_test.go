@@ -0,0 +1,72 @@
+package maven
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTerminalTranscript(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []string
+	}{
+		{"plain lines", []string{"hello\r\nworld\r\n"}, []string{"hello", "world"}},
+		{"colors are stripped", []string{"\x1b[1;32mBUILD SUCCESS\x1b[0m\r\n"}, []string{"BUILD SUCCESS"}},
+		{"text resembling escape codes is kept", []string{"Tests took 10m and 0m0m\r\n"}, []string{"Tests took 10m and 0m0m"}},
+		{"carriage return overwrites", []string{"Progress 10%\rProgress 100%\r\n"}, []string{"Progress 100%"}},
+		{"shorter overwrite keeps the tail", []string{"abcdef\rXY\r\n"}, []string{"XYcdef"}},
+		{"erase in line", []string{"Downloading 1/3\r\x1b[KDone\r\n"}, []string{"Done"}},
+		{"echoed erase", []string{"Alicf\b \be\r\n"}, []string{"Alice"}},
+		{"osc title is dropped", []string{"\x1b]0;mvn exec:java\x07Started\r\n"}, []string{"Started"}},
+		{"sequences split across writes", []string{"\x1b[3", "1mred\x1b", "[0m\r\n"}, []string{"red"}},
+		{"utf-8 split across writes", []string{"caf\xc3", "\xa9 \xe2\x9c", "\x93\r\n"}, []string{"café ✓"}},
+		{"unterminated final line", []string{"Enter your name: "}, []string{"Enter your name:"}},
+		{"blank lines are kept", []string{"a\r\n\r\nb\r\n"}, []string{"a", "", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := NewLogStore(0)
+			defer log.Close()
+
+			transcript := newTerminalTranscript(log)
+			for _, chunk := range tt.chunks {
+				transcript.Write([]byte(chunk))
+			}
+			transcript.Flush()
+
+			got, err := log.All()
+			if err != nil {
+				t.Fatalf("Failed to read log: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Transcript = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalTranscript_StyledLines(t *testing.T) {
+	log := NewLogStore(0)
+	styled := NewLogStore(0)
+	defer log.Close()
+	defer styled.Close()
+
+	transcript := newTerminalTranscript(log)
+	transcript.styled = styled
+	transcript.Write([]byte("\x1b[1m\x1b[32mOK\x1b[0m done\r\nWorking...\rDone      \r\n"))
+	transcript.Flush()
+
+	got, _ := styled.All()
+	want := []string{"\x1b[0m\x1b[1m\x1b[32mOK\x1b[0m done", "Done"}
+	if len(got) != 2 || got[0] != want[0] || strings.TrimRight(got[1], " ") != want[1] {
+		t.Errorf("Styled lines = %q, want %q", got, want)
+	}
+
+	plain, _ := log.All()
+	if !reflect.DeepEqual(plain, []string{"OK done", "Done"}) {
+		t.Errorf("Plain lines = %q", plain)
+	}
+}
@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AR0106/mvn-tui/maven"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Rows of the build options view
+const (
+	optionThreads = iota
+	optionAlsoMake
+	optionAlsoMakeDependents
+	optionResumeFrom
+	optionFailStrategy
+	optionSkipTestCompile
+	optionNoTransferProgress
+	optionNonRecursive
+	optionSettings
+	optionGlobalSettings
+	optionRows
+)
+
+// threadChoices are the -T values Space cycles through before a custom count
+var threadChoices = []string{"", "1", "1C", "2C"}
+
+// createOptionInput creates the text input for options that take a value
+func createOptionInput() textinput.Model {
+	optionInput := textinput.New()
+	optionInput.Width = 50
+
+	return optionInput
+}
+
+// openBuildOptions shows the build options view
+func (m *Model) openBuildOptions() {
+	m.optionEditing = false
+	m.err = nil
+	m.notice = ""
+	m.currentView = ViewBuildOptions
+}
+
+// handleBuildOptionsKey handles keys in the build options view
+func (m *Model) handleBuildOptionsKey(msg tea.KeyMsg) tea.Cmd {
+	if m.optionEditing {
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.optionEditing = false
+			m.optionInput.Blur()
+			m.err = nil
+		case "enter":
+			if err := m.setOptionText(strings.TrimSpace(m.optionInput.Value())); err != nil {
+				m.err = err
+				return nil
+			}
+			m.optionEditing = false
+			m.optionInput.Blur()
+			m.err = nil
+		default:
+			var cmd tea.Cmd
+			m.optionInput, cmd = m.optionInput.Update(msg)
+			return cmd
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "esc", "o":
+		m.err = nil
+		m.currentView = ViewMain
+	case "up", "k":
+		m.optionCursor = max(m.optionCursor-1, 0)
+	case "down", "j":
+		m.optionCursor = min(m.optionCursor+1, optionRows-1)
+	case " ", "enter":
+		m.changeOption()
+	case "backspace", "delete":
+		// Clear an option that takes a value
+		m.setOptionText("")
+	}
+	return nil
+}
+
+// changeOption toggles or cycles the highlighted option, or starts editing
+// its value
+func (m *Model) changeOption() {
+	o := &m.options
+	switch m.optionCursor {
+	case optionThreads:
+		i := slices.Index(threadChoices, o.Threads)
+		if i >= 0 && i+1 < len(threadChoices) {
+			o.Threads = threadChoices[i+1]
+		} else if i == len(threadChoices)-1 {
+			// After the presets comes a count of the user's choosing
+			m.editOption("")
+		} else {
+			o.Threads = ""
+		}
+	case optionAlsoMake:
+		o.AlsoMake = !o.AlsoMake
+	case optionAlsoMakeDependents:
+		o.AlsoMakeDependents = !o.AlsoMakeDependents
+	case optionResumeFrom:
+		m.editOption(o.ResumeFrom)
+	case optionFailStrategy:
+		i := slices.Index(maven.FailStrategies, o.FailStrategy)
+		o.FailStrategy = maven.FailStrategies[(i+1)%len(maven.FailStrategies)]
+	case optionSkipTestCompile:
+		o.SkipTestCompile = !o.SkipTestCompile
+	case optionNoTransferProgress:
+		o.NoTransferProgress = !o.NoTransferProgress
+	case optionNonRecursive:
+		o.NonRecursive = !o.NonRecursive
+	case optionSettings:
+		m.editOption(o.Settings)
+	case optionGlobalSettings:
+		m.editOption(o.GlobalSettings)
+	}
+}
+
+// editOption starts editing the value of the highlighted option
+func (m *Model) editOption(value string) {
+	placeholders := map[int]string{
+		optionThreads:        "Threads, e.g. 4 or 1.5C",
+		optionResumeFrom:     "Module to resume from, e.g. :shop-core",
+		optionSettings:       "Path of the user settings.xml",
+		optionGlobalSettings: "Path of the global settings.xml",
+	}
+	m.optionInput.Placeholder = placeholders[m.optionCursor]
+	m.optionInput.SetValue(value)
+	m.optionInput.CursorEnd()
+	m.optionInput.Focus()
+	m.optionEditing = true
+	m.err = nil
+}
+
+// setOptionText sets the value of the highlighted option; an empty value
+// turns it off
+func (m *Model) setOptionText(value string) error {
+	o := &m.options
+	switch m.optionCursor {
+	case optionThreads:
+		if value != "" && !maven.ValidThreads(value) {
+			return fmt.Errorf("%q is not a thread count such as 4 or 1C", value)
+		}
+		o.Threads = value
+	case optionResumeFrom:
+		if strings.ContainsAny(value, " \t,") {
+			return fmt.Errorf("resume from a single module, e.g. :shop-core")
+		}
+		o.ResumeFrom = value
+	case optionSettings:
+		o.Settings = value
+		m.reloadSettings()
+	case optionGlobalSettings:
+		o.GlobalSettings = value
+		m.reloadSettings()
+	}
+	return nil
+}
+
+// reloadSettings lists the profiles of the settings files now chosen and
+// works out again which Maven activates
+func (m *Model) reloadSettings() {
+	m.project.LoadSettings(m.options)
+	m.project.EvaluateProfiles(maven.NewActivationContext(m.project, m.options))
+	m.profileCursor = min(m.profileCursor, max(len(m.project.Profiles)-1, 0))
+}
+
+// optionLabel returns the flag, description and current value of a row
+func (m Model) optionLabel(row int) (flag, description, value string) {
+	o := m.options
+	check := func(on bool) string {
+		if on {
+			return "[✓]"
+		}
+		return "[ ]"
+	}
+	text := func(s string) string {
+		if s == "" {
+			return "(off)"
+		}
+		return s
+	}
+
+	switch row {
+	case optionThreads:
+		return "-T", "Threads", text(o.Threads)
+	case optionAlsoMake:
+		return "-am", "Also make dependencies", check(o.AlsoMake)
+	case optionAlsoMakeDependents:
+		return "-amd", "Also make dependents", check(o.AlsoMakeDependents)
+	case optionResumeFrom:
+		return "-rf", "Resume from", text(o.ResumeFrom)
+	case optionFailStrategy:
+		if flag := o.FailStrategy.Flag(); flag != "" {
+			return "-ff/-fae/-fn", "Fail strategy", fmt.Sprintf("%s (%s)", o.FailStrategy, flag)
+		}
+		return "-ff/-fae/-fn", "Fail strategy", "(Maven's default)"
+	case optionSkipTestCompile:
+		return "-Dmaven.test.skip", "Skip compiling tests", check(o.SkipTestCompile)
+	case optionNoTransferProgress:
+		return "-ntp", "No transfer progress", check(o.NoTransferProgress)
+	case optionNonRecursive:
+		return "-N", "Non-recursive", check(o.NonRecursive)
+	case optionSettings:
+		return "-s", "User settings", text(o.Settings)
+	case optionGlobalSettings:
+		return "-gs", "Global settings", text(o.GlobalSettings)
+	}
+	return "", "", ""
+}
+
+// optionNote explains why a set option doesn't reach the command
+func (m Model) optionNote(row int) string {
+	o := m.options
+	switch row {
+	case optionAlsoMake, optionAlsoMakeDependents:
+		if o.NonRecursive {
+			return "ignored with -N"
+		}
+		if selected := len(m.project.SelectedProjects()); selected == 0 || selected == len(m.project.Modules) {
+			return "applies when some modules are deselected"
+		}
+	case optionResumeFrom:
+		if o.NonRecursive && o.ResumeFrom != "" {
+			return "ignored with -N"
+		}
+	}
+	return ""
+}
+
+// advancedOptions returns the options of the build options view that are
+// set, as Maven flags, for the options pane
+func (m Model) advancedOptions() []string {
+	o := m.options
+	var set []string
+	add := func(on bool, flag string) {
+		if on {
+			set = append(set, flag)
+		}
+	}
+	add(o.Threads != "", "-T "+o.Threads)
+	add(o.AlsoMake, "-am")
+	add(o.AlsoMakeDependents, "-amd")
+	add(o.ResumeFrom != "", "-rf "+o.ResumeFrom)
+	add(o.FailStrategy.Flag() != "", o.FailStrategy.Flag())
+	add(o.SkipTestCompile, "-Dmaven.test.skip=true")
+	add(o.NoTransferProgress, "-ntp")
+	add(o.NonRecursive, "-N")
+	add(o.Settings != "", "-s "+o.Settings)
+	add(o.GlobalSettings != "", "-gs "+o.GlobalSettings)
+	return set
+}
+
+// renderBuildOptionsView renders the build options view
+func (m Model) renderBuildOptionsView() string {
+	header := m.renderHeader()
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render("Build Options"))
+	sb.WriteString("\n\n")
+
+	for row := range optionRows {
+		cursor := "  "
+		if row == m.optionCursor {
+			cursor = "› "
+		}
+		flag, description, value := m.optionLabel(row)
+		if m.optionEditing && row == m.optionCursor {
+			value = m.optionInput.View()
+		}
+		sb.WriteString(fmt.Sprintf("%s%-24s %-18s %s", cursor, description, flag, value))
+		if note := m.optionNote(row); note != "" {
+			sb.WriteString(dim.Render(" (" + note + ")"))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n" + maven.BuildCommand(m.project, nil, m.options).String())
+
+	border := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("205")).
+		Padding(0, 1)
+
+	footer := "↑/↓: Select | Space/Enter: Change | Backspace: Clear | Esc: Back"
+	if m.optionEditing {
+		footer = "Enter: Save (empty turns it off) | Esc: Cancel"
+	}
+	if m.err != nil {
+		footer = fmt.Sprintf("✗ %v | %s", m.err, footer)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, border.Render(sb.String()), footer)
+}
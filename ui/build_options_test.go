@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AR0106/mvn-tui/maven"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestBuildOptionsView_SetsMoreOptions(t *testing.T) {
+	project := &maven.Project{RootPath: t.TempDir(), Executable: "mvn", Modules: []maven.Module{
+		{Name: "core", ArtifactID: "core", Selected: true},
+		{Name: "web", ArtifactID: "web"},
+	}}
+	m := NewModel(project)
+	defer m.Close()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 50})
+	m = updated.(Model)
+
+	// Space cycles through the thread presets, then asks for a count
+	m = press(m, "o", "space", "space", "space")
+	if m.options.Threads != "2C" {
+		t.Fatalf("Expected the 2C preset, got %q", m.options.Threads)
+	}
+	m = press(m, "space", "lots", "enter")
+	if m.err == nil || !m.optionEditing {
+		t.Fatal("Expected a bad thread count to be refused")
+	}
+	m = press(m, "ctrl+u", "3", "enter")
+	if m.err != nil || m.options.Threads != "3" {
+		t.Fatalf("Expected 3 threads, got %q (%v)", m.options.Threads, m.err)
+	}
+
+	m = press(m, "down", "space", "down", "down", "enter", ":core", "enter", "down", "space", "space")
+	if m.options.FailStrategy != maven.FailAtEnd || m.options.ResumeFrom != ":core" {
+		t.Fatalf("Expected -rf :core and fail at end, got %+v", m.options)
+	}
+
+	m = press(m, "esc")
+	cmd := maven.BuildCommand(m.project, []string{"install"}, m.options)
+	if got := strings.Join(cmd.Args, " "); got != "-pl :core -am -rf :core -fae -T 3 install" {
+		t.Errorf("Unexpected args %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, "-T 3 -am -rf :core -fae") {
+		t.Errorf("Expected the options pane to list the options set:\n%s", view)
+	}
+}
+
+func TestOptionsPane_NotesSnapshotsIgnoredOffline(t *testing.T) {
+	m := NewModel(&maven.Project{RootPath: t.TempDir(), Executable: "mvn"})
+	defer m.Close()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 50})
+	m = updated.(Model)
+
+	m = press(m, "3")
+	if strings.Contains(m.View(), "ignored offline") {
+		t.Error("Expected no note while online")
+	}
+	m = press(m, "2")
+	if !strings.Contains(m.View(), "Update Snapshots (ignored offline)") {
+		t.Errorf("Expected -U to be noted as ignored offline:\n%s", m.View())
+	}
+}
+
+func TestBuildOptionsView_ChosenSettingsListTheirProfiles(t *testing.T) {
+	dir := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("MAVEN_HOME", "")
+	t.Setenv("PATH", "")
+	settings := `<settings>
+  <profiles><profile><id>mirror</id></profile></profiles>
+  <activeProfiles><activeProfile>mirror</activeProfile></activeProfiles>
+</settings>`
+	if err := os.WriteFile(filepath.Join(dir, "ci-settings.xml"), []byte(settings), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(&maven.Project{RootPath: dir, Executable: "mvn"})
+	defer m.Close()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 50})
+	m = updated.(Model)
+
+	m = press(m, "o")
+	for m.optionCursor < optionSettings {
+		m = press(m, "down")
+	}
+	m = press(m, "enter", "ci-settings.xml", "enter", "esc")
+	if len(m.project.Profiles) != 1 || !m.project.Profiles[0].AutoActive {
+		t.Fatalf("Expected the chosen settings.xml to activate mirror, got %+v", m.project.Profiles)
+	}
+	if view := m.View(); !strings.Contains(view, "[A] 1. mirror") {
+		t.Errorf("Expected mirror listed as active:\n%s", view)
+	}
+
+	// Clearing the option goes back to ~/.m2/settings.xml, which has none
+	m = press(m, "o", "backspace", "esc")
+	if len(m.project.Profiles) != 0 {
+		t.Errorf("Expected the profiles of the chosen settings to go, got %+v", m.project.Profiles)
+	}
+}
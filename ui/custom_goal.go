@@ -0,0 +1,259 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AR0106/mvn-tui/export"
+	"github.com/AR0106/mvn-tui/maven"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// goalCompletionsShown is how many candidates the prompt lists after an ambiguous Tab
+const goalCompletionsShown = 8
+
+// openCustomGoal shows the custom goal prompt
+func (m *Model) openCustomGoal() {
+	m.customGoalInput.SetValue("")
+	m.customGoalInput.Focus()
+	m.goalHistoryPos = len(m.goalHistory)
+	m.goalCompletions = nil
+	m.goalCompletion = -1
+	m.err = nil
+	m.notice = ""
+	m.currentView = ViewCustomGoal
+}
+
+// handleCustomGoalKey handles keys in the custom goal prompt
+func (m *Model) handleCustomGoalKey(msg tea.KeyMsg) tea.Cmd {
+	if msg.String() != "tab" {
+		m.goalCompletions = nil
+		m.goalCompletion = -1
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.customGoalInput.Blur()
+		m.err = nil
+		m.currentView = ViewMain
+		return nil
+
+	case "tab":
+		m.completeGoal()
+		return nil
+
+	case "up":
+		// Browse the lines typed before, newest first
+		if m.goalHistoryPos > 0 {
+			m.goalHistoryPos--
+			m.customGoalInput.SetValue(m.goalHistory[m.goalHistoryPos])
+			m.customGoalInput.CursorEnd()
+		}
+		return nil
+
+	case "down":
+		if m.goalHistoryPos < len(m.goalHistory) {
+			m.goalHistoryPos++
+			line := ""
+			if m.goalHistoryPos < len(m.goalHistory) {
+				line = m.goalHistory[m.goalHistoryPos]
+			}
+			m.customGoalInput.SetValue(line)
+			m.customGoalInput.CursorEnd()
+		}
+		return nil
+
+	case "enter":
+		return m.runCustomGoal()
+	}
+
+	var cmd tea.Cmd
+	m.customGoalInput, cmd = m.customGoalInput.Update(msg)
+	return cmd
+}
+
+// completeGoal completes the word before the cursor from the goals typed
+// before, the lifecycle phases and the goals of the project's plugins. An
+// ambiguous word is completed as far as the candidates agree and they are
+// listed; Tab again cycles through them.
+func (m *Model) completeGoal() {
+	value := []rune(m.customGoalInput.Value())
+	pos := min(m.customGoalInput.Position(), len(value))
+	start := pos
+	for start > 0 && value[start-1] != ' ' {
+		start--
+	}
+	word := string(value[start:pos])
+
+	if m.goalCompletion >= 0 {
+		m.goalCompletion = (m.goalCompletion + 1) % len(m.goalCompletions)
+		m.replaceGoalWord(start, pos, m.goalCompletions[m.goalCompletion])
+		return
+	}
+	if strings.HasPrefix(word, "-") {
+		return
+	}
+
+	matches := maven.CompleteGoal(word, m.usedGoals(), maven.LifecyclePhases, m.project.PluginGoals())
+	switch len(matches) {
+	case 0:
+		return
+	case 1:
+		m.replaceGoalWord(start, pos, matches[0]+" ")
+		return
+	}
+
+	m.goalCompletions = matches
+	if prefix := commonPrefix(matches); prefix != word {
+		m.replaceGoalWord(start, pos, prefix)
+		return
+	}
+	m.goalCompletion = 0
+	m.replaceGoalWord(start, pos, matches[0])
+}
+
+// replaceGoalWord replaces the runes of the prompt between start and end,
+// leaving the cursor after the replacement
+func (m *Model) replaceGoalWord(start, end int, replacement string) {
+	value := []rune(m.customGoalInput.Value())
+	m.customGoalInput.SetValue(string(value[:start]) + replacement + string(value[end:]))
+	m.customGoalInput.SetCursor(start + len([]rune(replacement)))
+}
+
+// usedGoals returns the goals and phases of the lines typed before, most
+// recent first
+func (m *Model) usedGoals() []string {
+	var goals []string
+	for i := len(m.goalHistory) - 1; i >= 0; i-- {
+		args, err := maven.SplitArgs(m.goalHistory[i])
+		if err != nil {
+			continue
+		}
+		goals = append(goals, export.Goals(maven.Command{Args: args})...)
+	}
+	return goals
+}
+
+// commonPrefix returns the longest prefix shared by words
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// customGoalArgs splits a line typed at the custom goal prompt into
+// arguments, quoted as in a shell, and checks that it names a goal and that
+// every -D names a property
+func customGoalArgs(line string) ([]string, error) {
+	args, err := maven.SplitArgs(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(export.Goals(maven.Command{Args: args})) == 0 {
+		return nil, fmt.Errorf("enter a goal or phase, e.g. dependency:tree")
+	}
+
+	for i, arg := range args {
+		property, ok := strings.CutPrefix(arg, "-D")
+		if !ok {
+			continue
+		}
+		if property == "" {
+			// Maven also takes the property as the next argument
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("-D needs a property, e.g. -Dname=value")
+			}
+			property = args[i+1]
+		}
+		name, _, _ := strings.Cut(property, "=")
+		if name == "" {
+			return nil, fmt.Errorf("%s needs a property name", arg)
+		}
+		if strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("property name %q contains a space; quote only the value, e.g. -Dname=\"two words\"", name)
+		}
+	}
+	return args, nil
+}
+
+// runCustomGoal runs the typed goals with the current modules, profiles and
+// options, remembering the line for completion and the ↑/↓ keys
+func (m *Model) runCustomGoal() tea.Cmd {
+	line := strings.TrimSpace(m.customGoalInput.Value())
+	args, err := customGoalArgs(line)
+	if err != nil {
+		m.err = fmt.Errorf("invalid goal: %w", err)
+		return nil
+	}
+
+	kept := m.goalHistory[:0]
+	for _, goal := range m.goalHistory {
+		if goal != line {
+			kept = append(kept, goal)
+		}
+	}
+	m.goalHistory = append(kept, line)
+	var saveCmd tea.Cmd
+	if store := m.historyStore; store != nil {
+		saveCmd = func() tea.Msg {
+			_, err := store.AddGoals(line)
+			return savedMsg{err: err}
+		}
+	}
+
+	cmd := maven.BuildCommand(m.project, args, m.options)
+	m.customGoalInput.Blur()
+	m.err = nil
+	m.resetLog(fmt.Sprintf("Executing: %s", cmd.String()), "")
+	m.currentView = ViewLogs
+	m.updateLogViewport()
+	return tea.Batch(saveCmd, m.runMavenCommand("Custom", cmd))
+}
+
+// renderCustomGoalView renders the custom goal prompt
+func (m Model) renderCustomGoalView() string {
+	header := m.renderHeader()
+
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render("Run custom goals"))
+	sb.WriteString("\n\n")
+	sb.WriteString(m.customGoalInput.View())
+	sb.WriteString("\n")
+
+	if len(m.goalCompletions) > 0 {
+		sb.WriteString("\n")
+		selected := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+		for i, candidate := range m.goalCompletions {
+			if i == goalCompletionsShown {
+				sb.WriteString(fmt.Sprintf("  … %d more\n", len(m.goalCompletions)-i))
+				break
+			}
+			if i == m.goalCompletion {
+				sb.WriteString("  " + selected.Render(candidate) + "\n")
+			} else {
+				sb.WriteString("  " + candidate + "\n")
+			}
+		}
+	}
+
+	if args, err := maven.SplitArgs(m.customGoalInput.Value()); err == nil && len(args) > 0 {
+		sb.WriteString("\n" + maven.BuildCommand(m.project, args, m.options).String())
+	}
+
+	border := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("205")).
+		Padding(0, 1)
+
+	footer := "Enter: Run | Tab: Complete | ↑/↓: Previous goals | Esc: Cancel"
+	if m.err != nil {
+		footer = fmt.Sprintf("✗ %v | %s", m.err, footer)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, border.Render(sb.String()), footer)
+}
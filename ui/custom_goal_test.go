@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AR0106/mvn-tui/history"
+	"github.com/AR0106/mvn-tui/maven"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCustomGoal_CompletesFromPhasesPluginsAndHistory(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	store, err := history.Open(dir, history.Retention{})
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	if _, err := store.AddGoals("clean dependency:tree"); err != nil {
+		t.Fatalf("Failed to save goals: %v", err)
+	}
+
+	project := &maven.Project{RootPath: dir, Executable: "mvn", Plugins: []maven.Plugin{{GroupID: "org.springframework.boot", ArtifactID: "spring-boot-maven-plugin"}}}
+	m := NewModel(project).WithHistory(store)
+	defer m.Close()
+
+	m = press(m, "c")
+	if m.currentView != ViewCustomGoal {
+		t.Fatalf("Expected the custom goal prompt, got view %v", m.currentView)
+	}
+
+	tests := []struct {
+		keys []string
+		want string
+	}{
+		{[]string{"insta", "tab"}, "install "},
+		{[]string{"spring-boot:ru", "tab"}, "spring-boot:run "},
+		// An ambiguous word cycles, starting with goals used before
+		{[]string{"dep", "tab"}, "dependency:tree"},
+		{[]string{"dep", "tab", "tab"}, "deploy"},
+		{[]string{"versions:display-d", "tab"}, "versions:display-dependency-updates "},
+		{[]string{"-Dskip", "tab"}, "-Dskip"},
+		{[]string{"up"}, "clean dependency:tree"},
+	}
+	for _, tt := range tests {
+		m.customGoalInput.SetValue("")
+		m.goalHistoryPos = len(m.goalHistory)
+		m = press(m, tt.keys...)
+		if got := m.customGoalInput.Value(); got != tt.want {
+			t.Errorf("%v: got %q, want %q", tt.keys, got, tt.want)
+		}
+	}
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	if view := updated.(Model).View(); !strings.Contains(view, "mvn clean dependency:tree") {
+		t.Errorf("Expected the command to be previewed:\n%s", view)
+	}
+}
+
+func TestCustomGoal_RunsQuotedArguments(t *testing.T) {
+	dir := t.TempDir()
+	m := NewModel(&maven.Project{RootPath: dir, Executable: "mvn"})
+	defer m.Close()
+
+	for _, line := range []string{"", "-o -P ci", `versions:set -D"new version"=2`, "package -D", `package -Dmsg="unterminated`} {
+		m = press(m, "c")
+		m.customGoalInput.SetValue(line)
+		if m = press(m, "enter"); m.err == nil || m.currentView != ViewCustomGoal {
+			t.Errorf("Expected %q to be refused", line)
+		}
+		m = press(m, "esc")
+	}
+
+	m = press(m, "c")
+	m.customGoalInput.SetValue(`versions:set -DnewVersion="2.0 beta" -D generateBackupPoms=false`)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	m.jobs.Shutdown()
+	if cmd == nil || m.currentView != ViewLogs {
+		t.Fatalf("Expected the goal to run, got err %v", m.err)
+	}
+
+	job := m.jobs.Get(m.activeJob)
+	want := []string{"versions:set", "-DnewVersion=2.0 beta", "-D", "generateBackupPoms=false"}
+	if job == nil || strings.Join(job.Command.Args, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected args %q, got %+v", want, job)
+	}
+	if len(m.goalHistory) != 1 || !strings.HasPrefix(m.goalHistory[0], "versions:set") {
+		t.Errorf("Expected the line to be remembered, got %q", m.goalHistory)
+	}
+}
@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AR0106/mvn-tui/export"
+	"github.com/AR0106/mvn-tui/maven"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// createExportInput creates the text input for the export file
+func createExportInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "File to write, relative to the project root"
+	input.Width = 60
+
+	return input
+}
+
+// taskCommand builds the command a task runs with the current options
+func (m *Model) taskCommand(task Task) maven.Command {
+	// Recipes bring their own modules, profiles and options for this run only
+	project, options := m.project, m.options
+	if task.Recipe != nil {
+		project, options = task.Recipe.Apply(project, options)
+	}
+	return maven.BuildCommand(project, task.Goals, options)
+}
+
+// toggleHistorySelection marks or unmarks the highlighted history entry for export
+func (m *Model) toggleHistorySelection() {
+	idx := m.selectedHistoryIndex()
+	if idx < 0 {
+		return
+	}
+	if m.historySelected == nil {
+		m.historySelected = make(map[int]bool)
+	}
+	if m.historySelected[idx] {
+		delete(m.historySelected, idx)
+	} else {
+		m.historySelected[idx] = true
+	}
+	m.refreshHistoryList()
+}
+
+// exportHistory opens the export prompt for the selected history entries, in
+// the order they ran, or for the highlighted entry if none are selected
+func (m *Model) exportHistory() {
+	var indexes []int
+	for idx := range m.historySelected {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+	if len(indexes) == 0 {
+		idx := m.selectedHistoryIndex()
+		if idx < 0 {
+			return
+		}
+		indexes = []int{idx}
+	}
+
+	steps := make([]export.Step, len(indexes))
+	for i, idx := range indexes {
+		cmd := m.history[idx].Command
+		name := strings.Join(export.Goals(cmd), " ")
+		if name == "" {
+			name = "Maven"
+		}
+		steps[i] = export.Step{Name: name, Command: cmd}
+	}
+	m.openExport(steps)
+}
+
+// exportTask opens the export prompt for the highlighted task
+func (m *Model) exportTask() {
+	idx := m.tasksList.Index()
+	if idx < 0 || idx >= len(m.tasks) {
+		return
+	}
+	task := m.tasks[idx]
+	m.openExport([]export.Step{{Name: task.Name, Command: m.taskCommand(task)}})
+}
+
+// openExport shows the export prompt for steps
+func (m *Model) openExport(steps []export.Step) {
+	m.exportSteps = steps
+	m.exportReturn = m.currentView
+	m.exportOverwrite = ""
+	m.exportInput.SetValue(m.exportFormat.DefaultFile())
+	m.exportInput.CursorEnd()
+	m.exportInput.Focus()
+	m.err = nil
+	m.notice = ""
+	m.currentView = ViewExport
+}
+
+// handleExportKey handles keys in the export prompt
+func (m *Model) handleExportKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.exportInput.Blur()
+		m.currentView = m.exportReturn
+		return nil
+
+	case "tab", "shift+tab":
+		// Cycle formats, keeping the file name unless it is the suggested one
+		step := 1
+		if msg.String() == "shift+tab" {
+			step = len(export.Formats) - 1
+		}
+		previous := m.exportFormat
+		m.exportFormat = export.Formats[(int(previous)+step)%len(export.Formats)]
+		if strings.TrimSpace(m.exportInput.Value()) == previous.DefaultFile() {
+			m.exportInput.SetValue(m.exportFormat.DefaultFile())
+			m.exportInput.CursorEnd()
+		}
+		m.exportOverwrite = ""
+		return nil
+
+	case "enter":
+		m.writeExport()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.exportInput, cmd = m.exportInput.Update(msg)
+	m.exportOverwrite = ""
+	return cmd
+}
+
+// exportPath resolves the file named in the prompt
+func (m *Model) exportPath() (string, error) {
+	path := strings.TrimSpace(m.exportInput.Value())
+	if path == "" {
+		return "", fmt.Errorf("enter a file to export to")
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, rest)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.project.RootPath, path)
+	}
+	return path, nil
+}
+
+// writeExport writes the steps to the chosen file, asking before replacing one
+func (m *Model) writeExport() {
+	path, err := m.exportPath()
+	if err != nil {
+		m.err = err
+		return
+	}
+	if _, err := os.Stat(path); err == nil && m.exportOverwrite != path {
+		m.exportOverwrite = path
+		m.err = fmt.Errorf("%s exists; press Enter again to replace it", path)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, m.exportFormat, m.project.RootPath, m.exportSteps); err != nil {
+		m.err = fmt.Errorf("failed to export: %w", err)
+		return
+	}
+	perm := os.FileMode(0644)
+	if m.exportFormat == export.FormatShell {
+		perm = 0755
+	}
+	if err := os.WriteFile(path, buf.Bytes(), perm); err != nil {
+		m.err = fmt.Errorf("failed to export: %w", err)
+		return
+	}
+	// WriteFile keeps the mode of a file it replaces
+	os.Chmod(path, perm)
+
+	m.err = nil
+	m.notice = fmt.Sprintf("Exported %d command(s) to %s", len(m.exportSteps), path)
+	m.historySelected = nil
+	m.refreshHistoryList()
+	m.exportInput.Blur()
+	m.currentView = m.exportReturn
+}
+
+// renderExportView renders the export prompt
+func (m Model) renderExportView() string {
+	header := m.renderHeader()
+
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Export %d command(s)", len(m.exportSteps))))
+	sb.WriteString("\n\n")
+	for _, step := range m.exportSteps {
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", step.Name, step.Command.String()))
+	}
+
+	sb.WriteString("\nFormat: ")
+	for i, format := range export.Formats {
+		if i > 0 {
+			sb.WriteString("  ")
+		}
+		if format == m.exportFormat {
+			sb.WriteString(lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Render("[" + format.String() + "]"))
+		} else {
+			sb.WriteString(" " + format.String() + " ")
+		}
+	}
+	sb.WriteString("\n\nFile: " + m.exportInput.View())
+
+	border := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("205")).
+		Padding(0, 1)
+
+	footer := "Enter: Export | Tab: Change format | Esc: Cancel"
+	if m.err != nil {
+		footer = fmt.Sprintf("✗ %v | %s", m.err, footer)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, border.Render(sb.String()), footer)
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AR0106/mvn-tui/config"
+	"github.com/AR0106/mvn-tui/history"
+	"github.com/AR0106/mvn-tui/maven"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// press sends keys to the model as the program would
+func press(m Model, keys ...string) Model {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "space":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+		case "up":
+			msg = tea.KeyMsg{Type: tea.KeyUp}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "ctrl+u":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlU}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+	return m
+}
+
+func TestModel_ExportsSelectedHistory(t *testing.T) {
+	dir := t.TempDir()
+	project := &maven.Project{RootPath: dir, Executable: filepath.Join(dir, "mvnw")}
+	m := NewModel(project)
+	defer m.Close()
+	for _, args := range [][]string{{"-P", "ci", "clean"}, {"compile"}, {"-pl", "core", "install"}} {
+		m.history = append(m.history, history.Entry{Command: maven.Command{Executable: project.Executable, Args: args}})
+	}
+	m.refreshHistoryList()
+
+	// Select the newest and oldest entries; the list shows the newest first
+	m = press(m, "h", "space", "down", "down", "space", "x")
+	if m.currentView != ViewExport {
+		t.Fatalf("Expected the export prompt, got view %v", m.currentView)
+	}
+	m = press(m, "enter")
+	if m.err != nil || m.currentView != ViewHistory {
+		t.Fatalf("Expected the export to succeed, got %v", m.err)
+	}
+
+	script, err := os.ReadFile(filepath.Join(dir, "mvn-tui.sh"))
+	if err != nil {
+		t.Fatalf("Expected the script in the project root: %v", err)
+	}
+	want := "# clean\n# Profiles: ci\n./mvnw -P ci clean\n\n# install\n# Modules: core\n./mvnw -pl core install\n"
+	if !strings.HasSuffix(string(script), want) {
+		t.Errorf("Expected the selected commands in the order they ran, got:\n%s", script)
+	}
+	if len(m.historySelected) != 0 {
+		t.Error("Expected the selection to be cleared after exporting")
+	}
+}
+
+func TestModel_ExportsTaskAndAsksBeforeReplacing(t *testing.T) {
+	dir := t.TempDir()
+	project := &maven.Project{RootPath: dir, Executable: "mvn", Profiles: []maven.Profile{{ID: "dev", Enabled: true}}}
+	cfg := config.Merge(config.BuiltIn(false), config.Layer{Source: config.SourceProject, Config: &config.Config{
+		Recipes: []config.Recipe{{Name: "Site", Goals: []string{"site"}, Profiles: []string{"docs"}}},
+	}})
+	m, err := NewModelWithConfig(project, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	defer m.Close()
+
+	target := filepath.Join(dir, "ci.yml")
+	if err := os.WriteFile(target, []byte("keep"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	m.tasksList.Select(len(m.tasks) - 1)
+	m = press(m, "x", "tab", "tab", "ctrl+u", "ci.yml", "enter")
+	if m.currentView != ViewExport || m.err == nil {
+		t.Fatal("Expected to be asked before replacing ci.yml")
+	}
+	if data, _ := os.ReadFile(target); string(data) != "keep" {
+		t.Fatal("Expected ci.yml to be left alone until confirmed")
+	}
+
+	m = press(m, "enter")
+	if m.currentView != ViewMain || !strings.Contains(m.notice, target) {
+		t.Fatalf("Expected the export to finish with a notice, got %q (err %v)", m.notice, m.err)
+	}
+	data, _ := os.ReadFile(target)
+	if !strings.Contains(string(data), "- name: Site\n  run: mvn -P dev,docs site\n") {
+		t.Errorf("Expected a GitHub Actions step with the recipe's profiles, got:\n%s", data)
+	}
+}
@@ -1,24 +1,24 @@
 package ui
 
 import (
-	"bufio"
-	"bytes"
-	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"regexp"
 	"strings"
-	"time"
 
+	"github.com/AR0106/mvn-tui/history"
 	"github.com/AR0106/mvn-tui/maven"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 // handleEnter handles the Enter key press based on current view
 func (m *Model) handleEnter() (Model, tea.Cmd) {
-	if m.currentView == ViewMain && m.focusedPane == 1 {
+	if m.currentView == ViewMain && m.focusedPane == 0 {
+		// Fold or unfold the highlighted aggregator
+		if item, ok := m.modulesList.SelectedItem().(moduleItem); ok {
+			m.setModuleCollapsed(!item.collapsed)
+		}
+	} else if m.currentView == ViewMain && m.focusedPane == 1 {
 		// Execute selected task
 		selectedIdx := m.tasksList.Index()
 		if selectedIdx >= 0 && selectedIdx < len(m.tasks) {
@@ -27,15 +27,21 @@ func (m *Model) handleEnter() (Model, tea.Cmd) {
 		}
 	} else if m.currentView == ViewHistory {
 		// Re-run command from history
-		selectedIdx := m.historyList.Index()
-		if selectedIdx >= 0 && selectedIdx < len(m.history) {
-			histIdx := len(m.history) - 1 - selectedIdx
-			result := m.history[histIdx]
-			m.logBuffer = []string{fmt.Sprintf("Re-executing: %s", result.Command.String()), ""}
-			m.running = true
-			m.currentView = ViewLogs
-			m.updateLogViewport()
-			return *m, m.runMavenCommand(result.Command)
+		if idx := m.selectedHistoryIndex(); idx >= 0 {
+			runCmd := m.rerun(m.history[idx].Command)
+			return *m, runCmd
+		}
+	} else if m.currentView == ViewJobs {
+		// Show the selected job's terminal or output
+		if job := m.selectedJob(); job != nil && job.Terminal != nil {
+			m.openTerminal(job)
+		} else if job != nil {
+			m.showJobLog(job)
+		}
+	} else if m.currentView == ViewProblems {
+		// Open the selected problem in the editor
+		if problem := m.selectedProblem(); problem != nil {
+			return *m, m.openProblem(*problem)
 		}
 	} else if m.currentView == ViewProjectCreation && m.projectCreation != nil {
 		// Execute project creation
@@ -66,14 +72,13 @@ func (m *Model) handleProjectCreation() (Model, tea.Cmd) {
 	artifactId := m.projectCreation.GetArtifactId()
 	javaVersion := m.projectCreation.GetSelectedJavaVersion()
 
-	m.logBuffer = []string{
+	m.resetLog(
 		fmt.Sprintf("Creating project: %s", cmd.String()),
 		fmt.Sprintf("Folder name: %s", folderName),
 		fmt.Sprintf("Maven artifact ID: %s", artifactId),
 		fmt.Sprintf("Java version: %s", javaVersion.Version),
 		"",
-	}
-	m.running = true
+	)
 	m.currentView = ViewLogs
 
 	// Store folder name for post-creation rename if it differs from artifactId
@@ -85,41 +90,44 @@ func (m *Model) handleProjectCreation() (Model, tea.Cmd) {
 	m.pendingJavaVersion = javaVersion.Version
 
 	m.updateLogViewport()
-	return *m, m.runMavenCommand(cmd)
+	runCmd := m.runMavenCommand("Create project "+artifactId, cmd)
+	m.creationJob = m.activeJob
+	return *m, runCmd
 }
 
 // handleSpace handles the Space key press
 func (m *Model) handleSpace() (Model, tea.Cmd) {
 	if m.currentView == ViewMain && m.focusedPane == 0 {
-		// Toggle module selection
-		selectedIdx := m.modulesList.Index()
-		if selectedIdx >= 0 && selectedIdx < len(m.project.Modules) {
-			m.project.ToggleModule(selectedIdx)
+		// Toggle module selection; hidden modules leave gaps in the list
+		if item, ok := m.modulesList.SelectedItem().(moduleItem); ok {
+			m.project.ToggleModule(item.index)
 			m.refreshModulesList()
 		}
+	} else if m.currentView == ViewMain && m.focusedPane == 2 {
+		m.project.ToggleProfile(m.profileCursor)
+	} else if m.currentView == ViewHistory {
+		m.toggleHistorySelection()
 	}
 	return *m, nil
 }
 
 // executeTask executes a Maven task with the current build options
 func (m *Model) executeTask(task Task) (Model, tea.Cmd) {
-	cmd := maven.BuildCommand(m.project, task.Goals, m.options)
+	cmd := m.taskCommand(task)
 
 	// Check if this is a Run task that needs interactive input
 	if strings.Contains(task.Name, "Run") {
-		// Set up logs view before interactive execution
-		m.logBuffer = []string{}
-		m.currentView = ViewLogs
-		m.updateLogViewport()
-		// Use interactive execution for Run tasks to support Scanner and other input
-		return *m, m.runInteractiveMavenCommand(cmd)
+		// Run tasks get a terminal pane to support Scanner and other input
+		m.resetLog()
+		runCmd := m.runTerminalCommand(task.Name, cmd)
+		return *m, runCmd
 	}
 
-	m.logBuffer = []string{fmt.Sprintf("Executing: %s", cmd.String()), ""}
-	m.running = true
+	m.resetLog(fmt.Sprintf("Executing: %s", cmd.String()), "")
 	m.currentView = ViewLogs
 	m.updateLogViewport()
-	return *m, m.runMavenCommand(cmd)
+	runCmd := m.runMavenCommand(task.Name, cmd)
+	return *m, runCmd
 }
 
 // quickRun finds and executes the first run task
@@ -127,12 +135,12 @@ func (m *Model) quickRun() (Model, tea.Cmd) {
 	// Find the first run task in the task list
 	for _, task := range m.tasks {
 		if strings.Contains(task.Name, "Run") {
-			m.logBuffer = []string{fmt.Sprintf("Quick Run: %s", task.Name), ""}
+			m.resetLog(fmt.Sprintf("Quick Run: %s", task.Name), "")
 			return m.executeTask(task)
 		}
 	}
 	// No run task found
-	m.logBuffer = []string{"No run task available for this project"}
+	m.resetLog("No run task available for this project")
 	m.updateLogViewport()
 	return *m, nil
 }
@@ -157,16 +165,17 @@ func (m *Model) handleModuleCreation() (Model, tea.Cmd) {
 	cmd := m.moduleCreation.BuildCreateModuleCommand(m.project.RootPath)
 	moduleName := m.moduleCreation.GetModuleName()
 
-	m.logBuffer = []string{
+	m.resetLog(
 		fmt.Sprintf("Creating module: %s", moduleName),
 		fmt.Sprintf("Command: %s", cmd.String()),
 		"",
-	}
-	m.running = true
+	)
 	m.currentView = ViewLogs
 	m.pendingModuleName = moduleName // Track for automatic pom.xml update
 	m.updateLogViewport()
-	return *m, m.runMavenCommand(cmd)
+	runCmd := m.runMavenCommand("Create module "+moduleName, cmd)
+	m.creationJob = m.activeJob
+	return *m, runCmd
 }
 
 // handleDependencyAddition handles adding a dependency to the project
@@ -198,7 +207,7 @@ func (m *Model) handleDependencyAddition() (Model, tea.Cmd) {
 	}
 	depXML.WriteString("    </dependency>")
 
-	m.logBuffer = []string{
+	m.resetLog(
 		"Add this dependency to your pom.xml:",
 		"",
 		depXML.String(),
@@ -208,13 +217,13 @@ func (m *Model) handleDependencyAddition() (Model, tea.Cmd) {
 		"Dependency details:",
 		fmt.Sprintf("  GroupID: %s", dep.GroupID),
 		fmt.Sprintf("  ArtifactID: %s", dep.ArtifactID),
-	}
+	)
 
 	if dep.Version != "" {
-		m.logBuffer = append(m.logBuffer, fmt.Sprintf("  Version: %s", dep.Version))
+		m.logStore.Append(fmt.Sprintf("  Version: %s", dep.Version))
 	}
 	if dep.Scope != "" {
-		m.logBuffer = append(m.logBuffer, fmt.Sprintf("  Scope: %s", dep.Scope))
+		m.logStore.Append(fmt.Sprintf("  Scope: %s", dep.Scope))
 	}
 
 	m.currentView = ViewLogs
@@ -222,159 +231,80 @@ func (m *Model) handleDependencyAddition() (Model, tea.Cmd) {
 	return *m, nil
 }
 
-// runMavenCommand executes a Maven command asynchronously
-func (m *Model) runMavenCommand(cmd maven.Command) tea.Cmd {
-	return func() tea.Msg {
-		// Create a cancellable context for this execution
-		ctx, cancel := context.WithCancel(m.ctx)
-		m.cancelFunc = cancel
-
-		// Execute the Maven command with streaming output
-		result, err := maven.Execute(
-			ctx,
-			cmd,
-			m.project.RootPath,
-			func(line string) {
-				// Note: This callback runs in the executor goroutine
-				// We can't directly send to the program here, but we'll
-				// include all output in the result
-			},
-		)
-
-		if err != nil && result.Error == nil {
-			result.Error = err
-		}
-
-		// Clear the cancel function
-		m.cancelFunc = nil
+// runMavenCommand starts a Maven command as a background job that writes
+// into the current log, and shows that job in the logs view
+func (m *Model) runMavenCommand(name string, cmd maven.Command) tea.Cmd {
+	job, runCmd := m.jobs.Start(m.ctx, name, cmd, m.project.RootPath, m.logStore)
+	job.Progress = maven.NewReactorProgress(m.project.Modules)
+	m.activeJob = job.ID
+	m.refreshJobsList()
+	return m.withProgressTick(runCmd)
+}
 
-		return executionCompleteMsg{result: result}
+// withProgressTick adds the progress tick to a job's command unless it is
+// already scheduled, to keep elapsed times moving while Maven is quiet
+func (m *Model) withProgressTick(runCmd tea.Cmd) tea.Cmd {
+	if m.ticking {
+		return runCmd
 	}
+	m.ticking = true
+	return tea.Batch(runCmd, progressTick())
 }
 
-// ANSI escape code regex to strip color codes and other terminal sequences
-// This matches ESC[...m codes and also bare escape sequences
-var ansiRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]|\x1b\].*?\x07|\x1b[^\[]*`)
-
-// runInteractiveMavenCommand executes a Maven command interactively with full terminal access
-// This temporarily exits the TUI to allow user input (e.g., Scanner in Java programs)
+// runInteractiveMavenCommand suspends the TUI and runs a Maven command under
+// a pseudo-terminal, for when the terminal pane is unavailable. Programs
+// reading input (e.g., Scanner in Java programs) work, and the session is
+// recorded in the log.
 func (m *Model) runInteractiveMavenCommand(cmd maven.Command) tea.Cmd {
-	// Create a temporary file to capture full terminal session (including user input)
-	tmpfile, err := os.CreateTemp("", "mvn-tui-typescript-*.txt")
-	if err != nil {
-		return func() tea.Msg {
-			result := &maven.ExecutionResult{
-				Command:   cmd,
-				ExitCode:  1,
-				Error:     err,
-				Output:    []string{fmt.Sprintf("Failed to create temp file: %v", err)},
-				StartTime: time.Now(),
-			}
-			return executionCompleteMsg{result: result}
-		}
-	}
-	tmpfilePath := tmpfile.Name()
-	tmpfile.Close()
-
-	startTime := time.Now()
-
-	// Use script command to capture full terminal session including user input
-	// script -q (quiet) suppresses the "Script started/done" messages
-	// We'll pipe through col -b to remove control characters and backspaces
-	shellCmd := fmt.Sprintf("script -q %s %s %s",
-		tmpfilePath,
-		cmd.Executable,
-		strings.Join(cmd.Args, " "))
-
-	c := exec.Command("sh", "-c", shellCmd)
-	c.Dir = m.project.RootPath
-	c.Stdin = os.Stdin
-	c.Stdout = os.Stdout
-	c.Stderr = os.Stderr
-
-	return tea.ExecProcess(c, func(err error) tea.Msg {
-		// Give the file system a moment to flush
-		time.Sleep(100 * time.Millisecond)
-
-		result := &maven.ExecutionResult{
-			Command:   cmd,
-			StartTime: startTime,
-			Duration:  time.Since(startTime),
-			Output:    []string{},
-			ExitCode:  0,
-		}
-
-		// Read captured output from temp file (script command captures everything)
-		outputBytes, readErr := os.ReadFile(tmpfilePath)
-		if readErr == nil {
-			if len(outputBytes) > 0 {
-				// Clean the output by removing control characters using col -b
-				colCmd := exec.Command("col", "-b")
-				colCmd.Stdin = bytes.NewReader(outputBytes)
-				cleanedBytes, colErr := colCmd.Output()
-
-				if colErr == nil && len(cleanedBytes) > 0 {
-					outputBytes = cleanedBytes
-				}
-
-				// Parse the cleaned output and remove ANSI escape codes
-				scanner := bufio.NewScanner(bytes.NewReader(outputBytes))
-				for scanner.Scan() {
-					line := scanner.Text()
-					// Strip ANSI escape codes (colors, cursor movements, etc.)
-					line = ansiRegex.ReplaceAllString(line, "")
-					// Also remove any remaining escape characters and common artifacts
-					line = strings.ReplaceAll(line, "\x1b", "")
-					line = strings.ReplaceAll(line, "0m0m", "")
-					line = strings.ReplaceAll(line, "0m", "")
-					// Keep all lines including user input
-					result.Output = append(result.Output, line)
-				}
-			}
-		}
+	log := m.logStore
+	initialLines := log.Len()
 
+	c := maven.NewPTYCommand(cmd, m.project.RootPath, log)
+	return tea.Exec(c, func(err error) tea.Msg {
 		// If no output was captured, add a helpful message
-		if len(result.Output) == 0 {
-			result.Output = append(result.Output, "(Program executed but no output was captured)")
-			result.Output = append(result.Output, "This can happen if the program runs very quickly or produces no output.")
-		}
-
-		// Clean up temp file
-		os.Remove(tmpfilePath)
-
-		// Handle exit code
-		if err != nil {
-			if exitErr, ok := err.(*exec.ExitError); ok {
-				result.ExitCode = exitErr.ExitCode()
-			} else {
-				result.Error = err
-			}
+		if log.Len() == initialLines {
+			log.Append("(Program executed but no output was captured)")
 		}
-
-		return executionCompleteMsg{result: result}
+		return executionCompleteMsg{result: c.Result}
 	})
 }
 
-// handleExecutionComplete processes the completion of a Maven command execution
-func (m *Model) handleExecutionComplete(msg executionCompleteMsg) {
-	m.running = false
+// handleExecutionComplete processes the completion of a Maven command
+// execution. The returned command saves it to the persistent history.
+func (m *Model) handleExecutionComplete(msg executionCompleteMsg) tea.Cmd {
 	m.lastResult = msg.result
-	m.history = append(m.history, *msg.result)
+	m.jobs.Complete(msg.jobID, msg.result)
+	m.refreshJobsList()
+
+	// Show the output if this is the command being watched; background jobs finish quietly
+	watched := msg.jobID == 0 || msg.jobID == m.activeJob
+	if m.currentView == ViewTerminal && msg.jobID == m.terminalJob {
+		// Leave the finished program's screen up until the user moves on
+		m.terminalFocused = false
+	} else if watched {
+		m.currentView = ViewLogs
+	}
 
-	// Ensure we're in logs view to show the output
-	m.currentView = ViewLogs
+	// Write the summary into the command's own log, which may not be the one on screen
+	log := msg.result.Log
 
-	// Append all output from the execution result
-	m.logBuffer = append(m.logBuffer, msg.result.Output...)
+	// Only the job that created a project or module gets the post-creation steps
+	isCreation := msg.jobID != 0 && msg.jobID == m.creationJob
+	if isCreation {
+		m.creationJob = 0
+	}
 
 	// Add completion message
 	if msg.result.Error != nil {
-		m.logBuffer = append(m.logBuffer, "", fmt.Sprintf("Error: %v", msg.result.Error))
+		log.Append("", fmt.Sprintf("Error: %v", msg.result.Error))
+	}
+	log.Append("", fmt.Sprintf("Completed with exit code %d in %v", msg.result.ExitCode, msg.result.Duration))
+	if msg.result.ResumeFrom != "" {
+		log.Append(fmt.Sprintf("Press Shift+F to resume the build from %s", msg.result.ResumeFrom))
 	}
-	m.logBuffer = append(m.logBuffer, "", fmt.Sprintf("Completed with exit code %d in %v", msg.result.ExitCode, msg.result.Duration))
 
 	// If this was a project creation, handle post-creation tasks
-	if m.projectCreation != nil && msg.result.ExitCode == 0 && m.currentView == ViewLogs {
+	if isCreation && m.projectCreation != nil && msg.result.ExitCode == 0 {
 		artifactId := m.projectCreation.GetArtifactId()
 		projectPath := filepath.Join(m.project.RootPath, artifactId)
 		desiredFolderName := m.pendingModuleName
@@ -382,14 +312,14 @@ func (m *Model) handleExecutionComplete(msg executionCompleteMsg) {
 		// Update Java version in pom.xml
 		if m.pendingJavaVersion != "" {
 			pomPath := filepath.Join(projectPath, "pom.xml")
-			m.logBuffer = append(m.logBuffer, "", fmt.Sprintf("Updating Java version to %s in pom.xml...", m.pendingJavaVersion))
+			log.Append("", fmt.Sprintf("Updating Java version to %s in pom.xml...", m.pendingJavaVersion))
 
 			err := maven.UpdateJavaVersion(pomPath, m.pendingJavaVersion)
 			if err != nil {
-				m.logBuffer = append(m.logBuffer, fmt.Sprintf("Warning: Failed to update Java version: %v", err))
-				m.logBuffer = append(m.logBuffer, "You may need to manually update maven.compiler.source and maven.compiler.target")
+				log.Append(fmt.Sprintf("Warning: Failed to update Java version: %v", err))
+				log.Append("You may need to manually update maven.compiler.source and maven.compiler.target")
 			} else {
-				m.logBuffer = append(m.logBuffer, fmt.Sprintf("✓ Java version updated to %s", m.pendingJavaVersion))
+				log.Append(fmt.Sprintf("✓ Java version updated to %s", m.pendingJavaVersion))
 			}
 			m.pendingJavaVersion = ""
 		}
@@ -398,47 +328,66 @@ func (m *Model) handleExecutionComplete(msg executionCompleteMsg) {
 		if desiredFolderName != "" && desiredFolderName != artifactId {
 			newPath := filepath.Join(m.project.RootPath, desiredFolderName)
 
-			m.logBuffer = append(m.logBuffer, "", fmt.Sprintf("Renaming project directory from '%s' to '%s'...", artifactId, desiredFolderName))
+			log.Append("", fmt.Sprintf("Renaming project directory from '%s' to '%s'...", artifactId, desiredFolderName))
 
 			err := os.Rename(projectPath, newPath)
 			if err != nil {
-				m.logBuffer = append(m.logBuffer, fmt.Sprintf("Warning: Failed to rename directory: %v", err))
-				m.logBuffer = append(m.logBuffer, fmt.Sprintf("You can manually rename '%s' to '%s'", artifactId, desiredFolderName))
+				log.Append(fmt.Sprintf("Warning: Failed to rename directory: %v", err))
+				log.Append(fmt.Sprintf("You can manually rename '%s' to '%s'", artifactId, desiredFolderName))
 			} else {
-				m.logBuffer = append(m.logBuffer, fmt.Sprintf("✓ Project directory renamed to '%s'", desiredFolderName))
-				m.logBuffer = append(m.logBuffer, fmt.Sprintf("✓ Project created successfully in '%s'", newPath))
+				log.Append(fmt.Sprintf("✓ Project directory renamed to '%s'", desiredFolderName))
+				log.Append(fmt.Sprintf("✓ Project created successfully in '%s'", newPath))
 			}
 		} else {
-			m.logBuffer = append(m.logBuffer, fmt.Sprintf("✓ Project created successfully in '%s'", projectPath))
+			log.Append(fmt.Sprintf("✓ Project created successfully in '%s'", projectPath))
 		}
 
 		m.pendingModuleName = ""
 		m.projectCreation = nil // Clear project creation state
-	} else if m.pendingModuleName != "" && msg.result.ExitCode == 0 {
+	} else if isCreation && m.pendingModuleName != "" && msg.result.ExitCode == 0 {
 		// This was a module creation and it succeeded, add module to parent pom.xml
-		m.logBuffer = append(m.logBuffer, "", fmt.Sprintf("Adding module '%s' to parent pom.xml...", m.pendingModuleName))
+		log.Append("", fmt.Sprintf("Adding module '%s' to parent pom.xml...", m.pendingModuleName))
 
 		pomPath := m.project.RootPath + "/pom.xml"
 		err := maven.AddModuleToPom(pomPath, m.pendingModuleName)
 
 		if err != nil {
-			m.logBuffer = append(m.logBuffer, fmt.Sprintf("Warning: Failed to add module to pom.xml: %v", err))
-			m.logBuffer = append(m.logBuffer, "You'll need to manually add it to the <modules> section.")
+			log.Append(fmt.Sprintf("Warning: Failed to add module to pom.xml: %v", err))
+			log.Append("You'll need to manually add it to the <modules> section.")
 		} else {
-			m.logBuffer = append(m.logBuffer, fmt.Sprintf("✓ Module '%s' successfully added to parent pom.xml", m.pendingModuleName))
+			log.Append(fmt.Sprintf("✓ Module '%s' successfully added to parent pom.xml", m.pendingModuleName))
 
 			// Reload the project to pick up the new module
 			reloadedProject, err := maven.LoadProject(m.project.RootPath)
 			if err == nil {
 				m.project = reloadedProject
 				m.refreshModulesList()
-				m.logBuffer = append(m.logBuffer, "✓ Project reloaded with new module")
+				log.Append("✓ Project reloaded with new module")
 			}
 		}
 
 		m.pendingModuleName = "" // Clear the pending module
 	}
 
+	if watched {
+		m.logFollow = true
+	}
 	m.updateLogViewport()
+
+	// Record the command once its log is complete
+	entry := history.NewEntry(msg.result, history.GitBranch(m.project.RootPath))
+	m.history = append(m.history, entry)
 	m.refreshHistoryList()
+	return m.saveHistory(entry)
+}
+
+// saveHistory writes entry and its log to the history store in the background
+func (m *Model) saveHistory(entry history.Entry) tea.Cmd {
+	if m.historyStore == nil || entry.Log == nil {
+		return nil
+	}
+	store := m.historyStore
+	return func() tea.Msg {
+		return savedMsg{err: store.Add(entry, entry.Log)}
+	}
 }
@@ -0,0 +1,351 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/AR0106/mvn-tui/export"
+	"github.com/AR0106/mvn-tui/maven"
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// historyErrorLines is how many [ERROR] lines the detail pane shows
+const historyErrorLines = 5
+
+// writeClipboard copies text to the system clipboard; tests replace it
+var writeClipboard = clipboard.WriteAll
+
+// pairedOptions are shown on one line with their value in the detail pane
+var pairedOptions = map[string]bool{"-P": true, "-pl": true, "-T": true, "-rf": true}
+
+// historyDetail is what the detail pane shows for the highlighted entry,
+// kept so the entry's log is only read when the highlight moves
+type historyDetail struct {
+	index      int      // Index into history of the entry shown, -1 if none
+	errorLines []string // First [ERROR] lines of the entry's log
+	err        error    // Why the log couldn't be read
+}
+
+// createHistoryEditInput creates the text input for editing a command before re-running it
+func createHistoryEditInput() textinput.Model {
+	input := textinput.New()
+	input.Placeholder = "Arguments, quoted as in a shell"
+	input.Width = 80
+
+	return input
+}
+
+// selectedHistoryIndex returns the index into history of the highlighted
+// entry, or -1. Filtering hides entries, so list positions don't map to it.
+func (m *Model) selectedHistoryIndex() int {
+	if item, ok := m.historyList.SelectedItem().(historyItem); ok {
+		return item.index
+	}
+	return -1
+}
+
+// handleHistoryKey handles the keys only the history view uses. It reports
+// whether the key was handled; other keys go on to handleKeyPress.
+func (m *Model) handleHistoryKey(msg tea.KeyMsg) (bool, tea.Cmd) {
+	if m.historyEditing {
+		switch msg.String() {
+		case "esc":
+			m.historyEditing = false
+			m.historyEditInput.Blur()
+			m.err = nil
+		case "enter":
+			return true, m.rerunEdited()
+		default:
+			var cmd tea.Cmd
+			m.historyEditInput, cmd = m.historyEditInput.Update(msg)
+			return true, cmd
+		}
+		return true, nil
+	}
+
+	// While a filter is typed every key belongs to it, and Esc clears an applied one
+	if msg.String() != "ctrl+c" && (m.historyList.SettingFilter() || msg.String() == "esc" && m.historyList.FilterState() == list.FilterApplied) {
+		var cmd tea.Cmd
+		m.historyList, cmd = m.historyList.Update(msg)
+		m.updateHistoryDetail(false)
+		return true, cmd
+	}
+
+	idx := m.selectedHistoryIndex()
+	switch msg.String() {
+	case "e":
+		// Edit the arguments, then re-run
+		if idx >= 0 {
+			m.historyEditInput.SetValue(maven.JoinArgs(m.history[idx].Command.Args))
+			m.historyEditInput.CursorEnd()
+			m.historyEditInput.Focus()
+			m.historyEditing = true
+			m.notice = ""
+		}
+		return true, nil
+
+	case "y":
+		if idx >= 0 {
+			line := export.CommandLine(m.history[idx].Command)
+			if err := writeClipboard(line); err != nil {
+				m.err = fmt.Errorf("unable to copy to the clipboard: %w", err)
+			} else {
+				m.err = nil
+				m.notice = "Copied: " + line
+			}
+		}
+		return true, nil
+
+	case "o":
+		if idx >= 0 {
+			m.openHistoryLog(idx)
+		}
+		return true, nil
+
+	case "F":
+		// Resume a failed reactor build from the module that failed
+		if idx >= 0 && m.history[idx].ResumeFrom != "" {
+			return true, m.resume(m.history[idx].Command, m.history[idx].ResumeFrom)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// rerun runs a command from the history again
+func (m *Model) rerun(cmd maven.Command) tea.Cmd {
+	m.resetLog(fmt.Sprintf("Re-executing: %s", cmd.String()), "")
+	m.currentView = ViewLogs
+	m.updateLogViewport()
+	return m.runMavenCommand("Re-run", cmd)
+}
+
+// resume runs a failed reactor build again from the module it failed in,
+// with the same profiles, modules and options
+func (m *Model) resume(cmd maven.Command, module string) tea.Cmd {
+	cmd = cmd.WithResumeFrom(module)
+	m.resetLog(fmt.Sprintf("Resuming from %s: %s", module, cmd.String()), "")
+	m.currentView = ViewLogs
+	m.updateLogViewport()
+	return m.runMavenCommand("Resume", cmd)
+}
+
+// rerunEdited runs the highlighted entry's command with the edited arguments
+func (m *Model) rerunEdited() tea.Cmd {
+	idx := m.selectedHistoryIndex()
+	if idx < 0 {
+		m.historyEditing = false
+		return nil
+	}
+	args, err := maven.SplitArgs(m.historyEditInput.Value())
+	if err != nil {
+		m.err = fmt.Errorf("invalid arguments: %w", err)
+		return nil
+	}
+
+	cmd := m.history[idx].Command
+	cmd.Args = args
+	cmd.PrettyArgs = maven.JoinArgs(args)
+	m.historyEditing = false
+	m.historyEditInput.Blur()
+	m.err = nil
+	return m.rerun(cmd)
+}
+
+// historyLog returns the full log of a history entry: in memory for entries
+// of this session, otherwise loaded from the history store and kept with the
+// entry, so it is read once and closed on exit
+func (m *Model) historyLog(idx int) (*maven.LogStore, error) {
+	entry := &m.history[idx]
+	if entry.Log != nil {
+		return entry.Log, nil
+	}
+	if m.historyStore == nil || entry.LogFile == "" {
+		return nil, errors.New("no log was stored for this command")
+	}
+
+	f, err := os.Open(m.historyStore.LogPath(*entry))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read log: %w", err)
+	}
+	defer f.Close()
+
+	log := maven.NewLogStore(m.logWindow)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		log.Append(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		log.Close()
+		return nil, fmt.Errorf("unable to read log: %w", err)
+	}
+	entry.Log = log
+	return log, nil
+}
+
+// openHistoryLog shows the full log of a history entry in the logs view
+func (m *Model) openHistoryLog(idx int) {
+	log, err := m.historyLog(idx)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.err = nil
+	m.activeJob = 0
+	m.logStore = log
+	m.logFollow = true
+	m.currentView = ViewLogs
+	m.updateLogViewport()
+}
+
+// updateHistoryDetail finds the first errors of the highlighted entry for
+// the detail pane, unless they are already known and force is unset
+func (m *Model) updateHistoryDetail(force bool) {
+	idx := m.selectedHistoryIndex()
+	if !force && idx == m.historyDetail.index {
+		return
+	}
+	m.historyDetail = historyDetail{index: idx}
+	if idx < 0 {
+		return
+	}
+
+	// Collects error lines, reporting whether more are wanted
+	collect := func(line string) bool {
+		if strings.HasPrefix(line, "[ERROR]") && strings.TrimSpace(strings.TrimPrefix(line, "[ERROR]")) != "" {
+			m.historyDetail.errorLines = append(m.historyDetail.errorLines, line)
+		}
+		return len(m.historyDetail.errorLines) < historyErrorLines
+	}
+
+	entry := m.history[idx]
+	if entry.Log != nil {
+		m.historyDetail.err = entry.Log.Scan(collect)
+		return
+	}
+	if m.historyStore == nil || entry.LogFile == "" {
+		return
+	}
+
+	// Scan a stored log without loading it, stopping at the last error needed
+	f, err := os.Open(m.historyStore.LogPath(entry))
+	if err != nil {
+		m.historyDetail.err = fmt.Errorf("unable to read log: %w", err)
+		return
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() && collect(scanner.Text()) {
+	}
+	m.historyDetail.err = scanner.Err()
+}
+
+// historyPaneWidths splits the history view between the list and the detail pane
+func (m Model) historyPaneWidths() (int, int) {
+	listWidth := (m.width - 4) * 3 / 5
+	return listWidth, max(m.width-listWidth-8, 10)
+}
+
+// renderHistoryDetail renders the detail pane for the highlighted entry
+func (m Model) renderHistoryDetail(width int) string {
+	idx := m.historyDetail.index
+	if idx < 0 || idx >= len(m.history) {
+		return "No command selected."
+	}
+	entry := m.history[idx]
+
+	label := lipgloss.NewStyle().Bold(true)
+	var lines []string
+	lines = append(lines, label.Render("Command"))
+	for _, variable := range entry.Command.Env {
+		lines = append(lines, "  "+variable)
+	}
+	lines = append(lines, "  "+entry.Command.Executable)
+	args := entry.Command.Args
+	for i := 0; i < len(args); i++ {
+		// One argument per line, keeping options with their values
+		if pairedOptions[args[i]] && i+1 < len(args) {
+			lines = append(lines, "    "+args[i]+" "+export.Quote(args[i+1]))
+			i++
+			continue
+		}
+		lines = append(lines, "    "+export.Quote(args[i]))
+	}
+
+	lines = append(lines, "",
+		fmt.Sprintf("%s %s", label.Render("Started:"), entry.StartTime.Format("2006-01-02 15:04:05")),
+		fmt.Sprintf("%s %v", label.Render("Duration:"), entry.Duration.Round(time.Millisecond)),
+		fmt.Sprintf("%s %d", label.Render("Exit code:"), entry.ExitCode))
+	if entry.Branch != "" {
+		lines = append(lines, fmt.Sprintf("%s %s", label.Render("Branch:"), entry.Branch))
+	}
+	if entry.Error != "" {
+		lines = append(lines, fmt.Sprintf("%s %s", label.Render("Error:"), entry.Error))
+	}
+	if entry.ResumeFrom != "" {
+		lines = append(lines, fmt.Sprintf("%s %s", label.Render("Resume from:"), entry.ResumeFrom))
+	}
+
+	switch {
+	case m.historyDetail.err != nil:
+		lines = append(lines, "", fmt.Sprintf("(%v)", m.historyDetail.err))
+	case len(m.historyDetail.errorLines) > 0:
+		lines = append(lines, "", label.Render("First errors"))
+		lines = append(lines, m.historyDetail.errorLines...)
+	}
+
+	for i, line := range lines {
+		lines[i] = truncate(line, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderHistoryView renders the command history view with the detail pane
+func (m Model) renderHistoryView() string {
+	header := m.renderHeader()
+
+	listWidth, detailWidth := m.historyPaneWidths()
+	paneHeight := max(m.height-6, 1)
+	border := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("205"))
+	detailBorder := border.BorderForeground(lipgloss.Color("240")).
+		Width(detailWidth).
+		Height(paneHeight).
+		Padding(0, 1)
+
+	panes := lipgloss.JoinHorizontal(lipgloss.Top,
+		border.Width(listWidth).Render(m.historyList.View()),
+		detailBorder.Render(m.renderHistoryDetail(max(detailWidth-2, 1))))
+
+	var footer string
+	switch {
+	case m.historyEditing:
+		footer = "Re-run with: " + m.historyEditInput.View() + "\nEnter: Run | Esc: Cancel"
+	case m.historyList.SettingFilter():
+		footer = "Type to filter by goal, module, profile, branch or status | Enter: Apply | Esc: Cancel"
+	default:
+		footer = "Enter: Re-run | E: Edit & re-run | Y: Copy | O: Open log | /: Filter | Space: Select | X: Export | H: Return to main view"
+		if idx := m.selectedHistoryIndex(); idx >= 0 && m.history[idx].ResumeFrom != "" {
+			footer = "Shift+F: Resume from " + m.history[idx].ResumeFrom + " | " + footer
+		}
+	}
+	if m.notice != "" && !m.historyEditing {
+		footer = m.notice + " | " + footer
+	}
+	if m.err != nil {
+		footer = fmt.Sprintf("✗ %v | %s", m.err, footer)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, panes, footer)
+}
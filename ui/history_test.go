@@ -0,0 +1,174 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AR0106/mvn-tui/history"
+	"github.com/AR0106/mvn-tui/maven"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// historyModel returns a model whose saved history holds a failed core build
+// on a feature branch, which can resume from :core, and a successful web build
+func historyModel(t *testing.T) Model {
+	t.Helper()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	store, err := history.Open(dir, history.Retention{})
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	saved := []struct {
+		args       []string
+		exitCode   int
+		resumeFrom string
+		branch     string
+		log        []string
+	}{
+		{[]string{"-P", "ci", "-pl", "core", "verify"}, 1, ":core", "feature/tax", []string{"[INFO] Building core", "[ERROR] ", "[ERROR] Tax.java:[12,5] cannot find symbol", "[ERROR] Tax.java:[20,1] ';' expected"}},
+		{[]string{"-pl", "web", "package"}, 0, "", "main", []string{"[INFO] BUILD SUCCESS"}},
+	}
+	for i, s := range saved {
+		entry := history.NewEntry(&maven.ExecutionResult{
+			Command:    maven.Command{Executable: "mvn", Args: s.args, PrettyArgs: strings.Join(s.args, " ")},
+			ExitCode:   s.exitCode,
+			Duration:   1500 * time.Millisecond,
+			StartTime:  start.Add(time.Duration(i) * time.Minute),
+			ResumeFrom: s.resumeFrom,
+		}, s.branch)
+		log := maven.NewLogStore(0)
+		log.Append(s.log...)
+		if err := store.Add(entry, log); err != nil {
+			t.Fatalf("Failed to save history: %v", err)
+		}
+	}
+
+	m := NewModel(&maven.Project{RootPath: dir, Executable: "mvn"}).WithHistory(store)
+	t.Cleanup(m.Close)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	return press(updated.(Model), "h")
+}
+
+func TestHistoryView_FiltersAndShowsDetail(t *testing.T) {
+	m := historyModel(t)
+
+	// The newest entry is highlighted first
+	if view := m.View(); !strings.Contains(view, "Branch: main") || !strings.Contains(view, "-pl web") {
+		t.Errorf("Expected the web build's details, got:\n%s", view)
+	}
+
+	// Typed filter keys go to the filter rather than switching views
+	m = press(m, "/", "h")
+	if m.currentView != ViewHistory || !m.historyList.SettingFilter() {
+		t.Fatal("Expected H to be typed into the filter")
+	}
+	m = press(m, "esc")
+
+	m.historyList.SetFilterText("failed core")
+	m.updateHistoryDetail(false)
+	if items := m.historyList.VisibleItems(); len(items) != 1 || items[0].(historyItem).index != 0 {
+		t.Fatalf("Expected only the failed core build, got %v", items)
+	}
+	view := m.View()
+	for _, want := range []string{"-P ci", "Branch: feature/tax", "Exit code: 1", "Duration: 1.5s", "First errors", "[ERROR] Tax.java:[12,5] cannot find symbol"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected %q in the detail pane:\n%s", want, view)
+		}
+	}
+
+	// Enter re-runs the filtered entry, not the one at the same list position
+	_, cmd := m.handleEnter()
+	if cmd == nil || m.currentView != ViewLogs {
+		t.Fatal("Expected the entry to be re-run")
+	}
+	m.jobs.Shutdown()
+	if lines, _ := m.logStore.All(); !strings.Contains(lines[0], "mvn -P ci -pl core verify") {
+		t.Errorf("Expected the core build to be re-run, got %q", lines[0])
+	}
+}
+
+func TestHistoryView_EditCopyAndOpenLog(t *testing.T) {
+	original := writeClipboard
+	t.Cleanup(func() { writeClipboard = original })
+	var copied string
+	writeClipboard = func(text string) error {
+		copied = text
+		return nil
+	}
+
+	m := historyModel(t)
+	m = press(m, "y")
+	if copied != "mvn -pl web package" || !strings.Contains(m.notice, "Copied") {
+		t.Errorf("Expected the command on the clipboard, got %q", copied)
+	}
+	writeClipboard = func(string) error { return errors.New("no clipboard utility") }
+	if m = press(m, "y"); m.err == nil {
+		t.Error("Expected a clipboard failure to be reported")
+	}
+
+	// O shows the stored log
+	m = press(m, "o")
+	if m.currentView != ViewLogs {
+		t.Fatalf("Expected the logs view, got %v (err %v)", m.currentView, m.err)
+	}
+	if lines, _ := m.logStore.All(); len(lines) != 1 || lines[0] != "[INFO] BUILD SUCCESS" {
+		t.Errorf("Expected the stored log, got %q", lines)
+	}
+
+	// E edits the arguments before re-running; quotes keep a property together
+	m = press(m, "l", "h", "e")
+	if !m.historyEditing || m.historyEditInput.Value() != "-pl web package" {
+		t.Fatalf("Expected the arguments to edit, got %q", m.historyEditInput.Value())
+	}
+	m.historyEditInput.SetValue(`-pl web -Dmsg="two words" install`)
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	m.jobs.Shutdown()
+	if cmd == nil || m.historyEditing || m.currentView != ViewLogs {
+		t.Fatalf("Expected the edited command to run, got err %v", m.err)
+	}
+	if job := m.jobs.Get(m.activeJob); job == nil || len(job.Command.Args) != 4 || job.Command.Args[2] != "-Dmsg=two words" {
+		t.Errorf("Expected the edited arguments, got %+v", job)
+	}
+}
+
+func TestResume_RerunsFromTheFailedModule(t *testing.T) {
+	m := historyModel(t)
+
+	// The successful build has nothing to resume
+	if m = press(m, "F"); m.currentView != ViewHistory {
+		t.Fatal("Expected F to do nothing for a successful build")
+	}
+
+	m = press(m, "down")
+	if view := m.View(); !strings.Contains(view, "Resume from: :core") || !strings.Contains(view, "Shift+F: Resume from :core") {
+		t.Errorf("Expected the failed build to offer resuming:\n%s", view)
+	}
+	m = press(m, "F")
+	m.jobs.Shutdown()
+	job := m.jobs.Get(m.activeJob)
+	if m.currentView != ViewLogs || job == nil {
+		t.Fatal("Expected the build to be resumed")
+	}
+	if got := strings.Join(job.Command.Args, " "); got != "-P ci -pl core verify -rf :core" {
+		t.Errorf("Expected the same command resumed from :core, got %q", got)
+	}
+
+	// After a failure in this session, F resumes the last build
+	m.activeJob = 0
+	m.lastResult = &maven.ExecutionResult{
+		Command:    maven.Command{Executable: "mvn", Args: []string{"-q", "install", "-rf", ":core"}},
+		ExitCode:   1,
+		ResumeFrom: ":web",
+	}
+	m = press(m, "F")
+	m.jobs.Shutdown()
+	if job := m.jobs.Get(m.activeJob); job == nil || strings.Join(job.Command.Args, " ") != "-q install -rf :web" {
+		t.Errorf("Expected the last build resumed from :web, got %+v", job)
+	}
+}
@@ -0,0 +1,420 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/AR0106/mvn-tui/maven"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// JobStatus represents the state of a background Maven job
+type JobStatus int
+
+const (
+	JobRunning JobStatus = iota
+	JobSucceeded
+	JobFailed
+	JobCancelled
+)
+
+// String returns a human readable job status
+func (s JobStatus) String() string {
+	switch s {
+	case JobRunning:
+		return "running"
+	case JobSucceeded:
+		return "succeeded"
+	case JobFailed:
+		return "failed"
+	case JobCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// Icon returns the status symbol used in lists and the footer
+func (s JobStatus) Icon() string {
+	switch s {
+	case JobRunning:
+		return "⏳"
+	case JobSucceeded:
+		return "✓"
+	case JobCancelled:
+		return "⊘"
+	default:
+		return "✗"
+	}
+}
+
+// Job is a single Maven command execution tracked by the job manager
+type Job struct {
+	ID         int
+	Name       string
+	Command    maven.Command
+	Log        *maven.LogStore
+	Status     JobStatus
+	StartTime  time.Time
+	Result     *maven.ExecutionResult
+	Progress   *maven.ReactorProgress // Per-module progress parsed from the output, if tracked
+	Terminal   *maven.PTYSession      // Pseudo-terminal of an interactive job, shown in the terminal view
+	cancel     context.CancelFunc
+	cancelling bool
+	done       chan struct{}
+}
+
+// Elapsed returns how long the job has been running, or its final duration
+func (j *Job) Elapsed() time.Duration {
+	if j.Result != nil {
+		return j.Result.Duration
+	}
+	return time.Since(j.StartTime).Round(time.Second)
+}
+
+// track feeds output lines to the job's reactor progress
+func (j *Job) track(lines []string, now time.Time) {
+	if j.Progress == nil {
+		return
+	}
+	for _, line := range lines {
+		j.Progress.Feed(line, now)
+	}
+}
+
+// terminalStream notifies the program of output from an interactive job
+type terminalStream struct {
+	jobID  int
+	output chan struct{}
+	done   chan *maven.ExecutionResult
+}
+
+// notify records that new output is available, without blocking the reader
+func (s *terminalStream) notify() {
+	select {
+	case s.output <- struct{}{}:
+	default:
+	}
+}
+
+// wait returns a command that blocks until there is new output to show or
+// the job has finished. Bursts of output are coalesced into one message.
+func (s *terminalStream) wait() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case <-s.output:
+			return terminalOutputMsg{jobID: s.jobID, stream: s}
+		case result := <-s.done:
+			return executionCompleteMsg{jobID: s.jobID, result: result}
+		}
+	}
+}
+
+// progressTickMsg refreshes elapsed times while jobs are running
+type progressTickMsg struct{}
+
+// progressTick schedules the next progressTickMsg
+func progressTick() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return progressTickMsg{}
+	})
+}
+
+// JobManager tracks Maven commands running concurrently in the background.
+// Each job has its own log, status and cancel handle.
+type JobManager struct {
+	jobs   []*Job
+	nextID int
+}
+
+// NewJobManager creates an empty job manager
+func NewJobManager() *JobManager {
+	return &JobManager{nextID: 1}
+}
+
+// maxOutputBatch caps how many lines are delivered in a single executionOutputMsg
+const maxOutputBatch = 500
+
+// executionStream carries output lines from a running Maven process to the program
+type executionStream struct {
+	jobID int
+	lines chan string
+	done  chan *maven.ExecutionResult
+}
+
+// wait returns a command that blocks until more output is available. Lines
+// that are already queued are batched into one message so that busy builds
+// don't re-render the viewport for every single line. Once the output is
+// drained, the final executionCompleteMsg is delivered.
+func (s *executionStream) wait() tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-s.lines
+		if !ok {
+			return executionCompleteMsg{jobID: s.jobID, result: <-s.done}
+		}
+
+		batch := []string{line}
+		for len(batch) < maxOutputBatch {
+			select {
+			case line, ok := <-s.lines:
+				if !ok {
+					return executionOutputMsg{jobID: s.jobID, lines: batch, stream: s}
+				}
+				batch = append(batch, line)
+			default:
+				return executionOutputMsg{jobID: s.jobID, lines: batch, stream: s}
+			}
+		}
+		return executionOutputMsg{jobID: s.jobID, lines: batch, stream: s}
+	}
+}
+
+// Start launches a Maven command in the background, writing its output into
+// log. It returns the new job and the command that streams its output to the
+// program.
+func (jm *JobManager) Start(ctx context.Context, name string, cmd maven.Command, workDir string, log *maven.LogStore) (*Job, tea.Cmd) {
+	ctx, cancel := context.WithCancel(ctx)
+	job := jm.newJob(name, cmd, log, cancel)
+
+	stream := &executionStream{
+		jobID: job.ID,
+		lines: make(chan string, maxOutputBatch),
+		done:  make(chan *maven.ExecutionResult, 1),
+	}
+
+	go func() {
+		defer close(job.done)
+		defer cancel()
+
+		// Execute the Maven command into the job's log, notifying the program of each line
+		result, err := maven.ExecuteWithOptions(ctx, cmd, workDir, maven.ExecuteOptions{
+			Log: log,
+			OutputHandler: func(line maven.OutputLine) {
+				stream.lines <- line.Text
+			},
+		})
+
+		if err != nil && result.Error == nil {
+			result.Error = err
+		}
+
+		close(stream.lines)
+		stream.done <- result
+	}()
+
+	return job, stream.wait()
+}
+
+// StartTerminal launches an interactive Maven command on a pseudo-terminal of
+// the given size, recording its transcript into log. It returns the new job
+// and the command that notifies the program of output.
+func (jm *JobManager) StartTerminal(ctx context.Context, name string, cmd maven.Command, workDir string, log *maven.LogStore, cols, rows int) (*Job, tea.Cmd, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	stream := &terminalStream{
+		output: make(chan struct{}, 1),
+		done:   make(chan *maven.ExecutionResult, 1),
+	}
+	session, err := maven.StartPTYSession(ctx, cmd, workDir, log, cols, rows, stream.notify)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+
+	job := jm.newJob(name, cmd, log, cancel)
+	job.Terminal = session
+	stream.jobID = job.ID
+
+	go func() {
+		defer close(job.done)
+		defer cancel()
+		stream.done <- session.Wait()
+	}()
+
+	return job, stream.wait(), nil
+}
+
+// newJob registers a running job
+func (jm *JobManager) newJob(name string, cmd maven.Command, log *maven.LogStore, cancel context.CancelFunc) *Job {
+	job := &Job{
+		ID:        jm.nextID,
+		Name:      name,
+		Command:   cmd,
+		Log:       log,
+		Status:    JobRunning,
+		StartTime: time.Now(),
+		cancel:    cancel,
+		done:      make(chan struct{}),
+	}
+	jm.nextID++
+	jm.jobs = append(jm.jobs, job)
+	return job
+}
+
+// Get returns the job with the given ID, or nil if there is none
+func (jm *JobManager) Get(id int) *Job {
+	for _, job := range jm.jobs {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+// Jobs returns all tracked jobs, oldest first
+func (jm *JobManager) Jobs() []*Job {
+	return jm.jobs
+}
+
+// RunningCount returns the number of jobs that are still running
+func (jm *JobManager) RunningCount() int {
+	count := 0
+	for _, job := range jm.jobs {
+		if job.Status == JobRunning {
+			count++
+		}
+	}
+	return count
+}
+
+// IsRunning reports whether the job with the given ID is still running
+func (jm *JobManager) IsRunning(id int) bool {
+	job := jm.Get(id)
+	return job != nil && job.Status == JobRunning
+}
+
+// Cancel requests cancellation of a running job. It returns false if the
+// job does not exist or has already finished.
+func (jm *JobManager) Cancel(id int) bool {
+	job := jm.Get(id)
+	if job == nil || job.Status != JobRunning {
+		return false
+	}
+	job.cancelling = true
+	job.cancel()
+	return true
+}
+
+// Complete records the result of a finished job
+func (jm *JobManager) Complete(id int, result *maven.ExecutionResult) *Job {
+	job := jm.Get(id)
+	if job == nil {
+		return nil
+	}
+
+	job.Result = result
+	switch {
+	case job.cancelling || result.Stopped != maven.NotStopped:
+		job.Status = JobCancelled
+	case result.ExitCode == 0 && result.Error == nil:
+		job.Status = JobSucceeded
+	default:
+		job.Status = JobFailed
+	}
+	if job.Progress != nil {
+		job.Progress.Finish(job.Status == JobSucceeded, time.Now())
+		// A cancelled build didn't fail anywhere in particular
+		if job.Status == JobFailed {
+			result.ResumeFrom = job.Progress.ResumeFrom()
+		}
+	}
+	return job
+}
+
+// ClearFinished drops finished jobs from the manager. Their logs remain
+// referenced by the command history.
+func (jm *JobManager) ClearFinished() {
+	var running []*Job
+	for _, job := range jm.jobs {
+		if job.Status == JobRunning {
+			running = append(running, job)
+		}
+	}
+	jm.jobs = running
+}
+
+// Shutdown cancels every running job and waits for their processes to exit
+func (jm *JobManager) Shutdown() {
+	for _, job := range jm.jobs {
+		if job.Status == JobRunning {
+			job.cancel()
+		}
+	}
+	for _, job := range jm.jobs {
+		<-job.done
+	}
+}
+
+// jobItem represents a job in the jobs list
+type jobItem struct {
+	job *Job
+}
+
+func (i jobItem) Title() string {
+	title := fmt.Sprintf("%s #%d %s", i.job.Status.Icon(), i.job.ID, i.job.Name)
+	if i.job.Terminal != nil {
+		title += " [terminal]"
+	}
+	return title
+}
+
+func (i jobItem) Description() string {
+	status := i.job.Status.String()
+	if i.job.cancelling && i.job.Status == JobRunning {
+		status = "cancelling"
+	}
+	return fmt.Sprintf("%s, %v | %s", status, i.job.Elapsed(), i.job.Command.String())
+}
+
+func (i jobItem) FilterValue() string { return i.job.Name }
+
+// createJobsList creates a list widget for background jobs
+func createJobsList() list.Model {
+	jobsList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	jobsList.Title = "Jobs"
+	jobsList.SetShowStatusBar(false)
+	jobsList.SetFilteringEnabled(false)
+
+	return jobsList
+}
+
+// refreshJobsList updates the jobs list with the current jobs, newest first
+func (m *Model) refreshJobsList() {
+	jobs := m.jobs.Jobs()
+	items := make([]list.Item, len(jobs))
+	for i, job := range jobs {
+		items[len(jobs)-1-i] = jobItem{job: job}
+	}
+	m.jobsList.SetItems(items)
+}
+
+// selectedJob returns the job highlighted in the jobs list
+func (m *Model) selectedJob() *Job {
+	item, ok := m.jobsList.SelectedItem().(jobItem)
+	if !ok {
+		return nil
+	}
+	return item.job
+}
+
+// showJobLog switches the logs view to the given job's output
+func (m *Model) showJobLog(job *Job) {
+	m.activeJob = job.ID
+	m.logStore = job.Log
+	m.logFollow = true
+	m.currentView = ViewLogs
+	m.updateLogViewport()
+}
+
+// cancelActiveJob cancels the job shown in the logs view, if it is running
+func (m *Model) cancelActiveJob() bool {
+	if !m.jobs.Cancel(m.activeJob) {
+		return false
+	}
+	m.logStore.Append("", fmt.Sprintf("Cancelling command: sending SIGINT, then SIGKILL after %v...", maven.DefaultGracePeriod))
+	m.updateLogViewport()
+	m.refreshJobsList()
+	return true
+}
@@ -0,0 +1,282 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AR0106/mvn-tui/maven"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestExecutionStream_DeliversLinesThenCompletion(t *testing.T) {
+	stream := &executionStream{
+		jobID: 7,
+		lines: make(chan string, 10),
+		done:  make(chan *maven.ExecutionResult, 1),
+	}
+
+	stream.lines <- "[INFO] Scanning for projects..."
+	stream.lines <- "[INFO] BUILD SUCCESS"
+	close(stream.lines)
+	result := &maven.ExecutionResult{ExitCode: 0}
+	stream.done <- result
+
+	msg := stream.wait()()
+	outMsg, ok := msg.(executionOutputMsg)
+	if !ok {
+		t.Fatalf("Expected executionOutputMsg, got %T", msg)
+	}
+	if len(outMsg.lines) != 2 {
+		t.Errorf("Expected queued lines to be batched together, got %d lines", len(outMsg.lines))
+	}
+	if outMsg.jobID != 7 {
+		t.Errorf("Expected output to be tagged with job 7, got %d", outMsg.jobID)
+	}
+
+	msg = outMsg.stream.wait()()
+	completeMsg, ok := msg.(executionCompleteMsg)
+	if !ok {
+		t.Fatalf("Expected executionCompleteMsg after output drained, got %T", msg)
+	}
+	if completeMsg.result != result || completeMsg.jobID != 7 {
+		t.Error("Expected completion to carry the job's execution result")
+	}
+}
+
+func TestExecutionStream_BatchIsCapped(t *testing.T) {
+	stream := &executionStream{
+		lines: make(chan string, maxOutputBatch+10),
+		done:  make(chan *maven.ExecutionResult, 1),
+	}
+	for i := 0; i < maxOutputBatch+10; i++ {
+		stream.lines <- "line"
+	}
+
+	outMsg, ok := stream.wait()().(executionOutputMsg)
+	if !ok {
+		t.Fatal("Expected executionOutputMsg")
+	}
+	if len(outMsg.lines) != maxOutputBatch {
+		t.Errorf("Expected batch of %d lines, got %d", maxOutputBatch, len(outMsg.lines))
+	}
+}
+
+// drainJob runs a job's command chain until it completes
+func drainJob(t *testing.T, cmd tea.Cmd) executionCompleteMsg {
+	t.Helper()
+	for {
+		switch msg := cmd().(type) {
+		case executionOutputMsg:
+			cmd = msg.stream.wait()
+		case executionCompleteMsg:
+			return msg
+		default:
+			t.Fatalf("Unexpected message %T", msg)
+		}
+	}
+}
+
+func TestJobManager_RunsJobsConcurrently(t *testing.T) {
+	tmpDir := t.TempDir()
+	fakeMvn := filepath.Join(tmpDir, "fake-mvn")
+	script := "#!/bin/sh\necho \"[INFO] $1\"\nsleep \"$2\"\nexit \"$3\"\n"
+	if err := os.WriteFile(fakeMvn, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake Maven script: %v", err)
+	}
+
+	jm := NewJobManager()
+
+	server, serverCmd := jm.Start(context.Background(), "Run", maven.Command{Executable: fakeMvn, Args: []string{"server", "30", "0"}}, tmpDir, maven.NewLogStore(0))
+	build, buildCmd := jm.Start(context.Background(), "Test", maven.Command{Executable: fakeMvn, Args: []string{"test", "0", "1"}}, tmpDir, maven.NewLogStore(0))
+
+	if server.ID == build.ID {
+		t.Fatal("Expected jobs to get distinct IDs")
+	}
+	if jm.RunningCount() != 2 {
+		t.Fatalf("Expected 2 running jobs, got %d", jm.RunningCount())
+	}
+
+	// The short build finishes while the long-running job keeps going
+	done := drainJob(t, buildCmd)
+	jm.Complete(done.jobID, done.result)
+	if build.Status != JobFailed {
+		t.Errorf("Expected failed build job, got %v", build.Status)
+	}
+	if !jm.IsRunning(server.ID) {
+		t.Error("Expected the long-running job to still be running")
+	}
+
+	// Killing the long-running job cancels only that job
+	start := time.Now()
+	if !jm.Cancel(server.ID) {
+		t.Fatal("Expected Cancel to succeed for a running job")
+	}
+	done = drainJob(t, serverCmd)
+	jm.Complete(done.jobID, done.result)
+	if server.Status != JobCancelled {
+		t.Errorf("Expected cancelled job, got %v", server.Status)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Error("Expected cancellation to stop the job promptly")
+	}
+
+	if jm.Cancel(server.ID) {
+		t.Error("Expected Cancel to fail for a finished job")
+	}
+
+	jm.ClearFinished()
+	if len(jm.Jobs()) != 0 {
+		t.Errorf("Expected finished jobs to be cleared, got %d", len(jm.Jobs()))
+	}
+}
+
+func TestModel_ShowsReactorProgressForActiveJob(t *testing.T) {
+	tmpDir := t.TempDir()
+	fakeMvn := filepath.Join(tmpDir, "fake-mvn")
+	script := `#!/bin/sh
+echo "[INFO] Reactor Build Order:"
+echo "[INFO] "
+echo "[INFO] app-core                                                          [jar]"
+echo "[INFO] app-web                                                           [jar]"
+echo "[INFO] "
+echo "[INFO] Building app-core 1.0 [1/2]"
+echo "[INFO] --- surefire:3.2.2:test (default-test) @ app-core ---"
+exit 1
+`
+	if err := os.WriteFile(fakeMvn, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake Maven script: %v", err)
+	}
+
+	project := &maven.Project{RootPath: tmpDir, Executable: fakeMvn, Modules: []maven.Module{{Name: "app-core"}, {Name: "app-web"}}}
+	m := NewModel(project)
+	defer m.Close()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m = updated.(Model)
+
+	m.currentView = ViewLogs
+	m.ticking = true // Keep the returned command a plain output stream
+	cmd := m.runMavenCommand("Test", maven.Command{Executable: fakeMvn})
+	job := m.jobs.Get(m.activeJob)
+
+	// Deliver the job's output through Update, as the program would
+	sawPanel := false
+	for cmd != nil {
+		msg := cmd()
+		updated, cmd = m.Update(msg)
+		m = updated.(Model)
+		if _, ok := msg.(executionCompleteMsg); ok {
+			cmd = nil
+		}
+		if job.Progress.Current() != nil {
+			view := m.View()
+			sawPanel = sawPanel || strings.Contains(view, "Reactor 0/2") && strings.Contains(view, "↳ surefire:test")
+		}
+	}
+	if !sawPanel {
+		t.Error("Expected the reactor panel to show the running goal")
+	}
+
+	if job.Progress.Modules[0].State != maven.ModuleStateFailed || job.Progress.Modules[1].State != maven.ModuleStateSkipped {
+		t.Errorf("Expected app-core failed and app-web skipped, got %v and %v", job.Progress.Modules[0].State, job.Progress.Modules[1].State)
+	}
+
+	// The panel stays up after the build, until S hides it
+	m.currentView = ViewLogs
+	m.activeJob = job.ID
+	if !strings.Contains(m.View(), "Reactor 2/2") {
+		t.Error("Expected the finished reactor panel")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	m = updated.(Model)
+	if strings.Contains(m.View(), "Reactor 2/2") {
+		t.Error("Expected S to hide the reactor panel")
+	}
+}
+
+func TestModel_RecordsWhereFailedBuildResumes(t *testing.T) {
+	tmpDir := t.TempDir()
+	fakeMvn := filepath.Join(tmpDir, "fake-mvn")
+	script := `#!/bin/sh
+echo "[INFO] Reactor Summary for shop 1.0:"
+echo "[INFO] shop-core .......................................... SUCCESS [  1.200 s]"
+echo "[INFO] shop-web ........................................... FAILURE [  0.800 s]"
+echo "[INFO] BUILD FAILURE"
+echo "[ERROR] After correcting the problems, you can resume the build with the command"
+echo "[ERROR]   mvn <args> -rf :shop-web"
+exit 1
+`
+	if err := os.WriteFile(fakeMvn, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake Maven script: %v", err)
+	}
+
+	project := &maven.Project{RootPath: tmpDir, Executable: fakeMvn}
+	m := NewModel(project)
+	defer m.Close()
+
+	m.ticking = true
+	cmd := m.runMavenCommand("Test", maven.Command{Executable: fakeMvn})
+	for cmd != nil {
+		msg := cmd()
+		updated, next := m.Update(msg)
+		m = updated.(Model)
+		cmd = next
+		if _, ok := msg.(executionCompleteMsg); ok {
+			cmd = nil
+		}
+	}
+
+	if m.lastResult == nil || m.lastResult.ResumeFrom != ":shop-web" {
+		t.Fatalf("Expected to resume from :shop-web, got %+v", m.lastResult)
+	}
+	if lines, _ := m.logStore.All(); lines[len(lines)-1] != "Press Shift+F to resume the build from :shop-web" {
+		t.Errorf("Expected the resume hint at the end of the log, got %q", lines[len(lines)-1])
+	}
+}
+
+func TestModel_ReactorPanelExplainsQuietBuilds(t *testing.T) {
+	tmpDir := t.TempDir()
+	fakeMvn := filepath.Join(tmpDir, "fake-mvn")
+	if err := os.WriteFile(fakeMvn, []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake Maven script: %v", err)
+	}
+
+	project := &maven.Project{RootPath: tmpDir, Executable: fakeMvn, Modules: []maven.Module{{Name: "app-core"}, {Name: "app-web"}}}
+	m := NewModel(project)
+	defer m.Close()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m = updated.(Model)
+
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"-q", "verify"}, true},
+		{[]string{"--quiet", "verify"}, true},
+		{[]string{"verify"}, false},
+	}
+	for _, tt := range tests {
+		m.ticking = true
+		cmd := m.runMavenCommand("Test", maven.Command{Executable: fakeMvn, Args: tt.args})
+		for cmd != nil {
+			msg := cmd()
+			updated, cmd = m.Update(msg)
+			m = updated.(Model)
+			if _, ok := msg.(executionCompleteMsg); ok {
+				cmd = nil
+			}
+		}
+
+		m.currentView = ViewLogs
+		view := m.View()
+		if got := strings.Contains(view, "No progress: quiet mode (-q)"); got != tt.want {
+			t.Errorf("%v: expected the quiet mode note %v, got %v", tt.args, tt.want, got)
+		}
+		if strings.Contains(view, "Reactor 0/2") {
+			t.Errorf("%v: expected no list of pending modules", tt.args)
+		}
+	}
+}
@@ -2,14 +2,20 @@ package ui
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/AR0106/mvn-tui/export"
+	"github.com/AR0106/mvn-tui/history"
 	"github.com/AR0106/mvn-tui/maven"
 )
 
 // moduleItem represents a module in the modules list
 type moduleItem struct {
-	module maven.Module
-	index  int
+	module     maven.Module
+	index      int
+	hasModules bool // The module is an aggregator with modules of its own
+	collapsed  bool // Its modules are left out of the pane
 }
 
 func (i moduleItem) Title() string {
@@ -17,11 +23,23 @@ func (i moduleItem) Title() string {
 	if i.module.Selected {
 		prefix = "[✓]"
 	}
-	return fmt.Sprintf("%s %s", prefix, i.module.Name)
+	marker := ""
+	if i.hasModules && i.collapsed {
+		marker = "▸ "
+	} else if i.hasModules {
+		marker = "▾ "
+	}
+	return fmt.Sprintf("%s%s %s%s", strings.Repeat("  ", i.module.Depth), prefix, marker, i.module.Label())
+}
+
+func (i moduleItem) Description() string {
+	if i.module.ArtifactID == "" {
+		return strings.Repeat("  ", i.module.Depth) + i.module.Path
+	}
+	return fmt.Sprintf("%s%s · %s", strings.Repeat("  ", i.module.Depth), i.module.ArtifactID, i.module.Packaging)
 }
 
-func (i moduleItem) Description() string { return i.module.Path }
-func (i moduleItem) FilterValue() string { return i.module.Name }
+func (i moduleItem) FilterValue() string { return i.module.Name + " " + i.module.ArtifactID }
 
 // taskItem represents a task in the tasks list
 type taskItem struct {
@@ -32,24 +50,43 @@ func (i taskItem) Title() string       { return i.task.Name }
 func (i taskItem) Description() string { return i.task.Description }
 func (i taskItem) FilterValue() string { return i.task.Name }
 
-// historyItem represents a command execution result in the history list
+// historyItem represents a command execution in the history list
 type historyItem struct {
-	result maven.ExecutionResult
+	entry    history.Entry
+	index    int  // Index into the model's history
+	selected bool // Marked for export
 }
 
 func (i historyItem) Title() string {
 	status := "✓"
-	if i.result.ExitCode != 0 {
+	if !i.entry.Succeeded() {
 		status = "✗"
 	}
-	return fmt.Sprintf("%s %s", status, i.result.Command.String())
+	if i.selected {
+		status = "[✓] " + status
+	}
+	return fmt.Sprintf("%s %s", status, i.entry.Command.String())
 }
 
 func (i historyItem) Description() string {
-	return fmt.Sprintf("Duration: %v, Exit code: %d", i.result.Duration, i.result.ExitCode)
+	desc := fmt.Sprintf("%s, Duration: %v, Exit code: %d", i.entry.StartTime.Format("2006-01-02 15:04"), i.entry.Duration.Round(time.Millisecond), i.entry.ExitCode)
+	if i.entry.Branch != "" {
+		desc += ", Branch: " + i.entry.Branch
+	}
+	return desc
 }
 
-func (i historyItem) FilterValue() string { return i.result.Command.String() }
+// FilterValue lets the history be filtered by status, goal, module, profile
+// and branch as well as anything else in the command
+func (i historyItem) FilterValue() string {
+	status := "success"
+	if !i.entry.Succeeded() {
+		status = "failed"
+	}
+	profiles, modules := export.ProfilesAndModules(i.entry.Command)
+	parts := []string{status, strings.Join(export.Goals(i.entry.Command), " "), strings.Join(modules, " "), strings.Join(profiles, " "), i.entry.Branch, i.entry.Command.PrettyArgs}
+	return strings.Join(parts, " ")
+}
 
 // dependencyItem represents a dependency in the dependency manager list
 type dependencyItem struct {
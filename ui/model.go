@@ -2,7 +2,12 @@ package ui
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/AR0106/mvn-tui/config"
+	"github.com/AR0106/mvn-tui/export"
+	"github.com/AR0106/mvn-tui/history"
 	"github.com/AR0106/mvn-tui/maven"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -20,22 +25,44 @@ const (
 	ViewProjectCreation
 	ViewModuleCreation
 	ViewDependencyManager
+	ViewJobs
+	ViewProblems
+	ViewTerminal
+	ViewExport
+	ViewCustomGoal
+	ViewProperties
+	ViewBuildOptions
 )
 
 // Message types for async operations
 type executionOutputMsg struct {
-	line string
+	jobID  int
+	lines  []string
+	stream *executionStream
+}
+
+type terminalOutputMsg struct {
+	jobID  int
+	stream *terminalStream
 }
 
 type executionCompleteMsg struct {
+	jobID  int // Zero for commands that did not run as a background job
 	result *maven.ExecutionResult
 }
 
+// savedMsg reports a background write to the history store: a command, the
+// goal history or the properties. The error says which couldn't be saved.
+type savedMsg struct {
+	err error
+}
+
 // Task represents a Maven task
 type Task struct {
 	Name        string
 	Description string
 	Goals       []string
+	Recipe      *config.Recipe // Set for tasks defined in the project's config file
 }
 
 // Model represents the application state
@@ -43,36 +70,128 @@ type Model struct {
 	project               *maven.Project
 	tasks                 []Task
 	options               maven.BuildOptions
-	history               []maven.ExecutionResult
-	logBuffer             []string
+	history               []history.Entry
+	historyStore          *history.Store // Nil when history isn't persisted
+	historySelected       map[int]bool   // Indexes into history marked for export
+	historyDetail         historyDetail
+	historyEditing        bool // Editing the highlighted command before re-running it
+	historyEditInput      textinput.Model
+	logStore              *maven.LogStore
+	logWindow             int  // Lines of each log kept in memory before spilling to disk
+	logOffset             int  // Index of the first log line shown in the logs view
+	logFollow             bool // Keep the logs view pinned to the newest line
+	showReactor           bool // Show the reactor progress panel beside the logs
 	currentView           ViewMode
 	width                 int
 	height                int
 	modulesList           list.Model
+	collapsedModules      map[string]bool // Names of aggregators whose modules are folded away
 	tasksList             list.Model
 	historyList           list.Model
+	jobsList              list.Model
+	problemsList          list.Model
 	logViewport           viewport.Model
 	customGoalInput       textinput.Model
+	goalHistory           []string         // Lines typed at the custom goal prompt, oldest first
+	goalHistoryPos        int              // Line shown while browsing with ↑/↓; len(goalHistory) when not browsing
+	goalCompletions       []string         // Candidates listed after an ambiguous Tab
+	goalCompletion        int              // Candidate inserted by cycling with Tab, -1 if not cycling
+	properties            []maven.Property // Set in the properties panel, saved per project
+	configProperties      []maven.Property // -D properties of the config files, beneath the panel's
+	propertyCursor        int              // Highlighted row of the properties panel
+	propertyEditing       int              // Row being edited, len(properties) when adding, -1 if not editing
+	propertyInput         textinput.Model
+	propertyCompletions   []string // Declared properties listed after an ambiguous Tab
+	optionCursor          int      // Highlighted row of the build options view
+	optionEditing         bool     // Typing the value of the highlighted option
+	optionInput           textinput.Model
+	exportInput           textinput.Model
+	exportFormat          export.Format
+	exportSteps           []export.Step
+	exportReturn          ViewMode // View to go back to after exporting
+	exportOverwrite       string   // File the user was warned exists; Enter again replaces it
 	projectCreation       *ProjectCreation
 	moduleCreation        *ModuleCreation
 	dependencyManager     *DependencyManager
 	focusedPane           int // 0: modules, 1: tasks, 2: profiles/options
+	profileCursor         int // Index of the highlighted profile in the options pane
 	lastResult            *maven.ExecutionResult
-	running               bool
+	jobs                  *JobManager
+	activeJob             int  // ID of the job shown in the logs view, zero if none
+	creationJob           int  // ID of the job creating a project or module, zero if none
+	ticking               bool // A progressTickMsg is scheduled
+	terminalJob           int  // ID of the job shown in the terminal view
+	terminalFocused       bool // Keys in the terminal view go to the program
 	err                   error
-	startedWithoutProject bool // True if started without a pom.xml
+	notice                string // Result of the last action, shown in the footer
+	startedWithoutProject bool   // True if started without a pom.xml
 	ctx                   context.Context
-	cancelFunc            context.CancelFunc
 	pendingModuleName     string // Module name to add to pom.xml after creation
 	pendingJavaVersion    string // Java version to set in pom.xml after project creation
 }
 
 // NewModel creates a new application model with an existing project
 func NewModel(project *maven.Project) Model {
-	tasks := BuiltInTasks(project)
+	// The built-in defaults never name a JDK, so they can't fail to apply
+	model, _ := NewModelWithConfig(project, config.Merge(config.BuiltIn(false)))
+	return model
+}
+
+// NewModelWithConfig creates a new application model with an existing
+// project, set up from merged config layers: their recipes join the tasks,
+// and their defaults replace the model's. It fails if the configured JDK
+// can't be found.
+func NewModelWithConfig(project *maven.Project, cfg *config.Config) (Model, error) {
+	tasks := ConfiguredTasks(project, cfg)
 	model := initializeModel(project, tasks, false)
 	model.ctx = context.Background()
-	return model
+
+	model.options = maven.BuildOptions{}
+	if err := cfg.Apply(project, &model.options); err != nil {
+		return model, err
+	}
+	model.configProperties = model.options.Properties
+	// The config may pick another JDK or settings files, or add properties
+	// profiles depend on
+	if model.options.Settings != "" || model.options.GlobalSettings != "" {
+		project.LoadSettings(model.options)
+	}
+	project.EvaluateProfiles(maven.NewActivationContext(project, model.options))
+	model.refreshModulesList()
+
+	if cfg.UI.ReactorPanel != nil {
+		model.showReactor = *cfg.UI.ReactorPanel
+	}
+	if cfg.UI.LogLines > 0 {
+		model.logWindow = cfg.UI.LogLines
+		model.logStore.Close()
+		model.logStore = maven.NewLogStore(model.logWindow)
+	}
+	return model, nil
+}
+
+// WithHistory persists the command history in store, starting from the
+// entries saved there in earlier sessions
+func (m Model) WithHistory(store *history.Store) Model {
+	entries, err := store.Load()
+	if err != nil {
+		m.err = err
+	}
+	m.history = append(entries, m.history...)
+	goals, err := store.Goals()
+	if err != nil {
+		m.err = err
+	}
+	m.goalHistory = append(goals, m.goalHistory...)
+	properties, err := store.Properties()
+	if err != nil {
+		m.err = err
+	}
+	m.properties = append(properties, m.properties...)
+	m.applyProperties()
+	m.historyStore = store
+	m.refreshHistoryList()
+	return m
 }
 
 // NewModelWithoutProject creates a new application model without a project (for project creation)
@@ -114,18 +233,70 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.updateSizes()
+		m.resizeTerminals()
 		return m, nil
 
 	case executionOutputMsg:
-		m.logBuffer = append(m.logBuffer, msg.line)
-		m.updateLogViewport()
-		return m, nil
+		if job := m.jobs.Get(msg.jobID); job != nil {
+			job.track(msg.lines, time.Now())
+		}
+		// The executor already stored the lines; just refresh the visible page
+		if msg.jobID == m.activeJob {
+			m.updateLogViewport()
+		}
+		return m, msg.stream.wait()
+
+	case terminalOutputMsg:
+		// The pane reads the session's screen when rendering
+		return m, msg.stream.wait()
 
 	case executionCompleteMsg:
-		m.handleExecutionComplete(msg)
+		saveCmd := m.handleExecutionComplete(msg)
+		return m, saveCmd
+
+	case savedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+		}
 		return m, nil
 
+	case editorFinishedMsg:
+		m.err = msg.err
+		return m, nil
+
+	case progressTickMsg:
+		if m.jobs.RunningCount() == 0 {
+			m.ticking = false
+			return m, nil
+		}
+		if m.currentView == ViewJobs {
+			m.refreshJobsList()
+		}
+		return m, progressTick()
+
 	case tea.KeyMsg:
+		// The terminal pane handles its own keys, forwarding them while focused
+		if m.currentView == ViewTerminal {
+			return m, m.handleTerminalKey(msg)
+		}
+		if m.currentView == ViewExport {
+			return m, m.handleExportKey(msg)
+		}
+		if m.currentView == ViewCustomGoal {
+			return m, m.handleCustomGoalKey(msg)
+		}
+		if m.currentView == ViewProperties {
+			return m, m.handlePropertiesKey(msg)
+		}
+		if m.currentView == ViewBuildOptions {
+			return m, m.handleBuildOptionsKey(msg)
+		}
+		if m.currentView == ViewHistory {
+			if handled, historyCmd := m.handleHistoryKey(msg); handled {
+				return m, historyCmd
+			}
+		}
+
 		// Skip command processing when in text input views
 		// Let the component handle the key first
 		isTextInputView := m.currentView == ViewProjectCreation ||
@@ -143,10 +314,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// For text input views, only handle special keys
 			switch msg.String() {
 			case "ctrl+c":
-				if m.running && m.cancelFunc != nil {
-					m.cancelFunc()
-					m.logBuffer = append(m.logBuffer, "", "Cancelling command...")
-					m.updateLogViewport()
+				if m.cancelActiveJob() {
 					return m, nil
 				}
 				return m, tea.Quit
@@ -170,11 +338,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 
 	case ViewLogs:
-		m.logViewport, cmd = m.logViewport.Update(msg)
-		cmds = append(cmds, cmd)
+		m.scrollLogs(msg)
 
 	case ViewHistory:
 		m.historyList, cmd = m.historyList.Update(msg)
+		m.updateHistoryDetail(false)
+		cmds = append(cmds, cmd)
+
+	case ViewJobs:
+		m.jobsList, cmd = m.jobsList.Update(msg)
+		cmds = append(cmds, cmd)
+
+	case ViewProblems:
+		m.problemsList, cmd = m.problemsList.Update(msg)
 		cmds = append(cmds, cmd)
 
 	case ViewProjectCreation:
@@ -204,19 +380,16 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Cmd) {
 	switch msg.String() {
 	case "ctrl+c":
-		// If a command is running, cancel it instead of quitting
-		if m.running && m.cancelFunc != nil {
-			m.cancelFunc()
-			m.logBuffer = append(m.logBuffer, "", "Cancelling command...")
-			m.updateLogViewport()
+		// If the watched command is running, cancel it instead of quitting
+		if m.cancelActiveJob() {
 			return true, nil
 		}
-		// Otherwise, quit the application
+		// Otherwise, quit the application (background jobs are stopped on exit)
 		return true, tea.Quit
 
 	case "q":
-		// Don't allow quitting while a command is running
-		if m.running {
+		// Don't allow quitting while commands are running
+		if m.jobs.RunningCount() > 0 {
 			return true, nil
 		}
 		return true, tea.Quit
@@ -225,6 +398,26 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Cmd) {
 		m.focusedPane = (m.focusedPane + 1) % 3
 		return true, nil
 
+	case "up", "down":
+		// Move between profiles in the options pane
+		if m.currentView == ViewMain && m.focusedPane == 2 {
+			if msg.String() == "up" {
+				m.profileCursor = max(m.profileCursor-1, 0)
+			} else {
+				m.profileCursor = min(m.profileCursor+1, max(len(m.project.Profiles)-1, 0))
+			}
+			return true, nil
+		}
+		return false, nil
+
+	case "left", "right":
+		// Fold nested modules in the modules pane; elsewhere the lists page
+		if m.currentView == ViewMain && m.focusedPane == 0 {
+			m.setModuleCollapsed(msg.String() == "left")
+			return true, nil
+		}
+		return false, nil
+
 	case "shift+tab":
 		m.focusedPane = (m.focusedPane - 1 + 3) % 3
 		return true, nil
@@ -246,6 +439,89 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Cmd) {
 		}
 		return true, nil
 
+	case "j":
+		// J scrolls in the logs view, so only claim it where it toggles the jobs view
+		if m.currentView == ViewMain {
+			m.refreshJobsList()
+			m.currentView = ViewJobs
+			return true, nil
+		} else if m.currentView == ViewJobs {
+			m.currentView = ViewMain
+			return true, nil
+		}
+		return false, nil
+
+	case "x":
+		switch {
+		case m.currentView == ViewJobs:
+			// Kill the selected job
+			if job := m.selectedJob(); job != nil && m.jobs.Cancel(job.ID) {
+				job.Log.Append("", fmt.Sprintf("Cancelling command: sending SIGINT, then SIGKILL after %v...", maven.DefaultGracePeriod))
+				m.updateLogViewport()
+				m.refreshJobsList()
+			}
+			return true, nil
+		case m.currentView == ViewHistory:
+			m.exportHistory()
+			return true, nil
+		case m.currentView == ViewMain && m.focusedPane == 1:
+			m.exportTask()
+			return true, nil
+		}
+		return false, nil
+
+	case "s":
+		switch m.currentView {
+		case ViewMain:
+			// Edit the -D system properties passed to every command
+			if !m.startedWithoutProject {
+				m.openProperties()
+			}
+			return true, nil
+		case ViewLogs:
+			// Toggle the reactor progress panel
+			m.showReactor = !m.showReactor
+			return true, nil
+		}
+		return false, nil
+
+	case "e":
+		// Show the errors, warnings and failing tests of the current log
+		switch m.currentView {
+		case ViewMain, ViewLogs:
+			m.showProblems()
+			return true, nil
+		case ViewProblems:
+			m.currentView = ViewLogs
+			m.updateLogViewport()
+			return true, nil
+		}
+		return false, nil
+
+	case "c":
+		switch m.currentView {
+		case ViewMain:
+			// Run goals that aren't in the task list
+			if !m.startedWithoutProject {
+				m.openCustomGoal()
+			}
+			return true, nil
+		case ViewJobs:
+			// Clear finished jobs
+			m.jobs.ClearFinished()
+			m.refreshJobsList()
+			return true, nil
+		}
+		return false, nil
+
+	case "o":
+		// Set the options that aren't on the number keys
+		if m.currentView == ViewMain {
+			m.openBuildOptions()
+			return true, nil
+		}
+		return false, nil
+
 	case "p":
 		if m.currentView == ViewMain {
 			pc := NewProjectCreation()
@@ -321,6 +597,27 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Cmd) {
 		m.options.BatchMode = !m.options.BatchMode
 		return true, nil
 
+	case "F":
+		// Resume the last build from the module it failed in
+		switch m.currentView {
+		case ViewMain, ViewLogs:
+			if m.lastResult != nil && m.lastResult.ResumeFrom != "" && !m.jobs.IsRunning(m.activeJob) {
+				return true, m.resume(m.lastResult.Command, m.lastResult.ResumeFrom)
+			}
+			return true, nil
+		}
+		return false, nil
+
+	case "t":
+		// Reopen the most recent interactive program
+		if m.currentView == ViewMain {
+			if job := m.latestTerminalJob(); job != nil {
+				m.openTerminal(job)
+			}
+			return true, nil
+		}
+		return false, nil
+
 	case "r":
 		// Quick run - execute the first run task found
 		if m.currentView == ViewMain {
@@ -358,10 +655,18 @@ func (m *Model) handleKeyPress(msg tea.KeyMsg) (bool, tea.Cmd) {
 
 // handleEscapeKey handles the Escape key press based on context
 func (m Model) handleEscapeKey() (tea.Model, tea.Cmd) {
-	// If viewing logs and a command is running, cancel it
-	if m.currentView == ViewLogs && m.running && m.cancelFunc != nil {
-		m.cancelFunc()
-		m.logBuffer = append(m.logBuffer, "", "Cancelling command...")
+	// If viewing logs and the command is running, cancel it
+	if m.currentView == ViewLogs && m.cancelActiveJob() {
+		return m, nil
+	}
+
+	if m.currentView == ViewJobs {
+		m.currentView = ViewMain
+		return m, nil
+	}
+
+	if m.currentView == ViewProblems {
+		m.currentView = ViewLogs
 		m.updateLogViewport()
 		return m, nil
 	}
@@ -406,6 +711,20 @@ func (m Model) View() string {
 		return m.renderModuleCreationView()
 	case ViewDependencyManager:
 		return m.renderDependencyManagerView()
+	case ViewJobs:
+		return m.renderJobsView()
+	case ViewProblems:
+		return m.renderProblemsView()
+	case ViewTerminal:
+		return m.renderTerminalView()
+	case ViewExport:
+		return m.renderExportView()
+	case ViewCustomGoal:
+		return m.renderCustomGoalView()
+	case ViewProperties:
+		return m.renderPropertiesView()
+	case ViewBuildOptions:
+		return m.renderBuildOptionsView()
 	default:
 		return "Unknown view"
 	}
@@ -1,12 +1,16 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 
+	"github.com/AR0106/mvn-tui/config"
+	"github.com/AR0106/mvn-tui/history"
 	"github.com/AR0106/mvn-tui/maven"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // BuiltInTasks returns the default Maven tasks
@@ -51,25 +55,74 @@ func BuiltInTasks(project *maven.Project) []Task {
 			})
 		}
 
-		// Add Tomcat run for war packaging
-		if project.Packaging == "war" {
-			tasks = append(tasks, Task{
-				Name:        "Run (Tomcat)",
-				Description: "Run WAR on embedded Tomcat",
-				Goals:       []string{"tomcat7:run"},
-			})
+		// Add the tasks of well-known plugins the POM declares
+		for _, suggested := range project.PluginTasks() {
+			if !hasTask(tasks, suggested.Name) {
+				tasks = append(tasks, Task{
+					Name:        suggested.Name,
+					Description: suggested.Description,
+					Goals:       suggested.Goals,
+				})
+			}
 		}
 	}
 
 	return tasks
 }
 
-// createModulesList creates a list widget for modules
-func createModulesList(modules []maven.Module) list.Model {
-	items := make([]list.Item, len(modules))
-	for i, mod := range modules {
-		items[i] = moduleItem{module: mod, index: i}
+// hasTask reports whether tasks include one with the given name
+func hasTask(tasks []Task, name string) bool {
+	for _, task := range tasks {
+		if task.Name == name {
+			return true
+		}
 	}
+	return false
+}
+
+// ConfiguredTasks returns the built-in tasks followed by the recipes of the
+// project's config file
+func ConfiguredTasks(project *maven.Project, cfg *config.Config) []Task {
+	tasks := BuiltInTasks(project)
+	for i := range cfg.Recipes {
+		recipe := &cfg.Recipes[i]
+		description := recipe.Description
+		if description == "" {
+			description = strings.Join(recipe.Goals, " ")
+		}
+		tasks = append(tasks, Task{
+			Name:        recipe.Name,
+			Description: description,
+			Goals:       recipe.Goals,
+			Recipe:      recipe,
+		})
+	}
+	return tasks
+}
+
+// moduleItems returns list items for the modules that aren't hidden, leaving
+// out the modules below hidden or collapsed aggregators
+func moduleItems(project *maven.Project, collapsed map[string]bool) []list.Item {
+	items := make([]list.Item, 0, len(project.Modules))
+	skipBelow := -1 // Depth of the collapsed aggregator being skipped, -1 if none
+	for i, mod := range project.Modules {
+		if skipBelow >= 0 && mod.Depth > skipBelow {
+			continue
+		}
+		skipBelow = -1
+		if mod.Hidden || collapsed[mod.Name] {
+			skipBelow = mod.Depth
+		}
+		if !mod.Hidden {
+			items = append(items, moduleItem{module: mod, index: i, hasModules: project.HasModules(i), collapsed: collapsed[mod.Name]})
+		}
+	}
+	return items
+}
+
+// createModulesList creates a list widget for modules
+func createModulesList(project *maven.Project) list.Model {
+	items := moduleItems(project, nil)
 
 	modulesList := list.New(items, list.NewDefaultDelegate(), 0, 0)
 	modulesList.Title = "Modules"
@@ -99,7 +152,7 @@ func createHistoryList() list.Model {
 	historyList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
 	historyList.Title = "Command History"
 	historyList.SetShowStatusBar(false)
-	historyList.SetFilteringEnabled(false)
+	historyList.SetFilteringEnabled(true)
 
 	return historyList
 }
@@ -115,20 +168,35 @@ func createCustomGoalInput() textinput.Model {
 
 // refreshModulesList updates the modules list with current module state
 func (m *Model) refreshModulesList() {
-	items := make([]list.Item, len(m.project.Modules))
-	for i, mod := range m.project.Modules {
-		items[i] = moduleItem{module: mod, index: i}
+	m.modulesList.SetItems(moduleItems(m.project, m.collapsedModules))
+}
+
+// setModuleCollapsed collapses or expands the highlighted aggregator in the
+// modules pane
+func (m *Model) setModuleCollapsed(collapsed bool) {
+	item, ok := m.modulesList.SelectedItem().(moduleItem)
+	if !ok || !item.hasModules || item.collapsed == collapsed {
+		return
+	}
+	if m.collapsedModules == nil {
+		m.collapsedModules = make(map[string]bool)
 	}
-	m.modulesList.SetItems(items)
+	if collapsed {
+		m.collapsedModules[item.module.Name] = true
+	} else {
+		delete(m.collapsedModules, item.module.Name)
+	}
+	m.refreshModulesList()
 }
 
 // refreshHistoryList updates the history list with current history
 func (m *Model) refreshHistoryList() {
 	items := make([]list.Item, len(m.history))
 	for i := len(m.history) - 1; i >= 0; i-- {
-		items[len(m.history)-1-i] = historyItem{result: m.history[i]}
+		items[len(m.history)-1-i] = historyItem{entry: m.history[i], index: i, selected: m.historySelected[i]}
 	}
 	m.historyList.SetItems(items)
+	m.updateHistoryDetail(true)
 }
 
 // updateSizes updates the sizes of all UI components
@@ -138,9 +206,13 @@ func (m *Model) updateSizes() {
 
 	m.modulesList.SetSize(paneWidth, paneHeight)
 	m.tasksList.SetSize(paneWidth, paneHeight)
-	m.historyList.SetSize(m.width-4, paneHeight)
+	historyWidth, _ := m.historyPaneWidths()
+	m.historyList.SetSize(historyWidth, paneHeight)
+	m.jobsList.SetSize(m.width-4, paneHeight)
+	m.problemsList.SetSize(m.width-4, paneHeight)
 	m.logViewport.Width = m.width - 4
 	m.logViewport.Height = m.height - 6
+	m.updateLogViewport()
 
 	// Update dependency manager list size if it exists
 	if m.dependencyManager != nil {
@@ -148,27 +220,112 @@ func (m *Model) updateSizes() {
 	}
 }
 
-// updateLogViewport updates the log viewport content
+// resetLog starts a fresh log store for a new command or message
+func (m *Model) resetLog(lines ...string) {
+	m.logStore = maven.NewLogStore(m.logWindow)
+	m.logStore.Append(lines...)
+	m.activeJob = 0
+	m.logOffset = 0
+	m.logFollow = true
+	m.updateLogViewport()
+}
+
+// updateLogViewport loads the visible page of the log store into the viewport.
+// Only one screen of lines is read, so paging through huge logs stays cheap.
 func (m *Model) updateLogViewport() {
-	m.logViewport.SetContent(strings.Join(m.logBuffer, "\n"))
+	height := max(m.logViewport.Height, 1)
+	maxOffset := max(m.logStore.Len()-height, 0)
+	if m.logFollow || m.logOffset > maxOffset {
+		m.logOffset = maxOffset
+	}
+	m.logOffset = max(m.logOffset, 0)
+
+	lines, err := m.logStore.Lines(m.logOffset, m.logOffset+height)
+	if err != nil {
+		lines = []string{fmt.Sprintf("(Unable to read log: %v)", err)}
+	}
+	m.logViewport.SetContent(strings.Join(lines, "\n"))
+}
+
+// scrollLogs moves the logs view in response to navigation keys.
+// Scrolling to the end resumes following new output.
+func (m *Model) scrollLogs(msg tea.Msg) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return
+	}
+
+	page := max(m.logViewport.Height, 1)
+	maxOffset := max(m.logStore.Len()-page, 0)
+
+	switch keyMsg.String() {
+	case "up", "k":
+		m.logOffset--
+	case "down", "j":
+		m.logOffset++
+	case "pgup", "b":
+		m.logOffset -= page
+	case "pgdown", "f":
+		m.logOffset += page
+	case "home", "g":
+		m.logOffset = 0
+	case "end", "G":
+		m.logOffset = maxOffset
+	default:
+		return
+	}
+
+	m.logOffset = min(max(m.logOffset, 0), maxOffset)
+	m.logFollow = m.logOffset == maxOffset
+	m.updateLogViewport()
+}
+
+// Close stops any running jobs and releases the on-disk storage of every
+// log held by the model
+func (m Model) Close() {
+	m.jobs.Shutdown()
+	for _, job := range m.jobs.Jobs() {
+		job.Log.Close()
+		if job.Terminal != nil {
+			job.Terminal.Screen.Close()
+		}
+	}
+	for _, entry := range m.history {
+		if entry.Log != nil {
+			entry.Log.Close()
+		}
+	}
+	if m.logStore != nil {
+		m.logStore.Close()
+	}
 }
 
 // initializeModel initializes common model components
 func initializeModel(project *maven.Project, tasks []Task, startedWithoutProject bool) Model {
 	return Model{
-		project: project,
-		tasks:   tasks,
-		options: maven.BuildOptions{
-			Quiet: true, // Enable quiet mode by default for cleaner output
-		},
-		history:               []maven.ExecutionResult{},
-		logBuffer:             []string{},
+		project:               project,
+		tasks:                 tasks,
+		history:               []history.Entry{},
+		logStore:              maven.NewLogStore(maven.DefaultLogWindow),
+		logWindow:             maven.DefaultLogWindow,
+		logFollow:             true,
+		showReactor:           true,
 		currentView:           ViewMain,
-		modulesList:           createModulesList(project.Modules),
+		modulesList:           createModulesList(project),
 		tasksList:             createTasksList(tasks),
 		historyList:           createHistoryList(),
+		jobsList:              createJobsList(),
+		problemsList:          createProblemsList(),
+		jobs:                  NewJobManager(),
 		logViewport:           viewport.New(0, 0),
 		customGoalInput:       createCustomGoalInput(),
+		goalCompletion:        -1,
+		propertyEditing:       -1,
+		propertyInput:         createPropertyInput(),
+		optionInput:           createOptionInput(),
+		exportInput:           createExportInput(),
+		historyEditInput:      createHistoryEditInput(),
+		historyDetail:         historyDetail{index: -1},
 		focusedPane:           1, // Start with tasks focused
 		startedWithoutProject: startedWithoutProject,
 	}
@@ -0,0 +1,251 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AR0106/mvn-tui/config"
+	"github.com/AR0106/mvn-tui/history"
+	"github.com/AR0106/mvn-tui/maven"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLogViewport_FollowsTailUntilScrolledUp(t *testing.T) {
+	m := NewModel(&maven.Project{RootPath: t.TempDir(), Executable: "mvn"})
+	m.logViewport.Height = 10
+	defer m.Close()
+
+	m.resetLog()
+	for i := 0; i < 100; i++ {
+		m.logStore.Append(fmt.Sprintf("line %d", i))
+	}
+	m.updateLogViewport()
+
+	if m.logOffset != 90 {
+		t.Fatalf("Expected view to follow the tail at offset 90, got %d", m.logOffset)
+	}
+
+	// Scrolling up stops following
+	m.scrollLogs(tea.KeyMsg{Type: tea.KeyPgUp})
+	if m.logFollow || m.logOffset != 80 {
+		t.Fatalf("Expected offset 80 without following, got %d (follow=%v)", m.logOffset, m.logFollow)
+	}
+
+	m.logStore.Append("new line")
+	m.updateLogViewport()
+	if m.logOffset != 80 {
+		t.Errorf("Expected offset to stay at 80 while scrolled up, got %d", m.logOffset)
+	}
+
+	// Jumping to the end resumes following
+	m.scrollLogs(tea.KeyMsg{Type: tea.KeyEnd})
+	m.logStore.Append("another line")
+	m.updateLogViewport()
+	if !m.logFollow || m.logOffset != 92 {
+		t.Errorf("Expected to follow the tail at offset 92, got %d (follow=%v)", m.logOffset, m.logFollow)
+	}
+}
+
+func TestLogViewport_PagesThroughSpilledLines(t *testing.T) {
+	m := NewModel(&maven.Project{RootPath: t.TempDir(), Executable: "mvn"})
+	m.logWindow = 5
+	m.logViewport.Height = 3
+	defer m.Close()
+
+	m.resetLog()
+	for i := 0; i < 50; i++ {
+		m.logStore.Append(fmt.Sprintf("line %d", i))
+	}
+
+	m.scrollLogs(tea.KeyMsg{Type: tea.KeyHome})
+	if got := m.logViewport.View(); got[:6] != "line 0" {
+		t.Errorf("Expected first page to start with spilled line 0, got %q", got)
+	}
+}
+
+func TestNewModelWithConfig_AppliesLayers(t *testing.T) {
+	project := &maven.Project{
+		RootPath:   t.TempDir(),
+		Executable: "mvn",
+		Modules:    []maven.Module{{Name: "core", Selected: true}, {Name: "docs", Selected: true}},
+	}
+	hide := false
+	user := &config.Config{
+		Defaults: config.Defaults{Options: config.Options{Threads: "1C"}},
+		UI:       config.UI{ReactorPanel: &hide, LogLines: 50},
+		Recipes:  []config.Recipe{{Name: "Quick", Goals: []string{"install"}}},
+	}
+	projectConfig := &config.Config{Modules: config.Modules{Hidden: []string{"docs"}}}
+
+	cfg := config.Merge(config.BuiltIn(false),
+		config.Layer{Source: config.SourceUser, Config: user},
+		config.Layer{Source: config.SourceProject, Config: projectConfig})
+	m, err := NewModelWithConfig(project, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	defer m.Close()
+
+	if m.options.Quiet || m.options.Threads != "1C" {
+		t.Errorf("Expected Maven's normal output and the user's threads, got %+v", m.options)
+	}
+	if m.showReactor || m.logWindow != 50 {
+		t.Errorf("Expected the user's UI preferences, got showReactor=%v logWindow=%d", m.showReactor, m.logWindow)
+	}
+	if last := m.tasks[len(m.tasks)-1]; last.Recipe == nil || last.Name != "Quick" {
+		t.Errorf("Expected the user's recipe after the built-in tasks, got %+v", last)
+	}
+	if items := m.modulesList.Items(); len(items) != 1 || items[0].(moduleItem).module.Name != "core" {
+		t.Errorf("Expected only core in the modules pane, got %v", items)
+	}
+}
+
+// runToCompletion delivers a command's messages through Update until it
+// completes, then saves it to the history
+func runToCompletion(t *testing.T, m Model, cmd tea.Cmd) Model {
+	t.Helper()
+	for cmd != nil {
+		msg := cmd()
+		updated, next := m.Update(msg)
+		m = updated.(Model)
+		cmd = next
+		if _, ok := msg.(executionCompleteMsg); ok {
+			if cmd != nil {
+				updated, _ = m.Update(cmd())
+				m = updated.(Model)
+			}
+			return m
+		}
+	}
+	t.Fatal("Expected the command to complete")
+	return m
+}
+
+func TestModel_HistoryPersistsAcrossSessions(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	fakeMvn := filepath.Join(dir, "fake-mvn")
+	if err := os.WriteFile(fakeMvn, []byte("#!/bin/sh\necho \"[INFO] $*\"\nexit 2\n"), 0755); err != nil {
+		t.Fatalf("Failed to write fake Maven script: %v", err)
+	}
+	project := &maven.Project{RootPath: dir, Executable: fakeMvn}
+	command := maven.BuildCommand(project, []string{"verify"}, maven.BuildOptions{SkipTests: true})
+
+	store, err := history.Open(dir, history.Retention{})
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	first := NewModel(project).WithHistory(store)
+	defer first.Close()
+	first.ticking = true
+	first = runToCompletion(t, first, first.runMavenCommand("Verify", command))
+	if first.err != nil {
+		t.Fatalf("Expected the history to be saved, got %v", first.err)
+	}
+
+	// A new session starts from the saved history and re-runs the same command
+	store, _ = history.Open(dir, history.Retention{})
+	second := NewModel(project).WithHistory(store)
+	defer second.Close()
+	if len(second.history) != 1 {
+		t.Fatalf("Expected 1 entry from the previous session, got %d", len(second.history))
+	}
+	entry := second.history[0]
+	if entry.Command.String() != command.String() || entry.ExitCode != 2 || entry.Log != nil {
+		t.Errorf("Expected the previous run without its in-memory log, got %+v", entry)
+	}
+	if log, err := os.ReadFile(store.LogPath(entry)); err != nil || !strings.Contains(string(log), "[INFO] -DskipTests verify") {
+		t.Errorf("Expected the run's output in its stored log, got %q (%v)", log, err)
+	}
+
+	second.currentView = ViewHistory
+	second.ticking = true
+	_, cmd := second.handleEnter()
+	second = runToCompletion(t, second, cmd)
+	if len(second.history) != 2 || second.history[1].Command.String() != command.String() {
+		t.Errorf("Expected the re-run to repeat the saved command, got %+v", second.history)
+	}
+	if entries, _ := store.Load(); len(entries) != 2 {
+		t.Errorf("Expected both runs saved, got %d", len(entries))
+	}
+}
+
+func TestBuiltInTasks_SuggestsPluginTasks(t *testing.T) {
+	project := &maven.Project{
+		Packaging:     "war",
+		HasSpringBoot: true,
+		Plugins: []maven.Plugin{
+			{GroupID: "org.springframework.boot", ArtifactID: "spring-boot-maven-plugin"},
+			{GroupID: "com.diffplug.spotless", ArtifactID: "spotless-maven-plugin"},
+		},
+	}
+
+	var names []string
+	for _, task := range BuiltInTasks(project) {
+		names = append(names, task.Name)
+	}
+	got := strings.Join(names, ", ")
+	if want := "Clean Install, Run (Spring Boot), Spotless Apply, Spotless Check"; !strings.HasSuffix(got, want) {
+		t.Errorf("Expected tasks ending %q, got %q", want, got)
+	}
+	if strings.Contains(got, "Tomcat") {
+		t.Errorf("Expected no Tomcat task without the Tomcat plugin, got %q", got)
+	}
+}
+
+func TestModulesPane_FoldsNestedModules(t *testing.T) {
+	project := &maven.Project{RootPath: t.TempDir(), Executable: "mvn", Modules: []maven.Module{
+		{Name: "core", ArtifactID: "core", Selected: true},
+		{Name: "services", ArtifactID: "services", Packaging: "pom", Selected: true},
+		{Name: "services/orders", ArtifactID: "orders", Packaging: "jar", Parent: "services", Depth: 1, Selected: true},
+		{Name: "services/billing", ArtifactID: "billing", Packaging: "jar", Parent: "services", Depth: 1, Selected: true},
+	}}
+	m := NewModel(project)
+	defer m.Close()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = press(updated.(Model), "tab", "tab", "down")
+
+	if item := m.modulesList.Items()[2].(moduleItem); item.Title() != "  [✓] orders" {
+		t.Errorf("Expected the nested module indented under its aggregator, got %q", item.Title())
+	}
+
+	// Space on the aggregator deselects its modules; left folds them away
+	m = press(m, "space", "left")
+	if items := m.modulesList.Items(); len(items) != 2 || items[1].(moduleItem).Title() != "[ ] ▸ services" {
+		t.Fatalf("Expected the services modules folded away, got %d items", len(items))
+	}
+	if cmd := maven.BuildCommand(m.project, nil, maven.BuildOptions{}); strings.Join(cmd.Args, " ") != "-pl :core" {
+		t.Errorf("Expected only core to be built, got %v", cmd.Args)
+	}
+
+	m = press(m, "enter")
+	if len(m.modulesList.Items()) != 4 {
+		t.Error("Expected Enter to unfold the aggregator")
+	}
+}
+
+func TestOptionsPane_TogglesProfileUnderCursor(t *testing.T) {
+	project := &maven.Project{RootPath: t.TempDir(), Executable: "mvn", Profiles: []maven.Profile{
+		{ID: "local", Activation: &maven.Activation{ActiveByDefault: true}},
+		{ID: "ci"},
+	}}
+	m := NewModel(project)
+	defer m.Close()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = press(updated.(Model), "tab", "down", "space")
+
+	if view := m.View(); !strings.Contains(view, "[A] 1. local") || !strings.Contains(view, "(activeByDefault)") {
+		t.Errorf("Expected local marked active by default:\n%s", view)
+	}
+	if cmd := maven.BuildCommand(m.project, nil, maven.BuildOptions{}); strings.Join(cmd.Args, " ") != "-P local,ci" {
+		t.Errorf("Expected ci enabled beside local, got %v", cmd.Args)
+	}
+
+	m = press(m, "up", "space")
+	if cmd := maven.BuildCommand(m.project, nil, maven.BuildOptions{}); strings.Join(cmd.Args, " ") != "-P !local,ci" {
+		t.Errorf("Expected local disabled, got %v", cmd.Args)
+	}
+}
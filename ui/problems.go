@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/AR0106/mvn-tui/maven"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorFinishedMsg is sent when the editor opened from the problems view exits
+type editorFinishedMsg struct {
+	err error
+}
+
+// problemItem represents a build problem in the problems list
+type problemItem struct {
+	problem maven.Problem
+}
+
+func (i problemItem) Title() string {
+	icon := "✗"
+	switch i.problem.Kind {
+	case maven.ProblemWarning:
+		icon = "⚠"
+	case maven.ProblemTestFailure:
+		icon = "✗ test"
+	}
+
+	location := i.problem.Location()
+	if i.problem.Module != "" {
+		location = i.problem.Module + " › " + location
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s", icon, location))
+}
+
+func (i problemItem) Description() string {
+	if i.problem.Count > 1 {
+		return fmt.Sprintf("%s (reported %d times)", i.problem.Message, i.problem.Count)
+	}
+	return i.problem.Message
+}
+
+func (i problemItem) FilterValue() string { return i.problem.Message }
+
+// createProblemsList creates a list widget for build problems
+func createProblemsList() list.Model {
+	problemsList := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	problemsList.Title = "Problems"
+	problemsList.SetShowStatusBar(false)
+	problemsList.SetFilteringEnabled(false)
+
+	return problemsList
+}
+
+// showProblems lists the problems found in the log shown in the logs view
+func (m *Model) showProblems() {
+	m.err = nil
+	problems, err := maven.CollectProblems(m.logStore)
+	if err != nil {
+		m.err = fmt.Errorf("unable to read log: %w", err)
+	}
+
+	items := make([]list.Item, len(problems))
+	for i, problem := range problems {
+		items[i] = problemItem{problem: problem}
+	}
+	m.problemsList.SetItems(items)
+	m.problemsList.Select(0)
+	m.currentView = ViewProblems
+}
+
+// selectedProblem returns the problem highlighted in the problems list
+func (m *Model) selectedProblem() *maven.Problem {
+	item, ok := m.problemsList.SelectedItem().(problemItem)
+	if !ok {
+		return nil
+	}
+	return &item.problem
+}
+
+// openProblem suspends the TUI and opens the problem's source in $EDITOR
+func (m *Model) openProblem(problem maven.Problem) tea.Cmd {
+	file := problem.File
+	if file == "" && problem.Class != "" {
+		file = maven.FindTestSource(m.moduleDir(problem.Module), problem.Class)
+	}
+	if file == "" {
+		m.err = fmt.Errorf("no source file for %s", problem.Location())
+		return nil
+	}
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(m.project.RootPath, file)
+	}
+
+	args := editorArgs(configuredEditor(), file, problem.Line, problem.Column)
+	if len(args) == 0 {
+		m.err = fmt.Errorf("no editor configured")
+		return nil
+	}
+	c := exec.Command(args[0], args[1:]...)
+	c.Dir = m.project.RootPath
+
+	m.err = nil
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return editorFinishedMsg{err: err}
+	})
+}
+
+// moduleDir returns the directory of the module with the given artifactId,
+// falling back to the project root for single-module builds
+func (m *Model) moduleDir(artifactID string) string {
+	for _, module := range m.project.Modules {
+		if module.ArtifactID == artifactID || module.Name == artifactID || filepath.Base(module.Path) == artifactID {
+			return module.Path
+		}
+	}
+	return m.project.RootPath
+}
+
+// configuredEditor returns $EDITOR, or $VISUAL if it is unset or blank, or vi
+func configuredEditor() string {
+	for _, variable := range []string{"EDITOR", "VISUAL"} {
+		if editor := strings.TrimSpace(os.Getenv(variable)); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+// editorArgs builds the command line that opens file at line and column in
+// editor, using the position syntax of well-known editors. Editors that are
+// not recognised get the +line argument most terminal editors understand.
+// A blank editor gives no command line.
+func editorArgs(editor, file string, line, column int) []string {
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		return nil
+	}
+	if line <= 0 {
+		return append(args, file)
+	}
+
+	l, c := strconv.Itoa(line), strconv.Itoa(max(column, 1))
+	switch filepath.Base(args[0]) {
+	case "vi", "vim", "nvim", "gvim", "mvim":
+		if column > 0 {
+			return append(args, fmt.Sprintf("+call cursor(%s, %s)", l, c), file)
+		}
+		return append(args, "+"+l, file)
+	case "nano":
+		return append(args, "+"+l+","+c, file)
+	case "emacs", "emacsclient", "micro", "kak":
+		return append(args, "+"+l+":"+c, file)
+	case "hx", "helix", "subl", "zed":
+		return append(args, file+":"+l+":"+c)
+	case "code", "code-insiders", "codium", "cursor":
+		return append(args, "--goto", file+":"+l+":"+c)
+	case "idea", "idea.sh":
+		return append(args, "--line", l, "--column", c, file)
+	default:
+		return append(args, "+"+l, file)
+	}
+}
@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/AR0106/mvn-tui/maven"
+)
+
+func TestEditorArgs(t *testing.T) {
+	tests := []struct {
+		editor string
+		line   int
+		column int
+		want   []string
+	}{
+		{"vim", 42, 17, []string{"vim", "+call cursor(42, 17)", "/src/Foo.java"}},
+		{"/usr/bin/nvim", 42, 0, []string{"/usr/bin/nvim", "+42", "/src/Foo.java"}},
+		{"nano", 42, 17, []string{"nano", "+42,17", "/src/Foo.java"}},
+		{"emacsclient -t", 42, 17, []string{"emacsclient", "-t", "+42:17", "/src/Foo.java"}},
+		{"code -w", 42, 17, []string{"code", "-w", "--goto", "/src/Foo.java:42:17"}},
+		{"hx", 42, 0, []string{"hx", "/src/Foo.java:42:1"}},
+		{"ed", 42, 17, []string{"ed", "+42", "/src/Foo.java"}},
+		{"vim", 0, 0, []string{"vim", "/src/Foo.java"}},
+		{" ", 42, 17, nil},
+	}
+
+	for _, tt := range tests {
+		got := editorArgs(tt.editor, "/src/Foo.java", tt.line, tt.column)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("editorArgs(%q, %d, %d) = %q, want %q", tt.editor, tt.line, tt.column, got, tt.want)
+		}
+	}
+}
+
+func TestConfiguredEditor_SkipsBlankVariables(t *testing.T) {
+	t.Setenv("EDITOR", " ")
+	t.Setenv("VISUAL", "nano")
+	if got := configuredEditor(); got != "nano" {
+		t.Errorf("Expected a blank $EDITOR to fall back to $VISUAL, got %q", got)
+	}
+
+	t.Setenv("VISUAL", "\t")
+	if got := configuredEditor(); got != "vi" {
+		t.Errorf("Expected blank variables to fall back to vi, got %q", got)
+	}
+}
+
+func TestShowProblems_ListsLogProblems(t *testing.T) {
+	m := NewModel(&maven.Project{RootPath: t.TempDir(), Executable: "mvn"})
+	defer m.Close()
+
+	m.resetLog(
+		"[INFO] Building app 1.0",
+		"[ERROR] /work/app/src/main/java/App.java:[3,5] ';' expected",
+		"[ERROR] Failed to execute goal compile on project app: Compilation failure",
+		"[ERROR] /work/app/src/main/java/App.java:[3,5] ';' expected",
+		"[ERROR] To see the full stack trace of the errors, re-run Maven with the -e switch.",
+	)
+	m.showProblems()
+
+	if m.currentView != ViewProblems {
+		t.Fatalf("Expected the problems view, got %v", m.currentView)
+	}
+	items := m.problemsList.Items()
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 problems, got %d", len(items))
+	}
+	first := items[0].(problemItem)
+	if first.Title() != "✗ app › App.java:3:5" || first.problem.Count != 2 {
+		t.Errorf("Unexpected first problem %q (count %d)", first.Title(), first.problem.Count)
+	}
+}
+
+func TestOpenProblem_ResolvesTestSource(t *testing.T) {
+	root := t.TempDir()
+	source := filepath.Join(root, "core", "src", "test", "java", "InvoiceTest.java")
+	if err := os.MkdirAll(filepath.Dir(source), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(source, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	project := &maven.Project{RootPath: root, Executable: "mvn", Modules: []maven.Module{{Name: "core", Path: filepath.Join(root, "core")}}}
+	m := NewModel(project)
+	defer m.Close()
+
+	if dir := m.moduleDir("core"); dir != filepath.Join(root, "core") {
+		t.Errorf("Expected the core module directory, got %q", dir)
+	}
+
+	if cmd := m.openProblem(maven.Problem{Kind: maven.ProblemTestFailure, Module: "core", Class: "InvoiceTest", Test: "InvoiceTest.rounding", Line: 42}); cmd == nil || m.err != nil {
+		t.Errorf("Expected an editor command for the test source, got err %v", m.err)
+	}
+
+	if cmd := m.openProblem(maven.Problem{Kind: maven.ProblemTestFailure, Module: "core", Class: "MissingTest", Test: "MissingTest.x"}); cmd != nil || m.err == nil {
+		t.Error("Expected an error for a test without source")
+	}
+}
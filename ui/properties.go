@@ -0,0 +1,257 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/AR0106/mvn-tui/maven"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// createPropertyInput creates the text input for adding and editing properties
+func createPropertyInput() textinput.Model {
+	propertyInput := textinput.New()
+	propertyInput.Placeholder = "name=value (e.g., test=MyTest)"
+	propertyInput.Width = 50
+
+	return propertyInput
+}
+
+// openProperties shows the properties panel
+func (m *Model) openProperties() {
+	m.propertyCursor = min(m.propertyCursor, max(len(m.properties)-1, 0))
+	m.propertyEditing = -1
+	m.propertyCompletions = nil
+	m.err = nil
+	m.notice = ""
+	m.currentView = ViewProperties
+}
+
+// handlePropertiesKey handles keys in the properties panel
+func (m *Model) handlePropertiesKey(msg tea.KeyMsg) tea.Cmd {
+	if m.propertyEditing >= 0 {
+		return m.handlePropertyInputKey(msg)
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "esc", "s":
+		m.err = nil
+		m.currentView = ViewMain
+
+	case "up", "k":
+		m.propertyCursor = max(m.propertyCursor-1, 0)
+
+	case "down", "j":
+		m.propertyCursor = min(m.propertyCursor+1, max(len(m.properties)-1, 0))
+
+	case " ":
+		if m.propertyCursor < len(m.properties) {
+			m.properties[m.propertyCursor].Enabled = !m.properties[m.propertyCursor].Enabled
+			return m.applyProperties()
+		}
+
+	case "a":
+		m.editProperty(len(m.properties))
+
+	case "enter", "e":
+		if m.propertyCursor < len(m.properties) {
+			m.editProperty(m.propertyCursor)
+		}
+
+	case "d", "delete":
+		if m.propertyCursor < len(m.properties) {
+			m.properties = slices.Delete(m.properties, m.propertyCursor, m.propertyCursor+1)
+			m.propertyCursor = min(m.propertyCursor, max(len(m.properties)-1, 0))
+			return m.applyProperties()
+		}
+	}
+	return nil
+}
+
+// editProperty starts editing the property at index, or adding one if
+// index is past the end
+func (m *Model) editProperty(index int) {
+	value := ""
+	if index < len(m.properties) {
+		value = m.properties[index].String()
+	}
+	m.propertyEditing = index
+	m.propertyInput.SetValue(value)
+	m.propertyInput.CursorEnd()
+	m.propertyInput.Focus()
+	m.err = nil
+}
+
+// handlePropertyInputKey handles keys while a property is being edited
+func (m *Model) handlePropertyInputKey(msg tea.KeyMsg) tea.Cmd {
+	if msg.String() != "tab" {
+		m.propertyCompletions = nil
+	}
+
+	switch msg.String() {
+	case "ctrl+c", "esc":
+		m.propertyEditing = -1
+		m.propertyInput.Blur()
+		m.err = nil
+		return nil
+
+	case "tab":
+		m.completeProperty()
+		return nil
+
+	case "enter":
+		return m.commitProperty()
+	}
+
+	var cmd tea.Cmd
+	m.propertyInput, cmd = m.propertyInput.Update(msg)
+	return cmd
+}
+
+// completeProperty completes the property name before the cursor from the
+// properties declared in the project's POMs. An ambiguous name is completed
+// as far as the candidates agree and they are listed.
+func (m *Model) completeProperty() {
+	value := []rune(m.propertyInput.Value())
+	pos := min(m.propertyInput.Position(), len(value))
+	name := string(value[:pos])
+	if strings.Contains(name, "=") {
+		return
+	}
+
+	// Candidates are matched by prefix, the same way goals are
+	matches := maven.CompleteGoal(name, m.project.DeclaredProperties())
+	switch len(matches) {
+	case 0:
+		return
+	case 1:
+		m.propertyInput.SetValue(matches[0] + "=" + string(value[pos:]))
+		m.propertyInput.SetCursor(len([]rune(matches[0])) + 1)
+		return
+	}
+
+	m.propertyCompletions = matches
+	prefix := commonPrefix(matches)
+	m.propertyInput.SetValue(prefix + string(value[pos:]))
+	m.propertyInput.SetCursor(len([]rune(prefix)))
+}
+
+// commitProperty saves the property being edited. A new property replaces
+// one of the same name; an edited one keeps whether it was enabled.
+func (m *Model) commitProperty() tea.Cmd {
+	prop, err := maven.ParseProperty(m.propertyInput.Value())
+	if err != nil {
+		m.err = fmt.Errorf("invalid property: %w", err)
+		return nil
+	}
+
+	index := m.propertyEditing
+	if index < len(m.properties) {
+		prop.Enabled = m.properties[index].Enabled
+	} else if existing := slices.IndexFunc(m.properties, func(p maven.Property) bool { return p.Name == prop.Name }); existing >= 0 {
+		index = existing
+	}
+	if index < len(m.properties) {
+		m.properties[index] = prop
+	} else {
+		m.properties = append(m.properties, prop)
+	}
+
+	m.propertyCursor = index
+	m.propertyEditing = -1
+	m.propertyInput.Blur()
+	m.err = nil
+	return m.applyProperties()
+}
+
+// applyProperties passes the enabled properties to every command on top of
+// the configured ones, works out again which profiles they activate and
+// saves them for the next session
+func (m *Model) applyProperties() tea.Cmd {
+	m.options.Properties = maven.MergeProperties(m.configProperties, m.properties...)
+	m.project.EvaluateProfiles(maven.NewActivationContext(m.project, m.options))
+
+	store := m.historyStore
+	if store == nil {
+		return nil
+	}
+	saved := slices.Clone(m.properties)
+	return func() tea.Msg {
+		return savedMsg{err: store.SaveProperties(saved)}
+	}
+}
+
+// renderPropertiesView renders the properties panel
+func (m Model) renderPropertiesView() string {
+	header := m.renderHeader()
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	var sb strings.Builder
+	sb.WriteString(lipgloss.NewStyle().Bold(true).Render("System Properties (-D)"))
+	sb.WriteString("\n\n")
+
+	if len(m.properties) == 0 && m.propertyEditing < 0 {
+		sb.WriteString("  (none, press A to add one)\n")
+	}
+	for i, prop := range m.properties {
+		cursor := "  "
+		if i == m.propertyCursor {
+			cursor = "› "
+		}
+		if i == m.propertyEditing {
+			sb.WriteString(cursor + m.propertyInput.View() + "\n")
+			continue
+		}
+		checkbox := "[ ]"
+		if prop.Enabled {
+			checkbox = "[✓]"
+		}
+		sb.WriteString(fmt.Sprintf("%s%s %s\n", cursor, checkbox, prop))
+	}
+	if m.propertyEditing == len(m.properties) {
+		sb.WriteString("› " + m.propertyInput.View() + "\n")
+	}
+
+	if len(m.propertyCompletions) > 0 {
+		sb.WriteString("\n")
+		for i, candidate := range m.propertyCompletions {
+			if i == goalCompletionsShown {
+				sb.WriteString(fmt.Sprintf("  … %d more\n", len(m.propertyCompletions)-i))
+				break
+			}
+			sb.WriteString("  " + candidate + "\n")
+		}
+	}
+
+	// Properties of the config files apply unless the panel sets them too
+	var configured []string
+	enabled := maven.EnabledProperties(m.properties)
+	for _, prop := range m.configProperties {
+		if !slices.ContainsFunc(enabled, func(p maven.Property) bool { return p.Name == prop.Name }) {
+			configured = append(configured, prop.String())
+		}
+	}
+	if len(configured) > 0 {
+		sb.WriteString("\n" + dim.Render("From config: -D"+strings.Join(configured, " -D")) + "\n")
+	}
+
+	sb.WriteString("\n" + maven.BuildCommand(m.project, nil, m.options).String())
+
+	border := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("205")).
+		Padding(0, 1)
+
+	footer := "A: Add | Enter: Edit | Space: Toggle | D: Remove | Esc: Back"
+	if m.propertyEditing >= 0 {
+		footer = "Enter: Save | Tab: Complete name | Esc: Cancel"
+	}
+	if m.err != nil {
+		footer = fmt.Sprintf("✗ %v | %s", m.err, footer)
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, border.Render(sb.String()), footer)
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AR0106/mvn-tui/history"
+	"github.com/AR0106/mvn-tui/maven"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPropertiesPanel_FeedsBuildCommand(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	project := &maven.Project{RootPath: dir, Executable: "mvn", Model: &maven.POM{
+		Properties: maven.Properties{"skipITs": "false", "skipUTs": "false", "java.version": "21"},
+	}}
+	store, err := history.Open(dir, history.Retention{})
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	m := NewModel(project).WithHistory(store)
+	defer m.Close()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+
+	// An ambiguous name completes as far as the candidates agree
+	m = press(m, "s", "a", "sk", "tab")
+	if m.propertyInput.Value() != "skip" || !reflect.DeepEqual(m.propertyCompletions, []string{"skipITs", "skipUTs"}) {
+		t.Fatalf("Expected skip with two candidates, got %q %q", m.propertyInput.Value(), m.propertyCompletions)
+	}
+	m = press(m, "I", "tab", "true", "enter", "a", "msg=it's done", "enter")
+	if m.err != nil || m.currentView != ViewProperties {
+		t.Fatalf("Expected both properties added, got %v", m.err)
+	}
+
+	cmd := maven.BuildCommand(m.project, []string{"verify"}, m.options)
+	if want := []string{"-Dmsg=it's done", "-DskipITs=true", "verify"}; !reflect.DeepEqual(cmd.Args, want) {
+		t.Errorf("Args = %q, want %q", cmd.Args, want)
+	}
+	if !strings.Contains(cmd.String(), `'-Dmsg=it'\''s done'`) {
+		t.Errorf("Expected the spaced value quoted, got %s", cmd.String())
+	}
+
+	// Space disables the highlighted property, which then persists
+	updated, saveCmd := m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	m = updated.(Model)
+	if msg := saveCmd().(savedMsg); msg.err != nil {
+		t.Fatalf("Failed to save properties: %v", msg.err)
+	}
+	if slices.ContainsFunc(m.options.Properties, func(p maven.Property) bool { return p.Name == "msg" }) {
+		t.Errorf("Expected msg to be disabled, got %v", m.options.Properties)
+	}
+
+	next := NewModel(project).WithHistory(store)
+	defer next.Close()
+	want := []maven.Property{{Name: "skipITs", Value: "true", HasValue: true, Enabled: true}, {Name: "msg", Value: "it's done", HasValue: true}}
+	if !reflect.DeepEqual(next.properties, want) || !reflect.DeepEqual(next.options.Properties, want[:1]) {
+		t.Errorf("Expected the properties of the last session, got %+v and %v", next.properties, next.options.Properties)
+	}
+
+	// Adding a property again replaces it; D removes the highlighted one
+	m = press(m, "a", "skipITs=false", "enter", "d")
+	if len(m.properties) != 1 || m.properties[0].Name != "msg" {
+		t.Errorf("Expected only msg left, got %+v", m.properties)
+	}
+	m = press(m, "a", "two words=x", "enter")
+	if m.err == nil || m.propertyEditing < 0 {
+		t.Errorf("Expected a name with a space to be refused")
+	}
+}
+
+func TestPropertiesPanel_ReportsFailedSave(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	store, err := history.Open(dir, history.Retention{})
+	if err != nil {
+		t.Fatalf("Failed to open history: %v", err)
+	}
+	// A file where the store's directory should be makes every save fail
+	if err := os.MkdirAll(filepath.Dir(store.Dir()), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(store.Dir(), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(&maven.Project{RootPath: dir, Executable: "mvn"}).WithHistory(store)
+	defer m.Close()
+	m = press(m, "s", "a", "env=ci")
+	updated, saveCmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	updated, _ = updated.(Model).Update(saveCmd())
+	m = updated.(Model)
+	if m.err == nil || !strings.HasPrefix(m.err.Error(), "failed to save properties") {
+		t.Errorf("Expected the failed save to name the properties, got %v", m.err)
+	}
+}
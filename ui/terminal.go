@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AR0106/mvn-tui/maven"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// terminalDetachKey unfocuses the terminal pane; every other key is sent to the program
+const terminalDetachKey = "ctrl+]"
+
+// runTerminalCommand runs an interactive Maven command as a background job
+// shown in the terminal pane, so the rest of the TUI stays usable
+func (m *Model) runTerminalCommand(name string, cmd maven.Command) tea.Cmd {
+	cols, rows := m.terminalSize()
+	job, runCmd, err := m.jobs.StartTerminal(m.ctx, name, cmd, m.project.RootPath, m.logStore, cols, rows)
+	if errors.Is(err, maven.ErrTerminalUnsupported) {
+		// Run the command in place of the TUI instead
+		m.currentView = ViewLogs
+		m.updateLogViewport()
+		return m.runInteractiveMavenCommand(cmd)
+	}
+	if err != nil {
+		result := &maven.ExecutionResult{Command: cmd, ExitCode: 1, Error: err, Log: m.logStore}
+		return func() tea.Msg {
+			return executionCompleteMsg{result: result}
+		}
+	}
+
+	m.activeJob = job.ID
+	m.refreshJobsList()
+	m.openTerminal(job)
+	return m.withProgressTick(runCmd)
+}
+
+// openTerminal shows a terminal job's pane, focused if the job is still running
+func (m *Model) openTerminal(job *Job) {
+	m.terminalJob = job.ID
+	m.terminalFocused = job.Status == JobRunning
+	m.activeJob = job.ID
+	m.logStore = job.Log
+	m.logFollow = true
+	m.currentView = ViewTerminal
+}
+
+// latestTerminalJob returns the most recently started terminal job, or nil
+func (m *Model) latestTerminalJob() *Job {
+	jobs := m.jobs.Jobs()
+	for i := len(jobs) - 1; i >= 0; i-- {
+		if jobs[i].Terminal != nil {
+			return jobs[i]
+		}
+	}
+	return nil
+}
+
+// terminalSize returns the columns and rows available inside the terminal pane
+func (m *Model) terminalSize() (int, int) {
+	return max(m.width-4, 20), max(m.height-6, 5)
+}
+
+// resizeTerminals matches running terminal jobs to the pane size
+func (m *Model) resizeTerminals() {
+	cols, rows := m.terminalSize()
+	for _, job := range m.jobs.Jobs() {
+		if job.Terminal != nil && job.Status == JobRunning {
+			job.Terminal.Resize(cols, rows)
+		}
+	}
+}
+
+// handleTerminalKey handles keys in the terminal view. While focused, keys
+// go to the program, except the detach key.
+func (m *Model) handleTerminalKey(msg tea.KeyMsg) tea.Cmd {
+	job := m.jobs.Get(m.terminalJob)
+	running := job != nil && job.Status == JobRunning
+
+	if m.terminalFocused && running {
+		if msg.String() == terminalDetachKey {
+			m.terminalFocused = false
+			return nil
+		}
+		if input := keyBytes(msg); len(input) > 0 {
+			job.Terminal.Write(input)
+		}
+		return nil
+	}
+
+	switch msg.String() {
+	case "i", "enter":
+		m.terminalFocused = running
+	case "x", "ctrl+c":
+		// Stop the program, escalating like a cancelled build
+		if running && m.jobs.Cancel(job.ID) {
+			job.Log.Append("", fmt.Sprintf("Stopping: sending SIGINT, then SIGKILL after %v...", maven.DefaultGracePeriod))
+			m.refreshJobsList()
+		}
+	case "l":
+		if job != nil {
+			m.showJobLog(job)
+		}
+	case "esc", "t":
+		// Detach, leaving the program running
+		m.currentView = ViewMain
+	}
+	return nil
+}
+
+// keyBytes translates a key press into the bytes a terminal would send
+func keyBytes(msg tea.KeyMsg) []byte {
+	var seq string
+	switch msg.Type {
+	case tea.KeyRunes:
+		seq = string(msg.Runes)
+		if msg.Paste {
+			seq = "\x1b[200~" + seq + "\x1b[201~"
+		}
+	case tea.KeySpace:
+		seq = " "
+	case tea.KeyEnter:
+		seq = "\r"
+	case tea.KeyBackspace:
+		seq = "\x7f"
+	case tea.KeyTab:
+		seq = "\t"
+	case tea.KeyShiftTab:
+		seq = "\x1b[Z"
+	case tea.KeyUp:
+		seq = "\x1b[A"
+	case tea.KeyDown:
+		seq = "\x1b[B"
+	case tea.KeyRight:
+		seq = "\x1b[C"
+	case tea.KeyLeft:
+		seq = "\x1b[D"
+	case tea.KeyHome:
+		seq = "\x1b[H"
+	case tea.KeyEnd:
+		seq = "\x1b[F"
+	case tea.KeyPgUp:
+		seq = "\x1b[5~"
+	case tea.KeyPgDown:
+		seq = "\x1b[6~"
+	case tea.KeyDelete:
+		seq = "\x1b[3~"
+	case tea.KeyInsert:
+		seq = "\x1b[2~"
+	default:
+		// Control keys (ctrl+a to ctrl+_, esc) are their own control codes
+		if msg.Type >= 0 && msg.Type < 0x20 {
+			seq = string(rune(msg.Type))
+		}
+	}
+
+	if seq != "" && msg.Alt {
+		seq = "\x1b" + seq
+	}
+	return []byte(seq)
+}
+
+// renderTerminalView renders the pane of an interactive job
+func (m Model) renderTerminalView() string {
+	header := m.renderHeader()
+	cols, rows := m.terminalSize()
+
+	job := m.jobs.Get(m.terminalJob)
+	if job == nil || job.Terminal == nil {
+		return lipgloss.JoinVertical(lipgloss.Left, header, "No terminal to show. Press Esc to return to the main view.")
+	}
+	running := job.Status == JobRunning
+
+	// The newest lines, ending with the line being written, where the cursor is
+	screen := job.Terminal.Screen
+	total := screen.Len()
+	lines, err := screen.Lines(total-(rows-1), total)
+	if err != nil {
+		lines = []string{fmt.Sprintf("(Unable to read output: %v)", err)}
+	}
+	pending := job.Terminal.PendingLine()
+	if m.terminalFocused && running {
+		pending += lipgloss.NewStyle().Reverse(true).Render(" ")
+	}
+	lines = append(lines, pending)
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, cols, "")
+	}
+
+	borderColor := lipgloss.Color("240")
+	if m.terminalFocused && running {
+		borderColor = lipgloss.Color("205")
+	}
+	pane := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(borderColor).
+		Width(cols).
+		Height(rows).
+		Render(strings.Join(lines, "\n"))
+
+	var footer string
+	switch {
+	case !running:
+		footer = fmt.Sprintf("%s %s (%s) | L: Transcript | Esc: Return to main view", job.Status.Icon(), job.Name, job.Status)
+	case m.terminalFocused:
+		footer = fmt.Sprintf("⌨ Keys go to %s | Ctrl+]: Unfocus", job.Name)
+	default:
+		footer = "I or Enter: Focus | X: Stop | L: Transcript | Esc or T: Detach (keeps running)"
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, pane, lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(footer))
+}
@@ -0,0 +1,145 @@
+//go:build !windows
+
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AR0106/mvn-tui/maven"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKeyBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		key  tea.KeyMsg
+		want string
+	}{
+		{"runes", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hé")}, "hé"},
+		{"alt rune", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("b"), Alt: true}, "\x1bb"},
+		{"paste", tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a b"), Paste: true}, "\x1b[200~a b\x1b[201~"},
+		{"space", tea.KeyMsg{Type: tea.KeySpace}, " "},
+		{"enter", tea.KeyMsg{Type: tea.KeyEnter}, "\r"},
+		{"backspace", tea.KeyMsg{Type: tea.KeyBackspace}, "\x7f"},
+		{"up", tea.KeyMsg{Type: tea.KeyUp}, "\x1b[A"},
+		{"delete", tea.KeyMsg{Type: tea.KeyDelete}, "\x1b[3~"},
+		{"ctrl+c", tea.KeyMsg{Type: tea.KeyCtrlC}, "\x03"},
+		{"ctrl+d", tea.KeyMsg{Type: tea.KeyCtrlD}, "\x04"},
+		{"esc", tea.KeyMsg{Type: tea.KeyEsc}, "\x1b"},
+		{"function key", tea.KeyMsg{Type: tea.KeyF5}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(keyBytes(tt.key)); got != tt.want {
+				t.Errorf("keyBytes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// awaitMsg runs cmd, failing the test if it doesn't deliver a message in time
+func awaitMsg(t *testing.T, cmd tea.Cmd) tea.Msg {
+	t.Helper()
+	msgs := make(chan tea.Msg, 1)
+	go func() { msgs <- cmd() }()
+	select {
+	case msg := <-msgs:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the terminal job")
+		return nil
+	}
+}
+
+func TestModel_TerminalPaneForwardsKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+	fakeMvn := filepath.Join(tmpDir, "fake-mvn")
+	script := "#!/bin/sh\nprintf 'Enter your name: '\nread name\necho \"Hello, $name!\"\nsleep 30\n"
+	if err := os.WriteFile(fakeMvn, []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write fake Maven script: %v", err)
+	}
+
+	m := NewModel(&maven.Project{RootPath: tmpDir, Executable: fakeMvn})
+	defer m.Close()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updated.(Model)
+
+	m.ticking = true // Keep the returned command a plain output stream
+	cmd := m.runTerminalCommand("Run (Java)", maven.Command{Executable: fakeMvn})
+	if m.currentView != ViewTerminal || !m.terminalFocused {
+		t.Fatal("Expected the focused terminal pane to open")
+	}
+	job := m.jobs.Get(m.terminalJob)
+
+	// deliver feeds the job's messages through Update until cond holds
+	deliver := func(what string, cond func() bool) {
+		t.Helper()
+		for !cond() {
+			if cmd == nil {
+				t.Fatalf("Job finished before %s", what)
+			}
+			msg := awaitMsg(t, cmd)
+			updated, cmd = m.Update(msg)
+			m = updated.(Model)
+			if _, ok := msg.(executionCompleteMsg); ok {
+				cmd = nil
+			}
+		}
+	}
+
+	deliver("the prompt", func() bool { return strings.Contains(m.View(), "Enter your name:") })
+
+	// Typed keys go to the program, including ones bound elsewhere in the TUI
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune("q")},
+		{Type: tea.KeyBackspace},
+		{Type: tea.KeyRunes, Runes: []rune("Ada")},
+		{Type: tea.KeyEnter},
+	} {
+		updated, _ = m.Update(key)
+		m = updated.(Model)
+	}
+	deliver("the greeting", func() bool { return strings.Contains(m.View(), "Hello, Ada!") })
+
+	// Unfocusing and detaching leaves the program running
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlCloseBracket})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.currentView != ViewMain || !m.jobs.IsRunning(job.ID) {
+		t.Fatal("Expected Esc to detach while the program keeps running")
+	}
+
+	// T brings the pane back and X stops the program
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = updated.(Model)
+	if m.currentView != ViewTerminal || m.terminalJob != job.ID {
+		t.Fatal("Expected T to reopen the terminal pane")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlCloseBracket})
+	m = updated.(Model)
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m = updated.(Model)
+	deliver("the program to stop", func() bool { return cmd == nil })
+
+	if job.Status != JobCancelled {
+		t.Errorf("Expected cancelled job, got %v", job.Status)
+	}
+	if m.currentView != ViewTerminal {
+		t.Error("Expected the finished program's pane to stay open")
+	}
+	if !logContains(job.Log, "Enter your name: Ada") {
+		t.Error("Expected the transcript to record the typed input")
+	}
+}
+
+// logContains reports whether any line of log contains s
+func logContains(log *maven.LogStore, s string) bool {
+	lines, _ := log.All()
+	return strings.Contains(strings.Join(lines, "\n"), s)
+}
@@ -2,8 +2,11 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/AR0106/mvn-tui/maven"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -39,6 +42,7 @@ func (m Model) renderMainView() string {
 // renderOptionsPane renders the options and profiles pane
 func (m Model) renderOptionsPane() string {
 	var sb strings.Builder
+	dim := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
 
 	// Show project info
 	sb.WriteString("Project Info:\n\n")
@@ -52,11 +56,28 @@ func (m Model) renderOptionsPane() string {
 		sb.WriteString("  (none detected)\n")
 	} else {
 		for i, profile := range m.project.Profiles {
+			// Enabled with -P, active on its own, disabled with -P !id, or off
 			checkbox := "[ ]"
-			if profile.Enabled {
+			switch {
+			case profile.Enabled:
 				checkbox = "[✓]"
+			case profile.Disabled:
+				checkbox = "[✗]"
+			case profile.AutoActive:
+				checkbox = "[A]"
+			}
+			cursor := "  "
+			if m.focusedPane == 2 && i == m.profileCursor {
+				cursor = "› "
+			}
+			sb.WriteString(fmt.Sprintf("%s%s %d. %s", cursor, checkbox, i+1, profile.ID))
+			if profile.Reason != "" {
+				sb.WriteString(dim.Render(" (" + profile.Reason + ")"))
+			}
+			sb.WriteString("\n")
+			if len(profile.Sources) > 0 {
+				sb.WriteString(dim.Render("        "+profile.Source()) + "\n")
 			}
-			sb.WriteString(fmt.Sprintf("  %s %d. %s\n", checkbox, i+1, profile.ID))
 		}
 	}
 
@@ -78,7 +99,12 @@ func (m Model) renderOptionsPane() string {
 	if m.options.UpdateSnapshots {
 		checkbox = "[✓]"
 	}
-	sb.WriteString(fmt.Sprintf("  %s 3. Update Snapshots\n", checkbox))
+	sb.WriteString(fmt.Sprintf("  %s 3. Update Snapshots", checkbox))
+	if m.options.UpdateSnapshots && m.options.Offline {
+		// BuildCommand leaves out -U, as nothing can be updated offline
+		sb.WriteString(dim.Render(" (ignored offline)"))
+	}
+	sb.WriteString("\n")
 
 	sb.WriteString("\n\nOutput Options:\n\n")
 
@@ -112,6 +138,23 @@ func (m Model) renderOptionsPane() string {
 	}
 	sb.WriteString(fmt.Sprintf("  %s 8. Batch Mode (-B)\n", checkbox))
 
+	sb.WriteString("\n\nMore Options (O):\n\n")
+	if advanced := m.advancedOptions(); len(advanced) > 0 {
+		sb.WriteString("  " + strings.Join(advanced, " ") + "\n")
+	} else {
+		sb.WriteString("  (none)\n")
+	}
+
+	sb.WriteString("\n\nSystem Properties (S):\n\n")
+	if len(m.options.Properties) == 0 {
+		sb.WriteString("  (none)\n")
+	}
+	properties := maven.EnabledProperties(m.options.Properties)
+	slices.SortFunc(properties, func(a, b maven.Property) int { return strings.Compare(a.Name, b.Name) })
+	for _, prop := range properties {
+		sb.WriteString(fmt.Sprintf("  -D%s\n", prop))
+	}
+
 	return sb.String()
 }
 
@@ -134,7 +177,8 @@ func (m Model) renderHeader() string {
 func (m Model) renderFooter() string {
 	var parts []string
 
-	if m.running {
+	running := m.jobs.RunningCount()
+	if m.jobs.IsRunning(m.activeJob) {
 		parts = append(parts, "⏳ Running... | Ctrl+C or Esc: Cancel")
 	} else if m.lastResult != nil {
 		status := "✓"
@@ -143,10 +187,21 @@ func (m Model) renderFooter() string {
 		}
 		parts = append(parts, fmt.Sprintf("%s Exit: %d Duration: %v",
 			status, m.lastResult.ExitCode, m.lastResult.Duration))
+		if m.lastResult.ResumeFrom != "" {
+			parts = append(parts, "Shift+F: Resume from "+m.lastResult.ResumeFrom)
+		}
 	}
 
-	if !m.running {
-		parts = append(parts, "Tab: Switch | Enter: Execute | 1-8: Options | R: Run | M: Module | D: Dependency | L: Logs | H: History | Q: Quit")
+	if running > 0 {
+		parts = append(parts, fmt.Sprintf("⏳ %d job(s) running", running))
+	}
+
+	if m.notice != "" {
+		parts = append(parts, m.notice)
+	}
+
+	if !m.jobs.IsRunning(m.activeJob) {
+		parts = append(parts, "Tab: Switch | Enter: Execute | C: Custom goal | X: Export | 1-8: Options | O: More options | S: Properties | R: Run | M: Module | D: Dependency | L: Logs | E: Problems | H: History | J: Jobs | T: Terminal | Q: Quit")
 	}
 
 	return lipgloss.NewStyle().
@@ -159,35 +214,199 @@ func (m Model) renderLogsView() string {
 	header := m.renderHeader()
 
 	var footer string
-	if m.running {
-		footer = "⏳ Running... | Esc or Ctrl+C: Cancel | ↑/↓: Scroll"
+	if m.jobs.IsRunning(m.activeJob) {
+		footer = "⏳ Running... | Esc or Ctrl+C: Cancel | ↑/↓ PgUp/PgDn Home/End: Scroll | S: Reactor"
 	} else {
-		footer = "Press L to return to main view | ↑/↓: Scroll"
+		footer = "Press L to return to main view | ↑/↓ PgUp/PgDn Home/End: Scroll | S: Reactor | E: Problems"
+		if m.lastResult != nil && m.lastResult.ResumeFrom != "" {
+			footer += " | Shift+F: Resume from " + m.lastResult.ResumeFrom
+		}
+	}
+
+	if total := m.logStore.Len(); total > 0 {
+		last := min(m.logOffset+m.logViewport.Height, total)
+		footer = fmt.Sprintf("Lines %d-%d of %d | %s", m.logOffset+1, last, total, footer)
+	}
+
+	// Make room for the reactor panel beside the logs
+	progress := m.reactorProgress()
+	logWidth := m.width - 4
+	if progress != nil {
+		logWidth -= reactorPanelWidth + 2
+		m.logViewport.Width = logWidth
 	}
 
 	border := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("205")).
-		Width(m.width - 4).
+		Width(logWidth).
 		Height(m.height - 6)
 
 	logs := border.Render(m.logViewport.View())
 
+	if progress != nil {
+		panel := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			Width(reactorPanelWidth).
+			Height(m.height - 6).
+			Render(renderReactorPanel(progress, m.height-6, time.Now()))
+		logs = lipgloss.JoinHorizontal(lipgloss.Top, logs, panel)
+	}
+
 	return lipgloss.JoinVertical(lipgloss.Left, header, logs, footer)
 }
 
-// renderHistoryView renders the command history view
-func (m Model) renderHistoryView() string {
+// reactorPanelWidth is the width of the reactor progress panel in the logs view
+const reactorPanelWidth = 40
+
+// reactorProgress returns the progress of the job shown in the logs view if
+// the reactor panel should be displayed, or nil
+func (m Model) reactorProgress() *maven.ReactorProgress {
+	if !m.showReactor || m.width < 2*reactorPanelWidth {
+		return nil
+	}
+	job := m.jobs.Get(m.activeJob)
+	if job == nil || job.Progress == nil || len(job.Progress.Modules) < 2 {
+		return nil
+	}
+	// Quiet builds print no progress; the panel then says so rather than
+	// listing every module as pending
+	if !job.Progress.Reported() && !quietCommand(job.Command) {
+		return nil
+	}
+	return job.Progress
+}
+
+// quietCommand reports whether cmd runs Maven with -q, which suppresses the
+// reactor progress
+func quietCommand(cmd maven.Command) bool {
+	return slices.Contains(cmd.Args, "-q") || slices.Contains(cmd.Args, "--quiet")
+}
+
+// renderReactorPanel renders per-module status, timing and the current goal
+func renderReactorPanel(progress *maven.ReactorProgress, height int, now time.Time) string {
+	var sb strings.Builder
+	if !progress.Reported() {
+		sb.WriteString(fmt.Sprintf("Reactor %d modules\n\n", len(progress.Modules)))
+		sb.WriteString("No progress: quiet mode (-q)\n")
+		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Turn off Quiet in the options\npane to follow each module"))
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("Reactor %d/%d\n\n", progress.Completed(), len(progress.Modules)))
+
+	// Keep the module being built in view when the list is taller than the panel
+	rows := max(height-2, 1)
+	start := 0
+	if len(progress.Modules) > rows {
+		current := progress.Completed()
+		for i, module := range progress.Modules {
+			if module == progress.Current() {
+				current = i
+			}
+		}
+		start = max(min(current-rows/2, len(progress.Modules)-rows), 0)
+	}
+
+	lines := 0
+	for _, module := range progress.Modules[start:] {
+		if lines >= rows {
+			break
+		}
+
+		elapsed := ""
+		if module.State != maven.ModuleStatePending && module.State != maven.ModuleStateSkipped {
+			elapsed = formatElapsed(module.Elapsed(now))
+		}
+		name := truncate(module.Name, reactorPanelWidth-len(elapsed)-4)
+		gap := max(reactorPanelWidth-lipgloss.Width(name)-len(elapsed)-3, 1)
+		sb.WriteString(fmt.Sprintf("%s %s%s%s\n", moduleStateIcon(module.State), name, strings.Repeat(" ", gap), elapsed))
+		lines++
+
+		if module.State == maven.ModuleStateBuilding && module.Goal != "" && lines < rows {
+			sb.WriteString(fmt.Sprintf("  ↳ %s\n", truncate(module.Goal, reactorPanelWidth-4)))
+			lines++
+		}
+	}
+
+	return sb.String()
+}
+
+// moduleStateIcon returns the status symbol for a module in the reactor panel
+func moduleStateIcon(state maven.ModuleState) string {
+	switch state {
+	case maven.ModuleStateBuilding:
+		return "⏳"
+	case maven.ModuleStateSucceeded:
+		return "✓"
+	case maven.ModuleStateFailed:
+		return "✗"
+	case maven.ModuleStateSkipped:
+		return "⊘"
+	default:
+		return "·"
+	}
+}
+
+// formatElapsed formats a module duration compactly, e.g. 4.5s or 1m02s
+func formatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// truncate shortens s to at most width cells, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// renderJobsView renders the background jobs view
+func (m Model) renderJobsView() string {
 	header := m.renderHeader()
-	footer := "Press H to return to main view | ↑/↓: Navigate"
+	footer := "Enter: View log | X: Kill | C: Clear finished | J or Esc: Return to main view | ↑/↓: Navigate"
 
 	border := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(lipgloss.Color("205"))
 
-	history := border.Render(m.historyList.View())
+	var content string
+	if len(m.jobs.Jobs()) == 0 {
+		content = "No jobs yet. Run a task from the main view to start one."
+	} else {
+		content = m.jobsList.View()
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, border.Render(content), footer)
+}
+
+// renderProblemsView renders the errors, warnings and failing tests of the current log
+func (m Model) renderProblemsView() string {
+	header := m.renderHeader()
+	footer := "Enter: Open in $EDITOR | E or Esc: Return to logs | ↑/↓: Navigate"
+	if m.err != nil {
+		footer = fmt.Sprintf("✗ %v | %s", m.err, footer)
+	}
+
+	border := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("205"))
+
+	var content string
+	if len(m.problemsList.Items()) == 0 {
+		content = "No problems found in the current log."
+	} else {
+		content = m.problemsList.View()
+	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, header, history, footer)
+	return lipgloss.JoinVertical(lipgloss.Left, header, border.Render(content), footer)
 }
 
 // renderProjectCreationView renders the project creation view